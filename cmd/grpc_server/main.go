@@ -4,6 +4,9 @@ import (
 	"context"
 	"github.com/t1ltxz-gxd/shortify/internal/app"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -19,9 +22,15 @@ func main() {
 		log.Fatalf("failed to init app: %s", err.Error())
 	}
 
+	// Create a context that is cancelled when the process is asked to stop,
+	// by Ctrl+C in a terminal or by SIGTERM from Docker and Kubernetes.
+	runCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+
 	// Call the Run method of the application.
-	// This method starts the application and returns any error that might occur during the execution.
-	err = a.Run()
+	// This method serves until the process is asked to stop, then shuts the application down gracefully
+	// and returns any error that might occur during the execution.
+	err = a.Run(runCtx)
+	stop()
 
 	// If an error occurred during the execution of the application, log the error and terminate the program.
 	if err != nil {
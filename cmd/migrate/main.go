@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	// reviving the pq driver
+	_ "github.com/lib/pq"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/database/migrator"
+	pgURL "github.com/t1ltxz-gxd/shortify/internal/database/postgres/url"
+	sqliteURL "github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/migrations"
+	"log"
+	"os"
+)
+
+// usage is the help text printed for invalid arguments.
+const usage = `Usage: migrate [-target VERSION] up|down|pending
+
+  up       apply the pending migrations up to VERSION, or all of them if -target is not set
+  down     revert the applied migrations newer than VERSION, -target is required
+  pending  list the migrations that are not applied yet
+`
+
+func main() {
+	// Parse the target version and the direction from the command line.
+	target := flag.Int("target", migrator.Latest, "the version to migrate to")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load the configuration and the environment variables the same way the server does.
+	cfg, err := config.LoadConfig("config", "config", "yml")
+	if err != nil {
+		log.Fatalf("failed to load config: %s", err.Error())
+	}
+	err = logger.Init(cfg.Env, config.NewLoggerOptions(cfg))
+	if err != nil {
+		log.Fatalf("failed to init logger: %s", err.Error())
+	}
+
+	ctx := context.Background()
+
+	// Connect to the database of the configured storage driver and load its migrations.
+	var driverName, dsn, dialect string
+	switch driver := cfg.Storage.Driver; driver {
+	case "postgres":
+		driverName, dsn, dialect = "postgres", pgURL.DSN(cfg.Postgres), migrations.Postgres
+	case "sqlite":
+		driverName, dsn, dialect = "sqlite", sqliteURL.DSN(cfg.Storage.SQLite.Path), migrations.SQLite
+	default:
+		log.Fatalf("storage driver %q has no migrations", driver)
+	}
+	db, err := sqlx.ConnectContext(ctx, driverName, dsn)
+	if err != nil {
+		log.Fatalf("failed to connect to database: %s", err.Error())
+	}
+	defer db.Close()
+
+	m, err := migrator.New(db, migrations.Source(cfg.Migrations.Dir, dialect))
+	if err != nil {
+		log.Fatalf("failed to load migrations: %s", err.Error())
+	}
+
+	// Migrate in the requested direction.
+	switch flag.Arg(0) {
+	case "up":
+		err = m.Up(ctx, *target)
+	case "down":
+		if *target < 0 {
+			log.Fatal("down requires -target, use -target 0 to revert every migration")
+		}
+		err = m.Down(ctx, *target)
+	case "pending":
+		var pending []migrator.Migration
+		pending, err = m.Pending(ctx)
+		for _, migration := range pending {
+			fmt.Printf("%03d_%s\n", migration.Version, migration.Name)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("failed to migrate: %s", err.Error())
+	}
+}
@@ -0,0 +1,217 @@
+package admin_test
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/api/admin"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// MockAdminService is a struct that mocks the AdminService interface for testing.
+// It embeds the mock.Mock struct from the testify/mock package.
+type MockAdminService struct {
+	mock.Mock
+}
+
+// PurgeCache is a method that mocks the PurgeCache method of the AdminService interface.
+// It takes a context, the hash and the prefix as parameters.
+// It returns the number of purged URLs and an error, which are the return values of the Called method of the mock.Mock struct.
+func (m *MockAdminService) PurgeCache(ctx context.Context, hash, prefix string) (int64, error) {
+	args := m.Called(ctx, hash, prefix)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// SetLinkDisabled is a method that mocks the SetLinkDisabled method of the AdminService interface.
+// It takes a context, the hash and whether to disable the URL as parameters.
+// It returns an error, which is the return value of the Called method of the mock.Mock struct.
+func (m *MockAdminService) SetLinkDisabled(ctx context.Context, hash string, disabled bool) error {
+	args := m.Called(ctx, hash, disabled)
+	return args.Error(0)
+}
+
+// Stats is a method that mocks the Stats method of the AdminService interface.
+// It takes a context as a parameter.
+// It returns the statistics, which are the return value of the Called method of the mock.Mock struct.
+func (m *MockAdminService) Stats(ctx context.Context) *models.Stats {
+	args := m.Called(ctx)
+	return args.Get(0).(*models.Stats)
+}
+
+// Config is a method that mocks the Config method of the AdminService interface.
+// It takes a context as a parameter.
+// It returns the settings, which are the return value of the Called method of the mock.Mock struct.
+func (m *MockAdminService) Config(ctx context.Context) map[string]any {
+	args := m.Called(ctx)
+	return args.Get(0).(map[string]any)
+}
+
+// LogLevel is a method that mocks the LogLevel method of the AdminService interface.
+// It takes a context as a parameter.
+// It returns the name of the level, which is the return value of the Called method of the mock.Mock struct.
+func (m *MockAdminService) LogLevel(ctx context.Context) string {
+	args := m.Called(ctx)
+	return args.String(0)
+}
+
+// SetLogLevel is a method that mocks the SetLogLevel method of the AdminService interface.
+// It takes a context and the name of the level as parameters.
+// It returns the previous level and an error, which are the return values of the Called method of the mock.Mock struct.
+func (m *MockAdminService) SetLogLevel(ctx context.Context, level string) (string, error) {
+	args := m.Called(ctx, level)
+	return args.String(0), args.Error(1)
+}
+
+// TestPurgeCache_Prefix is a test function that tests purging the cached URLs by a prefix.
+// It creates a new MockAdminService that expects the PurgeCache method to be called with the prefix of the request.
+// It calls the PurgeCache method of the Implementation and checks that the response holds the number of purged URLs.
+// It checks if the expectations of the MockAdminService were met.
+func TestPurgeCache_Prefix(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("PurgeCache", mock.Anything, "", "ab").Return(int64(3), nil)
+
+	impl := admin.NewImplementation(mockService)
+	req := &desc.PurgeCacheRequest{Target: &desc.PurgeCacheRequest_Prefix{Prefix: "ab"}}
+
+	resp, err := impl.PurgeCache(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Purged)
+	mockService.AssertExpectations(t)
+}
+
+// TestPurgeCache_Error is a test function that tests a purge the service rejects.
+// It creates a new MockAdminService whose PurgeCache method returns models.ErrorInvalidPurge.
+// It calls the PurgeCache method of the Implementation with an empty request and checks that the response is nil and the error is returned.
+// It checks if the expectations of the MockAdminService were met.
+func TestPurgeCache_Error(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("PurgeCache", mock.Anything, "", "").Return(int64(0), models.ErrorInvalidPurge)
+
+	impl := admin.NewImplementation(mockService)
+
+	resp, err := impl.PurgeCache(context.Background(), &desc.PurgeCacheRequest{})
+
+	assert.ErrorIs(t, err, models.ErrorInvalidPurge)
+	assert.Nil(t, resp)
+	mockService.AssertExpectations(t)
+}
+
+// TestDisableLink is a test function that tests disabling and enabling a URL.
+// It creates a new MockAdminService that expects the SetLinkDisabled method to be called with true, then with false.
+// It calls the DisableLink and EnableLink methods of the Implementation and checks that neither returns an error.
+// It checks if the expectations of the MockAdminService were met.
+func TestDisableLink(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("SetLinkDisabled", mock.Anything, "validHash", true).Return(nil).Once()
+	mockService.On("SetLinkDisabled", mock.Anything, "validHash", false).Return(nil).Once()
+
+	impl := admin.NewImplementation(mockService)
+	req := &desc.LinkRequest{Hash: "validHash"}
+
+	_, err := impl.DisableLink(context.Background(), req)
+	require.NoError(t, err)
+	_, err = impl.EnableLink(context.Background(), req)
+	require.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+// TestDisableLink_NotFound is a test function that tests disabling a URL that does not exist.
+// It creates a new MockAdminService whose SetLinkDisabled method returns models.ErrorURLNotFound.
+// It calls the DisableLink method of the Implementation and checks that the response is nil and the error is returned.
+// It checks if the expectations of the MockAdminService were met.
+func TestDisableLink_NotFound(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("SetLinkDisabled", mock.Anything, "missingHash", true).Return(models.ErrorURLNotFound)
+
+	impl := admin.NewImplementation(mockService)
+
+	resp, err := impl.DisableLink(context.Background(), &desc.LinkRequest{Hash: "missingHash"})
+
+	assert.ErrorIs(t, err, models.ErrorURLNotFound)
+	assert.Nil(t, resp)
+	mockService.AssertExpectations(t)
+}
+
+// TestGetStats is a test function that tests the conversion of the statistics of the service.
+// It creates a new MockAdminService that returns the statistics of an instance started a minute ago.
+// It calls the GetStats method of the Implementation and checks the fields of the response.
+func TestGetStats(t *testing.T) {
+	startedAt := time.Now().Add(-time.Minute)
+	mockService := new(MockAdminService)
+	mockService.On("Stats", mock.Anything).Return(&models.Stats{
+		StartedAt:  startedAt,
+		GoVersion:  "go1.22.0",
+		Goroutines: 12,
+		HeapBytes:  1024,
+		GCCycles:   3,
+		Ready:      true,
+		CacheState: "closed",
+	})
+
+	impl := admin.NewImplementation(mockService)
+
+	resp, err := impl.GetStats(context.Background(), &emptypb.Empty{})
+
+	require.NoError(t, err)
+	assert.True(t, resp.StartedAt.AsTime().Equal(startedAt))
+	assert.GreaterOrEqual(t, resp.Uptime.AsDuration(), time.Minute)
+	assert.Equal(t, "go1.22.0", resp.GoVersion)
+	assert.Equal(t, int64(12), resp.Goroutines)
+	assert.Equal(t, uint64(1024), resp.HeapBytes)
+	assert.Equal(t, uint32(3), resp.GcCycles)
+	assert.True(t, resp.Ready)
+	assert.Equal(t, "closed", resp.CacheState)
+}
+
+// TestGetConfig is a test function that tests the conversion of the settings of the service.
+// It creates a new MockAdminService that returns nested settings.
+// It calls the GetConfig method of the Implementation and checks that the sections and the values are kept.
+func TestGetConfig(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("Config", mock.Anything).Return(map[string]any{
+		"ports":    map[string]any{"grpc": 50051},
+		"database": map[string]any{"driver": "sqlite", "password": "REDACTED"},
+	})
+
+	impl := admin.NewImplementation(mockService)
+
+	resp, err := impl.GetConfig(context.Background(), &emptypb.Empty{})
+
+	require.NoError(t, err)
+	config := resp.Config.AsMap()
+	assert.Equal(t, float64(50051), config["ports"].(map[string]any)["grpc"])
+	assert.Equal(t, "REDACTED", config["database"].(map[string]any)["password"])
+}
+
+// TestLogLevel is a test function that tests reading and changing the log level.
+// It creates a new MockAdminService that reports the info level, accepts a change to debug, and rejects an unknown level.
+// It calls the GetLogLevel and SetLogLevel methods of the Implementation and checks the responses and the error.
+// It checks if the expectations of the MockAdminService were met.
+func TestLogLevel(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("LogLevel", mock.Anything).Return("info")
+	mockService.On("SetLogLevel", mock.Anything, "debug").Return("info", nil)
+	mockService.On("SetLogLevel", mock.Anything, "loud").Return("", models.ErrorInvalidLogLevel)
+
+	impl := admin.NewImplementation(mockService)
+
+	resp, err := impl.GetLogLevel(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, "info", resp.Level)
+
+	resp, err = impl.SetLogLevel(context.Background(), &desc.LogLevel{Level: "debug"})
+	require.NoError(t, err)
+	assert.Equal(t, "info", resp.Level, "the response holds the previous level")
+
+	resp, err = impl.SetLogLevel(context.Background(), &desc.LogLevel{Level: "loud"})
+	assert.ErrorIs(t, err, models.ErrorInvalidLogLevel)
+	assert.Nil(t, resp)
+	mockService.AssertExpectations(t)
+}
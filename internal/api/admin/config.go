@@ -0,0 +1,17 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/converter"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetConfig is a method on the Implementation struct.
+// It takes a context and an empty request as parameters.
+// This method calls the Config method on the adminService, passing the context.
+// It returns a ConfigResponse containing the settings, converted with the ToConfigFromService function
+// from the converter package, or nil and the error if the settings cannot be converted.
+func (i *Implementation) GetConfig(ctx context.Context, _ *emptypb.Empty) (*desc.ConfigResponse, error) {
+	return converter.ToConfigFromService(i.adminService.Config(ctx))
+}
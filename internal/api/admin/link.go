@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// DisableLink is a method on the Implementation struct.
+// It takes a context and a LinkRequest as parameters.
+// The LinkRequest contains the hash of the URL to disable.
+// This method calls the SetLinkDisabled method on the adminService, passing the context, the hash from the request and true.
+// If the SetLinkDisabled method on the adminService returns an error, the DisableLink method returns nil and the error.
+// Otherwise it returns an empty response and nil error.
+func (i *Implementation) DisableLink(ctx context.Context, req *desc.LinkRequest) (_ *emptypb.Empty, err error) {
+	ctx, span := tracing.Start(ctx, "api.DisableLink", tracing.HashKey.String(req.GetHash()))
+	defer func() { tracing.End(span, err) }()
+
+	err = i.adminService.SetLinkDisabled(ctx, req.GetHash(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// EnableLink is a method on the Implementation struct.
+// It takes a context and a LinkRequest as parameters.
+// The LinkRequest contains the hash of the URL to enable.
+// This method calls the SetLinkDisabled method on the adminService, passing the context, the hash from the request and false.
+// If the SetLinkDisabled method on the adminService returns an error, the EnableLink method returns nil and the error.
+// Otherwise it returns an empty response and nil error.
+func (i *Implementation) EnableLink(ctx context.Context, req *desc.LinkRequest) (_ *emptypb.Empty, err error) {
+	ctx, span := tracing.Start(ctx, "api.EnableLink", tracing.HashKey.String(req.GetHash()))
+	defer func() { tracing.End(span, err) }()
+
+	err = i.adminService.SetLinkDisabled(ctx, req.GetHash(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"context"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetLogLevel is a method on the Implementation struct.
+// It takes a context and an empty request as parameters.
+// This method calls the LogLevel method on the adminService, passing the context.
+// It returns a LogLevel containing the lowest level the instance logs, and nil error.
+func (i *Implementation) GetLogLevel(ctx context.Context, _ *emptypb.Empty) (*desc.LogLevel, error) {
+	return &desc.LogLevel{
+		Level: i.adminService.LogLevel(ctx),
+	}, nil
+}
+
+// SetLogLevel is a method on the Implementation struct.
+// It takes a context and a LogLevel as parameters.
+// The LogLevel contains the lowest level the instance logs from now on.
+// This method calls the SetLogLevel method on the adminService, passing the context and the level from the request.
+// If the SetLogLevel method on the adminService returns an error, the SetLogLevel method returns nil and the error.
+// Otherwise it returns a LogLevel containing the level the instance logged before.
+func (i *Implementation) SetLogLevel(ctx context.Context, req *desc.LogLevel) (*desc.LogLevel, error) {
+	previous, err := i.adminService.SetLogLevel(ctx, req.GetLevel())
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.LogLevel{
+		Level: previous,
+	}, nil
+}
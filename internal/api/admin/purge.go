@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+)
+
+// PurgeCache is a method on the Implementation struct.
+// It takes a context and a PurgeCacheRequest as parameters.
+// The PurgeCacheRequest contains either the hash of a URL or a prefix of the hashes.
+// This method calls the PurgeCache method on the adminService, passing the context, the hash and the prefix from the request.
+// If the PurgeCache method on the adminService returns an error, the PurgeCache method returns nil and the error.
+// Otherwise it returns a PurgeCacheResponse containing the number of URLs evicted by a prefix.
+func (i *Implementation) PurgeCache(ctx context.Context, req *desc.PurgeCacheRequest) (_ *desc.PurgeCacheResponse, err error) {
+	ctx, span := tracing.Start(ctx, "api.PurgeCache")
+	defer func() { tracing.End(span, err) }()
+
+	purged, err := i.adminService.PurgeCache(ctx, req.GetHash(), req.GetPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.PurgeCacheResponse{
+		Purged: purged,
+	}, nil
+}
@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"github.com/t1ltxz-gxd/shortify/internal/service"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+)
+
+// Implementation is a struct that embeds the UnimplementedAdminV1Server interface from the admin_v1 package
+// and includes an AdminService from the internal service package.
+// This struct is used to implement the methods defined in the UnimplementedAdminV1Server interface.
+type Implementation struct {
+	desc.UnimplementedAdminV1Server                      // Embedding the UnimplementedAdminV1Server interface
+	adminService                    service.AdminService // AdminService from the internal service package
+}
+
+// NewImplementation is a function that creates a new Implementation struct.
+// It takes an AdminService as a parameter and returns a pointer to an Implementation struct.
+// The AdminService is assigned to the adminService field of the Implementation struct.
+func NewImplementation(adminService service.AdminService) *Implementation {
+	return &Implementation{
+		adminService: adminService, // Assigning the AdminService to the adminService field of the Implementation struct
+	}
+}
@@ -0,0 +1,17 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/converter"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetStats is a method on the Implementation struct.
+// It takes a context and an empty request as parameters.
+// This method calls the Stats method on the adminService, passing the context.
+// It returns a StatsResponse containing the statistics, converted with the ToStatsFromService function
+// from the converter package, and nil error.
+func (i *Implementation) GetStats(ctx context.Context, _ *emptypb.Empty) (*desc.StatsResponse, error) {
+	return converter.ToStatsFromService(i.adminService.Stats(ctx)), nil
+}
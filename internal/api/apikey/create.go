@@ -0,0 +1,30 @@
+package apikey
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/converter"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/apikey_v1"
+)
+
+// Create is a method on the Implementation struct.
+// It takes a context and a CreateRequest as parameters.
+// The CreateRequest contains the name of the holder of the new key.
+// This method calls the Create method on the apiKeyService, passing the context and the name from the request.
+// If the Create method on the apiKeyService returns an error, the Create method returns nil and the error.
+// Otherwise it returns a CreateResponse containing the key and its description, converted with the ToAPIKeyFromService
+// function from the converter package.
+func (i *Implementation) Create(ctx context.Context, req *desc.CreateRequest) (_ *desc.CreateResponse, err error) {
+	ctx, span := tracing.Start(ctx, "api.CreateAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	key, apiKey, err := i.apiKeyService.Create(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.CreateResponse{
+		Key:    key,
+		ApiKey: converter.ToAPIKeyFromService(apiKey),
+	}, nil
+}
@@ -0,0 +1,33 @@
+package apikey
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/converter"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/apikey_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// List is a method on the Implementation struct.
+// It takes a context and an empty request as parameters.
+// This method calls the List method on the apiKeyService, passing the context.
+// If the List method on the apiKeyService returns an error, the List method returns nil and the error.
+// Otherwise it returns a ListResponse containing the keys, converted with the ToAPIKeyFromService function
+// from the converter package.
+func (i *Implementation) List(ctx context.Context, _ *emptypb.Empty) (_ *desc.ListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "api.ListAPIKeys")
+	defer func() { tracing.End(span, err) }()
+
+	keys, err := i.apiKeyService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &desc.ListResponse{
+		ApiKeys: make([]*desc.ApiKey, 0, len(keys)),
+	}
+	for _, key := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, converter.ToAPIKeyFromService(key))
+	}
+	return resp, nil
+}
@@ -0,0 +1,26 @@
+package apikey
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/apikey_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Revoke is a method on the Implementation struct.
+// It takes a context and a RevokeRequest as parameters.
+// The RevokeRequest contains the ID of the key to revoke.
+// This method calls the Revoke method on the apiKeyService, passing the context and the ID from the request.
+// If the Revoke method on the apiKeyService returns an error, the Revoke method returns nil and the error.
+// Otherwise it returns an empty response and nil error.
+func (i *Implementation) Revoke(ctx context.Context, req *desc.RevokeRequest) (_ *emptypb.Empty, err error) {
+	ctx, span := tracing.Start(ctx, "api.RevokeAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	err = i.apiKeyService.Revoke(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
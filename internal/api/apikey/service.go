@@ -0,0 +1,23 @@
+package apikey
+
+import (
+	"github.com/t1ltxz-gxd/shortify/internal/service"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/apikey_v1"
+)
+
+// Implementation is a struct that embeds the UnimplementedApiKeyV1Server interface from the apikey_v1 package
+// and includes an APIKeyService from the internal service package.
+// This struct is used to implement the methods defined in the UnimplementedApiKeyV1Server interface.
+type Implementation struct {
+	desc.UnimplementedApiKeyV1Server                       // Embedding the UnimplementedApiKeyV1Server interface
+	apiKeyService                    service.APIKeyService // APIKeyService from the internal service package
+}
+
+// NewImplementation is a function that creates a new Implementation struct.
+// It takes an APIKeyService as a parameter and returns a pointer to an Implementation struct.
+// The APIKeyService is assigned to the apiKeyService field of the Implementation struct.
+func NewImplementation(apiKeyService service.APIKeyService) *Implementation {
+	return &Implementation{
+		apiKeyService: apiKeyService, // Assigning the APIKeyService to the apiKeyService field of the Implementation struct
+	}
+}
@@ -3,6 +3,7 @@ package url
 import (
 	"context"
 	"github.com/t1ltxz-gxd/shortify/internal/converter"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
 	desc "github.com/t1ltxz-gxd/shortify/pkg/url_v1"
 )
 
@@ -15,10 +16,14 @@ import (
 // The URL from the request is converted from a descriptor URL to a service URL using the ToURLFromDesc function from the converter package.
 // If the Create method on the urlService returns an error, the Create method returns nil and the error.
 // If the Create method on the urlService does not return an error, the Create method returns a CreateResponse containing the shortened URL and nil error.
-func (i *Implementation) Create(ctx context.Context, req *desc.CreateRequest) (*desc.CreateResponse, error) {
+func (i *Implementation) Create(ctx context.Context, req *desc.CreateRequest) (_ *desc.CreateResponse, err error) {
+	ctx, span := tracing.Start(ctx, "api.Create")
+	defer func() { tracing.End(span, err) }()
+
 	// Call the Create method on the urlService, passing the context and the URL from the request.
 	// The URL from the request is converted from a descriptor URL to a service URL using the ToURLFromDesc function from the converter package.
-	shortURL, err := i.urlService.Create(ctx, converter.ToURLFromDesc(req.Url))
+	// The TTL from the request is zero if it is not set, which selects the default lifetime.
+	shortURL, err := i.urlService.Create(ctx, converter.ToURLFromDesc(req.Url), req.GetTtl().AsDuration())
 	// If the Create method on the urlService returns an error, return nil and the error.
 	if err != nil {
 		return nil, err
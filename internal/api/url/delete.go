@@ -0,0 +1,28 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/url_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Delete is a method on the Implementation struct.
+// It takes a context and a DeleteRequest as parameters.
+// The DeleteRequest contains the hash of the URL to be removed.
+// This method calls the Delete method on the urlService, passing the context and the hash from the request.
+// If the Delete method on the urlService returns an error, the Delete method returns nil and the error.
+// If the Delete method on the urlService does not return an error, the Delete method returns an empty response and nil error.
+func (i *Implementation) Delete(ctx context.Context, req *desc.DeleteRequest) (_ *emptypb.Empty, err error) {
+	ctx, span := tracing.Start(ctx, "api.Delete", tracing.HashKey.String(req.GetHash()))
+	defer func() { tracing.End(span, err) }()
+
+	// Call the Delete method on the urlService, passing the context and the hash from the request.
+	err = i.urlService.Delete(ctx, req.Hash)
+	// If the Delete method on the urlService returns an error, return nil and the error.
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
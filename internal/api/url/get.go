@@ -3,6 +3,7 @@ package url
 import (
 	"context"
 	"github.com/t1ltxz-gxd/shortify/internal/converter"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
 	desc "github.com/t1ltxz-gxd/shortify/pkg/url_v1"
 )
 
@@ -14,7 +15,10 @@ import (
 // If the Get method on the urlService does not return an error, the Get method returns a GetResponse containing the original URL and nil error.
 // The URL returned by the urlService is converted from a service URL to a descriptor URL using the ToURLFromService function from the converter package.
 // The original URL from the descriptor URL is then retrieved using the GetOriginalUrl method.
-func (i *Implementation) Get(ctx context.Context, req *desc.GetRequest) (*desc.GetResponse, error) {
+func (i *Implementation) Get(ctx context.Context, req *desc.GetRequest) (_ *desc.GetResponse, err error) {
+	ctx, span := tracing.Start(ctx, "api.Get", tracing.HashKey.String(req.GetHash()))
+	defer func() { tracing.End(span, err) }()
+
 	// Call the Get method on the urlService, passing the context and the hash from the request.
 	url, err := i.urlService.Get(ctx, req.Hash)
 	// If the Get method on the urlService returns an error, return nil and the error.
@@ -0,0 +1,36 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/converter"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/url_v1"
+)
+
+// Search is a method on the Implementation struct.
+// It takes a context and a SearchRequest as parameters.
+// The SearchRequest contains the query, the page size and the page token of a previous search.
+// This method calls the Search method on the urlService, passing the context and the fields of the request.
+// If the Search method on the urlService returns an error, the Search method returns nil and the error.
+// Otherwise it returns a SearchResponse containing the URLs, converted with the ToURLFromService function
+// from the converter package, and the token of the next page.
+func (i *Implementation) Search(ctx context.Context, req *desc.SearchRequest) (_ *desc.SearchResponse, err error) {
+	ctx, span := tracing.Start(ctx, "api.Search")
+	defer func() { tracing.End(span, err) }()
+
+	// Call the Search method on the urlService, passing the context and the fields of the request.
+	urls, next, err := i.urlService.Search(ctx, req.GetQuery(), int(req.GetPageSize()), req.GetPageToken())
+	// If the Search method on the urlService returns an error, return nil and the error.
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &desc.SearchResponse{
+		Urls:          make([]*desc.Url, 0, len(urls)),
+		NextPageToken: next,
+	}
+	for _, url := range urls {
+		resp.Urls = append(resp.Urls, converter.ToURLFromService(url))
+	}
+	return resp, nil
+}
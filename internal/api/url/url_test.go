@@ -5,11 +5,13 @@ import (
 	"errors"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/t1ltxz-gxd/shortify/internal/api/url"
 	desc "github.com/t1ltxz-gxd/shortify/pkg/url_v1"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 // MockURLService is a struct that mocks the URLService interface for testing.
@@ -22,10 +24,11 @@ type MockURLService struct {
 // It takes a context and a URL string as parameters.
 // The context is used for request-scoped data, cancellation signals, and deadlines.
 // The URL string is the original URL.
+// The TTL is how long the short URL resolves.
 // It returns a hash string that represents the hashed version of the URL and an error.
 // The hash string and the error are the return values of the Called method of the mock.Mock struct.
-func (m *MockURLService) Create(ctx context.Context, url string) (string, error) {
-	args := m.Called(ctx, url)
+func (m *MockURLService) Create(ctx context.Context, url string, ttl time.Duration) (string, error) {
+	args := m.Called(ctx, url, ttl)
 	return args.String(0), args.Error(1)
 }
 
@@ -43,6 +46,26 @@ func (m *MockURLService) Get(ctx context.Context, hash string) (*models.URL, err
 	return args.Get(0).(*models.URL), args.Error(1)
 }
 
+// Delete is a method that mocks the Delete method of the URLService interface.
+// It takes a context and a hash string as parameters.
+// It returns an error, which is the return value of the Called method of the mock.Mock struct.
+func (m *MockURLService) Delete(ctx context.Context, hash string) error {
+	args := m.Called(ctx, hash)
+	return args.Error(0)
+}
+
+// Search is a method that mocks the Search method of the URLService interface.
+// It takes a context, the query, the page size and the page token as parameters.
+// It returns the URLs, the token of the next page and an error,
+// which are the return values of the Called method of the mock.Mock struct.
+func (m *MockURLService) Search(ctx context.Context, query string, pageSize int, pageToken string) ([]*models.URL, string, error) {
+	args := m.Called(ctx, query, pageSize, pageToken)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.URL), args.String(1), args.Error(2)
+}
+
 // TestGet_Success is a test function that tests the successful retrieval of a URL from the service.
 // It creates a new MockURLService and sets the expected return value of the Get method to a URL model and nil.
 // It creates a new Implementation with the MockURLService and a GetRequest with a valid hash.
@@ -88,7 +111,7 @@ func TestGet_Error(t *testing.T) {
 // It checks if the expectations of the MockURLService were met.
 func TestCreate_Success(t *testing.T) {
 	mockService := new(MockURLService)
-	mockService.On("Create", mock.Anything, "https://example.com").Return("hash123", nil)
+	mockService.On("Create", mock.Anything, "https://example.com", time.Duration(0)).Return("hash123", nil)
 
 	impl := url.NewImplementation(mockService)
 	req := &desc.CreateRequest{Url: "https://example.com"}
@@ -107,7 +130,7 @@ func TestCreate_Success(t *testing.T) {
 // It checks if the expectations of the MockURLService were met.
 func TestCreate_Error(t *testing.T) {
 	mockService := new(MockURLService)
-	mockService.On("Create", mock.Anything, "https://invalid.com").Return("", errors.New("error"))
+	mockService.On("Create", mock.Anything, "https://invalid.com", time.Duration(0)).Return("", errors.New("error"))
 
 	impl := url.NewImplementation(mockService)
 	req := &desc.CreateRequest{Url: "https://invalid.com"}
@@ -119,6 +142,101 @@ func TestCreate_Error(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// TestCreate_TTL is a test function that tests that the TTL of a CreateRequest is passed to the service.
+// It creates a new MockURLService that expects the Create method to be called with the TTL of the request.
+// It calls the Create method of the Implementation with a CreateRequest with a TTL of one hour and checks that the error is nil.
+// It checks if the expectations of the MockURLService were met.
+func TestCreate_TTL(t *testing.T) {
+	mockService := new(MockURLService)
+	mockService.On("Create", mock.Anything, "https://example.com", time.Hour).Return("hash123", nil)
+
+	impl := url.NewImplementation(mockService)
+	req := &desc.CreateRequest{Url: "https://example.com", Ttl: durationpb.New(time.Hour)}
+
+	_, err := impl.Create(context.Background(), req)
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+// TestDelete_Success is a test function that tests the successful removal of a URL from the service.
+// It creates a new MockURLService and sets the expected return value of the Delete method to nil.
+// It calls the Delete method of the Implementation with a DeleteRequest and checks that the response is not nil and the error is nil.
+// It checks if the expectations of the MockURLService were met.
+func TestDelete_Success(t *testing.T) {
+	mockService := new(MockURLService)
+	mockService.On("Delete", mock.Anything, "validHash").Return(nil)
+
+	impl := url.NewImplementation(mockService)
+	req := &desc.DeleteRequest{Hash: "validHash"}
+
+	resp, err := impl.Delete(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	mockService.AssertExpectations(t)
+}
+
+// TestDelete_Error is a test function that tests the failed removal of a URL from the service.
+// It creates a new MockURLService and sets the expected return value of the Delete method to an invalid URL error.
+// It calls the Delete method of the Implementation with a DeleteRequest and checks that the response is nil and the error is returned.
+// It checks if the expectations of the MockURLService were met.
+func TestDelete_Error(t *testing.T) {
+	mockService := new(MockURLService)
+	mockService.On("Delete", mock.Anything, "invalidHash").Return(models.ErrorInvalidURL)
+
+	impl := url.NewImplementation(mockService)
+	req := &desc.DeleteRequest{Hash: "invalidHash"}
+
+	resp, err := impl.Delete(context.Background(), req)
+
+	assert.ErrorIs(t, err, models.ErrorInvalidURL)
+	assert.Nil(t, resp)
+	mockService.AssertExpectations(t)
+}
+
+// TestSearch_Success is a test function that tests the successful search of URLs in the service.
+// It creates a new MockURLService that returns two URLs and a next page token for the query of the request.
+// It calls the Search method of the Implementation and checks that the response holds the converted URLs and the token.
+// It checks if the expectations of the MockURLService were met.
+func TestSearch_Success(t *testing.T) {
+	mockService := new(MockURLService)
+	mockService.On("Search", mock.Anything, "example", 2, "").Return([]*models.URL{
+		{Hash: "abc", Original: "https://example.com"},
+		{Hash: "def", Original: "https://blog.example.com"},
+	}, "next", nil)
+
+	impl := url.NewImplementation(mockService)
+	req := &desc.SearchRequest{Query: "example", PageSize: 2}
+
+	resp, err := impl.Search(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Urls, 2)
+	assert.Equal(t, "abc", resp.Urls[0].ShortUrl)
+	assert.Equal(t, "https://blog.example.com", resp.Urls[1].OriginalUrl)
+	assert.Equal(t, "next", resp.NextPageToken)
+	mockService.AssertExpectations(t)
+}
+
+// TestSearch_Error is a test function that tests the failed search of URLs in the service.
+// It creates a new MockURLService that returns an invalid query error.
+// It calls the Search method of the Implementation and checks that the response is nil and the error is returned.
+// It checks if the expectations of the MockURLService were met.
+func TestSearch_Error(t *testing.T) {
+	mockService := new(MockURLService)
+	mockService.On("Search", mock.Anything, "ex", 0, "").Return(nil, "", models.ErrorInvalidQuery)
+
+	impl := url.NewImplementation(mockService)
+	req := &desc.SearchRequest{Query: "ex"}
+
+	resp, err := impl.Search(context.Background(), req)
+
+	assert.ErrorIs(t, err, models.ErrorInvalidQuery)
+	assert.Nil(t, resp)
+	mockService.AssertExpectations(t)
+}
+
 // BenchmarkCreate is a benchmark test for the Create method of the Implementation struct.
 // It measures the performance of the Create method by calling it B.N times in a loop.
 // B.N is automatically adjusted by the testing package to get meaningful results.
@@ -128,7 +246,7 @@ func BenchmarkCreate(b *testing.B) {
 	// Create a new MockURLService
 	mockService := new(MockURLService)
 	// Set up the Create method of the mock service to return a fixed hash and no error
-	mockService.On("Create", mock.Anything, "https://example.com").Return("hash123", nil)
+	mockService.On("Create", mock.Anything, "https://example.com", time.Duration(0)).Return("hash123", nil)
 
 	// Create an Implementation instance with the mock service
 	impl := url.NewImplementation(mockService)
@@ -2,42 +2,81 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	// reviving the pq driver
 	_ "github.com/lib/pq"
-	"github.com/spf13/viper"
+	"github.com/t1ltxz-gxd/shortify/internal/auth/bearer"
+	"github.com/t1ltxz-gxd/shortify/internal/certs"
 	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/health"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/interceptor"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	adminDesc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+	apiKeyDesc "github.com/t1ltxz-gxd/shortify/pkg/apikey_v1"
 	desc "github.com/t1ltxz-gxd/shortify/pkg/url_v1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime"
+	"sync"
+	"time"
 )
 
 // App is a struct that holds the dependencies for the application.
-// It includes a serviceProvider which provides the services for the application,
+// It includes the configuration of the application and the reloader holding its current reloadable settings, a serviceProvider which provides the services for the application,
 // a grpcServer which is the gRPC server for the application,
-// a db which is the database connection for the application,
-// and a cache which is the Redis client for the application.
+// the background work which runs until the application shuts down,
+// and a closer which releases the connections of the application when it shuts down.
 type App struct {
-	serviceProvider *serviceProvider // serviceProvider provides the services for the application
-	grpcServer      *grpc.Server     // grpcServer is the gRPC server for the application
+	cfg             *config.Config                   // cfg is the validated configuration the application started with
+	reloader        *config.Reloader                 // reloader holds the current configuration and reloads it when the file changes
+	serviceProvider *serviceProvider                 // serviceProvider provides the services for the application
+	creds           credentials.TransportCredentials // creds are the transport credentials of the gRPC servers
+	grpcServer      *grpc.Server                     // grpcServer is the gRPC server for the application
+	adminGRPCServer *grpc.Server                     // adminGRPCServer is the gRPC server for the operators, serving the admin service
+	httpServer      *http.Server                     // httpServer is the HTTP server for the health endpoints
+	adminServer     *http.Server                     // adminServer is the HTTP server for the operators, serving the metrics
+	health          health.Checker                   // health tracks the health of the dependencies of the application
+	jobs            *scheduler                       // jobs runs the background jobs
+	background      sync.WaitGroup                   // background tracks the background goroutines other than the jobs
+	stopBackground  context.CancelFunc               // stopBackground cancels the context of the background work
+	closer          *closer                          // closer releases the connections of the application
 }
 
 // NewApp is a function that creates a new App struct.
 // It takes a context as a parameter and returns a pointer to an App struct and an error.
+// The background work of the application, like the jobs and the cache invalidation, runs until the context is done
+// or the application shuts down.
 // It initializes the dependencies of the App struct by calling the initDeps method.
 // If the initDeps method returns an error, NewApp returns nil and the error.
 // If the initDeps method does not return an error, NewApp returns a pointer to the App struct and nil error.
 func NewApp(ctx context.Context) (*App, error) {
-	a := &App{} // Create a new App struct
+	a := &App{
+		jobs:   &scheduler{},
+		closer: &closer{},
+	} // Create a new App struct
+	ctx, a.stopBackground = context.WithCancel(ctx)
 
 	// Initialize the dependencies of the App struct
 	err := a.initDeps(ctx)
 	if err != nil {
+		a.stopBackground()
 		return nil, err // Return nil and the error if the initDeps method returns an error
 	}
 
@@ -45,16 +84,122 @@ func NewApp(ctx context.Context) (*App, error) {
 }
 
 // Run is a method on the App struct.
-// It starts the gRPC server by calling the runGRPCServer method and returns any error that it returns.
-func (a *App) Run() error {
-	return a.runGRPCServer() // Start the gRPC server and return any error that it returns
+// It starts the gRPC server, the HTTP server and the admin servers by calling the runGRPCServer and runHTTPServer methods
+// and serves until the context is done, which happens when the process receives SIGINT or SIGTERM, or until a server fails.
+// It then reports the application as not ready, stops the gRPC server gracefully,
+// letting the RPCs in progress finish within the drain timeout, stops the HTTP server and the admin servers,
+// which stay up the longest so the metrics of the drain can still be scraped, and shuts the application down.
+// It returns the error of the server that stopped on its own, if any.
+func (a *App) Run(ctx context.Context) error {
+	defer a.shutdown()
+
+	servers := []func() error{
+		func() error { return a.runGRPCServer("GRPC", a.grpcServer, a.serviceProvider.GRPCConfig().Address()) },
+		func() error { return a.runHTTPServer("HTTP", a.httpServer) },
+		func() error { return a.runHTTPServer("Admin", a.adminServer) },
+		func() error {
+			return a.runGRPCServer("Admin gRPC", a.adminGRPCServer, a.serviceProvider.AdminConfig().GRPCAddress())
+		},
+	}
+	errs := make(chan error, len(servers))
+	for _, run := range servers {
+		go func(run func() error) {
+			errs <- run() // Start the server and report the error that it returns
+		}(run)
+	}
+
+	var err error
+	pending := len(servers)
+	select {
+	case err = <-errs:
+		pending--
+		logger.Error("Server stopped unexpectedly, shutting down", zap.Error(err))
+	case <-ctx.Done():
+		logger.Info("Shutting down")
+	}
+
+	a.health.Shutdown()
+	a.stopGRPCServer("gRPC", a.grpcServer)
+	a.stopHTTPServer("HTTP", a.httpServer)
+	a.stopGRPCServer("Admin gRPC", a.adminGRPCServer)
+	a.stopHTTPServer("Admin", a.adminServer)
+	for ; pending > 0; pending-- {
+		if serveErr := <-errs; err == nil {
+			err = serveErr
+		}
+	}
+	return err
+}
+
+// stopGRPCServer is a method on the App struct.
+// It stops a gRPC server from accepting new connections and RPCs and waits for the RPCs in progress to finish.
+// If they are still running after shutdown.drainTimeout seconds, it cancels them and closes the connections.
+// The name of the server is used in logs.
+func (a *App) stopGRPCServer(name string, server *grpc.Server) {
+	timeout := time.Duration(a.cfg.Shutdown.DrainTimeout) * time.Second
+
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		logger.Info(name + " server stopped")
+	case <-timer.C:
+		logger.Warn("RPCs did not finish in time, cancelling them", zap.String("server", name), zap.Duration("drainTimeout", timeout))
+		server.Stop()
+		<-done
+	}
+}
+
+// stopHTTPServer is a method on the App struct.
+// It stops an HTTP server, waiting up to shutdown.drainTimeout seconds for the requests in progress.
+// The name of the server is used in logs.
+func (a *App) stopHTTPServer(name string, server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(a.cfg.Shutdown.DrainTimeout)*time.Second)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if err != nil {
+		logger.Warn(name+" server did not stop cleanly", zap.Error(err))
+		return
+	}
+	logger.Info(name + " server stopped")
+}
+
+// shutdown is a method on the App struct.
+// It releases everything the application holds, in order:
+// it cancels the background work and waits for it to return, so no job is left writing to a closed connection,
+// then closes the invalidation bus, the cache and the database, the last opened first,
+// and finally flushes the buffered logs.
+// It is safe to call more than once.
+func (a *App) shutdown() {
+	a.stopBackground()
+	a.jobs.wait()
+	a.background.Wait()
+	if a.health != nil {
+		a.health.Wait()
+	}
+
+	err := a.closer.closeAll()
+	if err != nil {
+		logger.Error("Failed to release every resource", zap.Error(err))
+	}
+
+	logger.Info("Stopped")
+	_ = logger.Sync() // Syncing a console fails on some platforms and there is nowhere left to report it
 }
 
 // initDeps is a method on the App struct.
 // It initializes the dependencies of the App struct.
 // It takes a context as a parameter and returns an error.
 // It creates a slice of functions that initialize the dependencies of the App struct.
-// These functions are initConfig, initLogger, initCache, initDatabase, initServiceProvider, and initGRPCServer.
+// These functions are initConfig, initLogger, initTracing, initServiceProvider, initGRPCServer, initHealth, initHTTPServer,
+// initAdminServer, initAdminGRPCServer, initInvalidation, initBackup, initJobs, and initReload.
 // It then iterates over the slice of functions and calls each function, passing the context as a parameter.
 // If any of the functions return an error, initDeps returns the error.
 // If none of the functions return an error, initDeps applies the database migrations by calling the applyMigration method.
@@ -65,8 +210,17 @@ func (a *App) initDeps(ctx context.Context) error {
 	inits := []func(context.Context) error{
 		a.initConfig,
 		a.initLogger,
+		a.initTracing,
 		a.initServiceProvider,
 		a.initGRPCServer,
+		a.initHealth,
+		a.initHTTPServer,
+		a.initAdminServer,
+		a.initAdminGRPCServer,
+		a.initInvalidation,
+		a.initBackup,
+		a.initJobs,
+		a.initReload,
 	}
 
 	// Iterate over the slice of functions and call each function, passing the context as a parameter
@@ -85,94 +239,471 @@ func (a *App) initDeps(ctx context.Context) error {
 // initConfig is a method on the App struct.
 // It initializes the configuration for the application.
 // It takes a context as a parameter and returns an error.
-// It calls the LoadDotEnv method from the config package, passing ".env" as the parameter.
-// This method loads the environment variables from the .env file.
-// If the LoadDotEnv method returns an error, initConfig returns the error.
-// If the LoadDotEnv method does not return an error, initConfig calls the LoadConfig method from the config package, passing "config", "config", and "yml" as the parameters.
-// This method loads the configuration from the config.yml file.
-// If the LoadConfig method returns an error, initConfig returns the error.
-// If the LoadConfig method does not return an error, initConfig returns nil.
+// It calls the LoadConfig method from the config package, passing "config", "config", and "yml" as the parameters.
+// This method loads the configuration from the config.yml file and the environment variables from the envFiles,
+// applies the defaults and the environment overrides, and validates the result.
+// If the LoadConfig method returns an error, initConfig returns the error, which lists every invalid setting.
+// Otherwise it keeps the configuration on the App struct, so the rest of the application is built from it,
+// creates the reloader that holds it while it is reloaded, and returns nil.
 func (a *App) initConfig(_ context.Context) error {
-	err := config.LoadConfig("config", "config", "yml")
-	if err != nil {
-		return err
-	}
-	err = config.LoadDotEnv(viper.GetStringSlice("envFiles")...)
+	cfg, err := config.LoadConfig("config", "config", "yml")
 	if err != nil {
 		return err
 	}
+	a.cfg = cfg
+	a.reloader = config.NewReloader(cfg)
 	return nil
 }
 
 // initLogger is a method on the App struct.
 // It initializes the logger for the application.
 // It takes a context as a parameter and returns an error.
-// It calls the Init method from the logger package, passing the environment variable "ENV" as the parameter.
-// This method initializes the logger with the specified environment.
+// It calls the Init method from the logger package, passing the environment of the configuration, set by the ENV environment variable,
+// and the options of the logger section of the configuration as the parameters.
+// This method initializes the logger with the defaults of the environment and the configured level, encoding, outputs and sampling.
+// If the Init method returns an error, initLogger returns the error.
 // After the logger is initialized, initLogger logs the system information (OS, architecture, Go version, and environment) using the Info method from the logger package.
 // It also logs that debug mode is enabled using the Debug method from the logger package.
 // initLogger then returns nil.
 func (a *App) initLogger(_ context.Context) error {
-	logger.Init(os.Getenv("ENV"))
+	err := logger.Init(a.cfg.Env, config.NewLoggerOptions(a.cfg))
+	if err != nil {
+		return err
+	}
 
 	// Recording system information after successful logger initialization
 	logger.Info("Successfully start!",
 		zap.String("OS", runtime.GOOS),
 		zap.String("Architecture", runtime.GOARCH),
 		zap.String("Go version", runtime.Version()),
-		zap.String("Environment", viper.GetString("env")))
+		zap.String("Environment", a.cfg.Env),
+		zap.String("Log level", logger.Level()))
 	logger.Debug("Debug mode enabled!")
 
 	return nil
 }
 
+// initTracing is a method on the App struct.
+// It initializes the tracing of the application with the exporter from the tracing configuration.
+// It takes a context as a parameter and returns an error.
+// The exporter is registered with the closer first, so it is closed last and the spans of the shutdown are exported too.
+// If the exporter cannot be created, initTracing returns the error.
+// It logs the exporter in use and then returns nil.
+func (a *App) initTracing(ctx context.Context) error {
+	shutdown, err := tracing.Init(ctx, a.cfg.Tracing, a.cfg.Env)
+	if err != nil {
+		return err
+	}
+	a.closer.add("tracing", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return shutdown(ctx)
+	})
+
+	logger.Info("Tracing initialized!", zap.String("exporter", a.cfg.Tracing.Exporter))
+
+	return nil
+}
+
 // initServiceProvider is a method on the App struct.
 // It initializes the service provider for the application.
 // It takes a context as a parameter and returns an error.
-// It creates a new service provider with the database connection and Redis client from the App struct.
+// It creates a new service provider that builds the services from the configuration held by the reloader of the App struct
+// and registers the connections it opens with the closer of the App struct.
 // It then assigns the service provider to the serviceProvider field of the App struct.
 // initServiceProvider then returns nil.
 func (a *App) initServiceProvider(_ context.Context) error {
-	a.serviceProvider = newServiceProvider()
+	a.serviceProvider = newServiceProvider(a.closer, a.reloader)
 	return nil
 }
 
 // initGRPCServer is a method on the App struct.
 // It initializes the gRPC server for the application.
 // It takes a context as a parameter and returns an error.
-// It creates a new gRPC server with insecure credentials.
-// It then registers the gRPC server for reflection and the URL service implementation from the service provider.
+// It creates a new gRPC server with the credentials from serverCredentials, plaintext or TLS, which the admin gRPC server shares,
+// and a chain of interceptors that assigns every RPC a request ID, writes it to the access log, records its metrics, and turns a panic in its handler into an Internal error.
+// The recovery comes before the authentication, so the access log and the metrics see the error a panic turned into
+// and the refused calls too.
+// The authentication interceptors from authInterceptors come next, then, unless the rate limits are disabled,
+// the rate limits of every client on creating and on resolving URLs, so the calls are counted by their caller.
+// Every RPC is also traced, continuing the trace from the W3C trace context in the metadata of the request.
+// It then registers the gRPC server for reflection, the URL and API key service implementations
+// and the grpc.health.v1 service from the service provider.
 // It logs that the gRPC server was initialized.
-// initGRPCServer then returns nil.
-func (a *App) initGRPCServer(_ context.Context) error {
-	a.grpcServer = grpc.NewServer(grpc.Creds(insecure.NewCredentials()))
+// If the credentials cannot be created, initGRPCServer returns the error, otherwise it returns nil.
+func (a *App) initGRPCServer(ctx context.Context) (err error) {
+	a.creds, err = a.serverCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	unary := []grpc.UnaryServerInterceptor{
+		interceptor.RequestID(),
+		interceptor.Logging(),
+		interceptor.Metrics(),
+		interceptor.Recovery(),
+	}
+	authenticators, err := a.authInterceptors(ctx)
+	if err != nil {
+		return err
+	}
+	unary = append(unary, authenticators...)
+	if cfg := a.serviceProvider.RateLimitConfig(); cfg.Enabled() {
+		service := "/" + desc.UrlV1_ServiceDesc.ServiceName + "/"
+		unary = append(unary, interceptor.RateLimit(a.serviceProvider.RateLimiter(), cfg.Policy(), map[string]ratelimit.Rule{
+			service + "Create": {Class: "create", Limits: a.serviceProvider.RateLimits()},
+			service + "Get":    {Class: "resolve", Limits: a.serviceProvider.RateLimits()},
+		}))
+	}
+
+	a.grpcServer = grpc.NewServer(
+		grpc.Creds(a.creds),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(
+			interceptor.RequestIDStream(),
+			interceptor.LoggingStream(),
+			interceptor.RecoveryStream(),
+		),
+	)
 
 	reflection.Register(a.grpcServer)
 
-	desc.RegisterUrlV1Server(a.grpcServer, a.serviceProvider.URLImpl())
+	desc.RegisterUrlV1Server(a.grpcServer, a.serviceProvider.URLImpl(ctx))
+	apiKeyDesc.RegisterApiKeyV1Server(a.grpcServer, a.serviceProvider.APIKeyImpl(ctx))
+	healthpb.RegisterHealthServer(a.grpcServer, a.serviceProvider.HealthServer())
 
 	logger.Info("gRPC server initialized!")
 
 	return nil
 }
 
+// authInterceptors is a method on the App struct.
+// It returns the interceptors that authenticate the callers of the gRPC server, in order.
+// The API key service only answers calls with the admin token.
+// Creating, deleting and searching URLs requires a bearer token when they are enabled, checked against the JWKS
+// that is loaded again every refresh interval in the background until the context is cancelled,
+// or an API key, unless the API keys are disabled; the subject of the token or the ID of the key owns the URLs it creates.
+// With both disabled, anyone can call these RPCs.
+// If the JWKS cannot be loaded, authInterceptors returns the error.
+func (a *App) authInterceptors(ctx context.Context) ([]grpc.UnaryServerInterceptor, error) {
+	authConfig := a.serviceProvider.AuthConfig()
+	jwtConfig := a.serviceProvider.JWTConfig()
+	service := "/" + desc.UrlV1_ServiceDesc.ServiceName + "/"
+	methods := []string{service + "Create", service + "Delete", service + "Search"}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		interceptor.AdminToken(authConfig.AdminToken(), apiKeyDesc.ApiKeyV1_ServiceDesc.ServiceName),
+	}
+	if len(authConfig.AdminToken()) == 0 {
+		logger.Warn("no admin token is configured, the API keys cannot be managed")
+	}
+
+	if jwtConfig.Enabled() {
+		verifier, err := bearer.NewVerifier(ctx, jwtConfig.JWKSFile(), jwtConfig.JWKSURL(),
+			jwtConfig.Issuer(), jwtConfig.Audience(), jwtConfig.RefreshInterval(), jwtConfig.Leeway())
+		if err != nil {
+			return nil, err
+		}
+		a.background.Add(1)
+		go func() {
+			defer a.background.Done()
+			verifier.Run(ctx)
+		}()
+		interceptors = append(interceptors, interceptor.Bearer(verifier, methods...))
+	}
+
+	switch {
+	case authConfig.APIKeysEnabled():
+		interceptors = append(interceptors, interceptor.APIKey(a.serviceProvider.APIKeyService(ctx), methods...))
+	case jwtConfig.Enabled():
+		interceptors = append(interceptors, interceptor.Authenticated(methods...))
+	default:
+		logger.Warn("API keys and bearer tokens are disabled, anyone can create, delete and search URLs")
+	}
+	return interceptors, nil
+}
+
+// serverCredentials is a method on the App struct.
+// It returns the transport credentials of the gRPC server from the TLS configuration of the service provider.
+// Without TLS, the server serves plaintext, for a network where something else encrypts the traffic.
+// With TLS, it serves the certificate from the configured files, and with a client CA bundle it also requires
+// every client to present a certificate signed by one of its CAs, for service-to-service calls.
+// The files are checked for a rotated certificate every reload interval in the background until the context is cancelled,
+// so a renewed certificate is served without restarting the process.
+// It returns an error if TLS is enabled and the files cannot be loaded.
+func (a *App) serverCredentials(ctx context.Context) (credentials.TransportCredentials, error) {
+	cfg := a.serviceProvider.TLSConfig()
+	if !cfg.Enabled() {
+		logger.Warn("gRPC server serves plaintext, enable grpc.tls to encrypt the traffic")
+		return insecure.NewCredentials(), nil
+	}
+
+	reloader, err := certs.NewReloader(cfg.CertFile(), cfg.KeyFile(), cfg.ClientCAFile(), cfg.ReloadInterval())
+	if err != nil {
+		return nil, err
+	}
+
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		reloader.Run(ctx)
+	}()
+
+	return credentials.NewTLS(reloader.TLSConfig()), nil
+}
+
+// initHealth is a method on the App struct.
+// It starts checking the health of the dependencies of the application.
+// It takes a context as a parameter and returns an error.
+// It pings the dependencies once with the health checker from the service provider, so the application
+// reports whether it is ready as soon as it serves, and keeps pinging them until the context is cancelled.
+// initHealth then returns nil.
+func (a *App) initHealth(ctx context.Context) error {
+	a.health = a.serviceProvider.HealthChecker(ctx)
+	a.health.Start(ctx)
+
+	logger.Info("Health checks started!", zap.Bool("ready", a.health.Ready()))
+
+	return nil
+}
+
+// initHTTPServer is a method on the App struct.
+// It initializes the HTTP server for the application.
+// It takes a context as a parameter and returns an error.
+// It serves the liveness of the application on /healthz and its readiness on /readyz,
+// on the address from the HTTP configuration of the service provider.
+// Unless the rate limits are disabled, every client is held to the limit on resolving URLs on the routes other than the probes,
+// which are never limited so the orchestrator always reaches them.
+// The requests are traced, continuing the trace from the W3C trace context in their headers.
+// It logs that the HTTP server was initialized.
+// initHTTPServer then returns nil.
+func (a *App) initHTTPServer(_ context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", a.health.Liveness)
+	mux.HandleFunc("GET /readyz", a.health.Readiness)
+
+	handler := http.Handler(mux)
+	if cfg := a.serviceProvider.RateLimitConfig(); cfg.Enabled() {
+		rule := ratelimit.Rule{Class: "resolve", Limits: a.serviceProvider.RateLimits()}
+		handler = ratelimit.Middleware(a.serviceProvider.RateLimiter(), cfg.Policy(), rule, "/healthz", "/readyz")(handler)
+	}
+
+	a.httpServer = &http.Server{
+		Addr:              a.serviceProvider.HTTPConfig().Address(),
+		Handler:           otelhttp.NewHandler(handler, "http"),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	logger.Info("HTTP server initialized!")
+
+	return nil
+}
+
+// initAdminServer is a method on the App struct.
+// It initializes the admin server for the application, kept apart from the public ports so it can stay internal.
+// It takes a context as a parameter and returns an error.
+// It serves the metrics in the Prometheus text format on /metrics,
+// on the address from the admin configuration of the service provider.
+// It logs that the admin server was initialized.
+// initAdminServer then returns nil.
+func (a *App) initAdminServer(_ context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	a.adminServer = &http.Server{
+		Addr:              a.serviceProvider.AdminConfig().Address(),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	logger.Info("Admin server initialized!")
+
+	return nil
+}
+
+// initAdminGRPCServer is a method on the App struct.
+// It initializes the admin gRPC server for the application, kept apart from the public ports so the operational actions
+// never share a port with the public traffic.
+// It takes a context as a parameter and returns an error.
+// It creates a new gRPC server with the credentials of the gRPC server, and a chain of interceptors that assigns every RPC a request ID,
+// writes it to the access log, records its metrics, turns a panic in its handler into an Internal error,
+// and requires the admin token from the admin configuration on every call of the admin service.
+// It then registers the gRPC server for reflection and the admin service implementation from the service provider.
+// It logs that the admin gRPC server was initialized, and warns if no admin token is configured.
+// initAdminGRPCServer then returns nil.
+func (a *App) initAdminGRPCServer(ctx context.Context) error {
+	token := a.serviceProvider.AdminConfig().Token()
+	if len(token) == 0 {
+		logger.Warn("no admin token is configured, the admin service cannot be called")
+	}
+
+	a.adminGRPCServer = grpc.NewServer(
+		grpc.Creds(a.creds),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			interceptor.RequestID(),
+			interceptor.Logging(),
+			interceptor.Metrics(),
+			interceptor.Recovery(),
+			interceptor.AdminToken(token, adminDesc.AdminV1_ServiceDesc.ServiceName),
+		),
+	)
+
+	reflection.Register(a.adminGRPCServer)
+
+	adminDesc.RegisterAdminV1Server(a.adminGRPCServer, a.serviceProvider.AdminImpl(ctx))
+
+	logger.Info("Admin gRPC server initialized!")
+
+	return nil
+}
+
+// initInvalidation is a method on the App struct.
+// It starts delivering cache invalidation messages from the other instances.
+// It takes a context as a parameter and returns an error.
+// It runs the invalidation bus from the service provider in a goroutine,
+// evicting every invalidated hash from the URL cache of this instance, until the context is cancelled.
+// If the URL database implements database.Invalidator, it hears about the hash first,
+// so a lookup that misses the evicted cache does not fill it again with a stale copy of the URL.
+// initInvalidation then returns nil.
+func (a *App) initInvalidation(ctx context.Context) error {
+	bus := a.serviceProvider.InvalidationBus()
+	handler := invalidation.NewEvictor(a.serviceProvider.URLCache(ctx))
+	if db, ok := a.serviceProvider.URLDatabase(ctx).(database.Invalidator); ok {
+		handler = invalidation.Chain(db, handler)
+	}
+
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		err := bus.Run(ctx, handler)
+		if err != nil {
+			logger.Error("Cache invalidation bus stopped", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// initBackup is a method on the App struct.
+// It lets an operator back up the URL database while the service is running.
+// It takes a context as a parameter and returns an error.
+// If the URL database from the service provider implements database.Backuper and the platform has a backup signal,
+// it writes a snapshot to a new timestamped file in the storage.backupDir directory every time the process receives SIGUSR1,
+// until the context is cancelled. Otherwise it does nothing.
+// initBackup then returns nil.
+func (a *App) initBackup(ctx context.Context) error {
+	backuper, ok := a.serviceProvider.URLDatabase(ctx).(database.Backuper)
+	if !ok || len(backupSignals) == 0 {
+		return nil
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, backupSignals...)
+
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				dir := a.cfg.Storage.BackupDir
+				err := os.MkdirAll(dir, 0o755)
+				if err != nil {
+					logger.Error("Failed to create the backup directory", zap.String("dir", dir), zap.Error(err))
+					continue
+				}
+				path := filepath.Join(dir, fmt.Sprintf("shortify-%s.db", time.Now().UTC().Format("20060102T150405Z")))
+				err = backuper.Backup(ctx, path)
+				if err != nil {
+					logger.Error("Failed to back up the database", zap.String("path", path), zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	logger.Info("Database backups are enabled, send SIGUSR1 to take one", zap.Int("pid", os.Getpid()))
+
+	return nil
+}
+
+// initJobs is a method on the App struct.
+// It starts the background jobs of the application.
+// It takes a context as a parameter and returns an error.
+// If jobs.purge.enabled is set and the URL database implements database.Purger, it schedules the purge job,
+// which removes the expired URLs and the URLs deleted longer than jobs.purge.retention hours ago
+// every jobs.purge.interval seconds, jobs.purge.batchSize rows at a time, and counts them in the metrics package.
+// The jobs run until the context is cancelled.
+// initJobs then returns nil.
+func (a *App) initJobs(ctx context.Context) error {
+	purger, ok := a.serviceProvider.URLDatabase(ctx).(database.Purger)
+	if purge := a.cfg.Jobs.Purge; ok && purge.Enabled {
+		retention := time.Duration(purge.Retention) * time.Hour
+		batchSize := purge.BatchSize
+		a.jobs.add(job{
+			name:     "purge",
+			interval: time.Duration(purge.Interval) * time.Second,
+			run: func(ctx context.Context) error {
+				n, err := purger.Purge(ctx, retention, batchSize)
+				metrics.LinksPurged.Add(float64(n))
+				if n > 0 {
+					logger.Info("Purged expired and deleted URLs", zap.Int64("rows", n))
+				}
+				return err
+			},
+		})
+	}
+
+	a.jobs.start(ctx)
+
+	return nil
+}
+
+// initReload is a method on the App struct.
+// It starts watching the configuration file, so a change to the reloadable settings, the cache TTL and the rate limits,
+// applies without a restart. A change to any other setting is rejected and logged until the application is restarted.
+// It takes a context as a parameter and returns nil.
+func (a *App) initReload(_ context.Context) error {
+	a.reloader.Watch()
+	return nil
+}
+
+// runHTTPServer is a method on the App struct.
+// It starts an HTTP server of the application. The name of the server is used in logs.
+// It logs that the server is running with its address, then listens and serves until the server is stopped.
+// It returns nil once the server is stopped by stopHTTPServer, and the error otherwise.
+func (a *App) runHTTPServer(name string, server *http.Server) error {
+	logger.Info(name+" server is running", zap.String("address", server.Addr))
+
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
 // runGRPCServer is a method on the App struct.
-// It starts the gRPC server for the application.
-// It logs that the gRPC server is running with the address from the gRPC configuration of the service provider.
-// It then listens for TCP connections on the address from the gRPC configuration of the service provider.
+// It starts a gRPC server of the application on an address. The name of the server is used in logs.
+// It logs that the gRPC server is running with its address.
+// It then listens for TCP connections on the address.
 // If the listen returns an error, runGRPCServer returns the error.
 // If the listen does not return an error, runGRPCServer serves the gRPC server on the listener.
 // If the serve returns an error, runGRPCServer returns the error.
 // If the serve does not return an error, runGRPCServer returns nil.
-func (a *App) runGRPCServer() error {
-	logger.Info("GRPC server is running", zap.String("address", a.serviceProvider.GRPCConfig().Address()))
+func (a *App) runGRPCServer(name string, server *grpc.Server, address string) error {
+	logger.Info(name+" server is running", zap.String("address", address))
 
-	list, err := net.Listen("tcp", a.serviceProvider.GRPCConfig().Address())
+	list, err := net.Listen("tcp", address)
 	if err != nil {
 		return err
 	}
 
-	err = a.grpcServer.Serve(list)
+	err = server.Serve(list)
 	if err != nil {
 		return err
 	}
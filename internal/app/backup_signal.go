@@ -0,0 +1,11 @@
+//go:build !windows
+
+package app
+
+import (
+	"os"
+	"syscall"
+)
+
+// backupSignals are the signals that make the application back up the URL database.
+var backupSignals = []os.Signal{syscall.SIGUSR1}
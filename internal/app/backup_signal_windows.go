@@ -0,0 +1,9 @@
+//go:build windows
+
+package app
+
+import "os"
+
+// backupSignals are the signals that make the application back up the URL database.
+// Windows has no user signals, so backups cannot be triggered there.
+var backupSignals []os.Signal
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// closeFunc is a struct that describes a resource the application releases when it shuts down.
+type closeFunc struct {
+	name  string       // The name of the resource, used in logs
+	close func() error // The function that releases the resource
+}
+
+// closer is a struct that releases the resources of the application in the reverse order of their creation,
+// so a resource is closed only after everything that was built on top of it.
+type closer struct {
+	m     sync.Mutex  // Guards funcs
+	funcs []closeFunc // The registered resources, in the order of their creation
+}
+
+// add is a method on the closer struct.
+// It registers a resource to be released by closeAll.
+func (c *closer) add(name string, close func() error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.funcs = append(c.funcs, closeFunc{name: name, close: close})
+}
+
+// closeAll is a method on the closer struct.
+// It releases every registered resource, the last registered first, and forgets them, so a second call does nothing.
+// A resource that fails to close is logged and does not stop the others from closing.
+// It returns the errors of the resources that failed to close.
+func (c *closer) closeAll() error {
+	c.m.Lock()
+	funcs := c.funcs
+	c.funcs = nil
+	c.m.Unlock()
+
+	var errs []error
+	for i := len(funcs) - 1; i >= 0; i-- {
+		f := funcs[i]
+		err := f.close()
+		if err != nil {
+			logger.Error("Failed to close", zap.String("resource", f.name), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		logger.Debug("Closed", zap.String("resource", f.name))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// job is a struct that describes a task the application runs in the background at a fixed interval.
+type job struct {
+	name     string                          // The name of the job, used in logs
+	interval time.Duration                   // The interval between two runs
+	run      func(ctx context.Context) error // The task, it should return when the context is done
+}
+
+// scheduler is a struct that runs the background jobs of the application.
+// Every job runs in its own goroutine, and a run that takes longer than the interval delays the next one instead of overlapping it.
+type scheduler struct {
+	jobs []job          // The registered jobs
+	wg   sync.WaitGroup // Tracks the running job loops
+}
+
+// add is a method on the scheduler struct.
+// It registers a job to be started by start.
+func (s *scheduler) add(j job) {
+	s.jobs = append(s.jobs, j)
+}
+
+// start is a method on the scheduler struct.
+// It starts every registered job and runs each one every interval until the context is done.
+// The first run happens one interval after start, so the jobs do not compete with the startup of the application.
+// A job without a positive interval is logged and skipped.
+func (s *scheduler) start(ctx context.Context) {
+	for _, j := range s.jobs {
+		if j.interval <= 0 {
+			logger.Error("Background job has no interval, it will not run", zap.String("job", j.name))
+			continue
+		}
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+		logger.Info("Background job scheduled", zap.String("job", j.name), zap.Duration("interval", j.interval))
+	}
+}
+
+// wait is a method on the scheduler struct.
+// It blocks until every job loop has returned after the context passed to start is done,
+// so a run in progress finishes before the connections it uses are closed.
+func (s *scheduler) wait() {
+	s.wg.Wait()
+}
+
+// loop is a method on the scheduler struct.
+// It runs the job every interval until the context is done, logging the duration and the error of every run.
+func (s *scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			started := time.Now()
+			err := j.run(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("Background job failed", zap.String("job", j.name), zap.Duration("took", time.Since(started)), zap.Error(err))
+				continue
+			}
+			logger.Debug("Background job finished", zap.String("job", j.name), zap.Duration("took", time.Since(started)))
+		}
+	}
+}
@@ -1,73 +1,392 @@
 package app
 
 import (
-	"github.com/spf13/viper"
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/api/admin"
+	"github.com/t1ltxz-gxd/shortify/internal/api/apikey"
 	"github.com/t1ltxz-gxd/shortify/internal/api/url"
 	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	boltURL "github.com/t1ltxz-gxd/shortify/internal/database/bolt/url"
 	pgURL "github.com/t1ltxz-gxd/shortify/internal/database/postgres/url"
+	sqliteURL "github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url"
+	"github.com/t1ltxz-gxd/shortify/internal/health"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/breaker"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation"
+	pgBus "github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation/postgres"
+	redisBus "github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation/redis"
+	memoryURL "github.com/t1ltxz-gxd/shortify/internal/middleware/cache/memory/url"
 	redisURL "github.com/t1ltxz-gxd/shortify/internal/middleware/cache/redis/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/tiered"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	fallbackLimiter "github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit/fallback"
+	localLimiter "github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit/local"
+	redisLimiter "github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit/redis"
 	"github.com/t1ltxz-gxd/shortify/internal/repository"
+	apiKeyRepository "github.com/t1ltxz-gxd/shortify/internal/repository/apikey"
 	urlRepository "github.com/t1ltxz-gxd/shortify/internal/repository/url"
 	"github.com/t1ltxz-gxd/shortify/internal/service"
+	adminService "github.com/t1ltxz-gxd/shortify/internal/service/admin"
+	apiKeyService "github.com/t1ltxz-gxd/shortify/internal/service/apikey"
 	urlService "github.com/t1ltxz-gxd/shortify/internal/service/url"
+	apiKeyDesc "github.com/t1ltxz-gxd/shortify/pkg/apikey_v1"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/url_v1"
 	"go.uber.org/zap"
+	grpcHealth "google.golang.org/grpc/health"
+	"time"
 )
 
 // serviceProvider is a struct that holds the dependencies for the service provider.
-// It includes a grpcConfig which holds the gRPC configuration,
+// It includes the configuration of the application and the reloader holding its current reloadable settings, a grpcConfig which holds the gRPC configuration,
 // a urlRepository which is the URL repository,
 // a urlService which is the URL service,
-// a urlImpl which is the URL implementation.
+// a urlImpl which is the URL implementation, the API key repository, service and implementation,
+// the admin service and implementation, the health reporting, the time when the application started,
+// and a closer that releases the connections the service provider opened when the application shuts down.
 type serviceProvider struct {
-	grpcConfig    config.GRPCConfig        // grpcConfig holds the gRPC configuration
-	urlRepository repository.URLRepository // urlRepository is the URL repository
-	urlService    service.URLService       // urlService is the URL service
-	urlImpl       *url.Implementation      // urlImpl is the URL implementation
+	cfg              *config.Config              // cfg is the validated configuration the application started with
+	reloader         *config.Reloader            // reloader holds the current configuration of the application
+	closer           *closer                     // closer releases the connections opened by the service provider
+	startedAt        time.Time                   // startedAt is the time when the application started
+	grpcConfig       config.GRPCConfig           // grpcConfig holds the gRPC configuration
+	httpConfig       config.HTTPConfig           // httpConfig holds the HTTP configuration
+	adminConfig      config.AdminConfig          // adminConfig holds the admin server configuration
+	tlsConfig        config.TLSConfig            // tlsConfig holds the TLS configuration of the gRPC server
+	authConfig       config.AuthConfig           // authConfig holds the authentication configuration of the gRPC server
+	jwtConfig        config.JWTConfig            // jwtConfig holds the bearer token configuration of the gRPC server
+	rateLimitConfig  config.RateLimitConfig      // rateLimitConfig holds the rate limit configuration
+	rateLimiter      ratelimit.Limiter           // rateLimiter is the rate limiter of the gRPC and HTTP servers
+	rateLimits       *ratelimit.Limits           // rateLimits are the current limits of the create and resolve requests
+	healthServer     *grpcHealth.Server          // healthServer is the grpc.health.v1 service
+	healthChecker    health.Checker              // healthChecker pings the dependencies and reports the health of the application
+	cacheBreaker     breaker.Breaker             // cacheBreaker is the circuit breaker around the URL cache
+	urlCache         cache.URLCache              // urlCache is the URL cache used by the repository
+	invalidationBus  invalidation.Bus            // invalidationBus is the cache invalidation bus
+	urlDatabase      database.URLDatabase        // urlDatabase is the URL storage of the configured driver
+	urlRepository    repository.URLRepository    // urlRepository is the URL repository
+	urlService       service.URLService          // urlService is the URL service
+	urlImpl          *url.Implementation         // urlImpl is the URL implementation
+	apiKeyRepository repository.APIKeyRepository // apiKeyRepository is the API key repository
+	apiKeyService    service.APIKeyService       // apiKeyService is the API key service
+	apiKeyImpl       *apikey.Implementation      // apiKeyImpl is the API key implementation
+	adminService     service.AdminService        // adminService is the admin service
+	adminImpl        *admin.Implementation       // adminImpl is the admin implementation
 }
 
 // newServiceProvider is a function that creates a new serviceProvider struct.
-// It takes a sqlx.DB pointer and a redis.Client pointer as parameters and returns a pointer to a serviceProvider struct.
+// It takes the closer that the connections opened by the service provider are registered with
+// and the reloader holding the validated configuration of the application as parameters,
+// and returns a pointer to a serviceProvider struct, which records the current time as the time when the application started.
+// The settings that need a restart are read from the configuration the application started with,
+// and the reloadable ones from the reloader, so a reload applies to them.
 // It logs that the service provider was initialized and returns the serviceProvider struct.
-func newServiceProvider() *serviceProvider {
+func newServiceProvider(closer *closer, reloader *config.Reloader) *serviceProvider {
 	logger.Debug("Service provider initialized!")
-	return &serviceProvider{}
+	return &serviceProvider{cfg: reloader.Current(), reloader: reloader, closer: closer, startedAt: time.Now()}
 }
 
 // GRPCConfig is a method on the serviceProvider struct.
 // It gets the gRPC configuration for the service provider.
-// If the grpcConfig field of the serviceProvider struct is nil, it creates a new gRPC configuration and assigns it to the grpcConfig field.
-// If the creation of the gRPC configuration returns an error, it logs the error and exits the application.
+// If the grpcConfig field of the serviceProvider struct is nil, it creates a new gRPC configuration from the configuration of the application and assigns it to the grpcConfig field.
 // It logs that the gRPC configuration was initialized and returns the gRPC configuration.
 func (s *serviceProvider) GRPCConfig() config.GRPCConfig {
 	if s.grpcConfig == nil {
-		cfg, err := config.NewGRPCConfig()
-		if err != nil {
-			logger.Fatal("failed to get grpc config", zap.Error(err)) // Log the error and exit the application if the creation of the gRPC configuration returns an error
-		}
-
-		s.grpcConfig = cfg // Set the gRPC configuration
+		s.grpcConfig = config.NewGRPCConfig(s.cfg)
 	}
 	logger.Debug("GRPC config initialized!")
 
 	return s.grpcConfig // Return the gRPC configuration
 }
 
-// URLRepository is a method on the serviceProvider struct.
-// It gets the URL repository for the service provider.
-// If the urlRepository field of the serviceProvider struct is nil, it creates a new URL repository with the database connection and Redis client from the serviceProvider struct and assigns it to the urlRepository field.
-// It logs that the URL repository was initialized and returns the URL repository.
-func (s *serviceProvider) URLRepository() repository.URLRepository {
-	if s.urlRepository == nil {
-		db := pgURL.Init()
+// HTTPConfig is a method on the serviceProvider struct.
+// It gets the HTTP configuration for the service provider.
+// If the httpConfig field of the serviceProvider struct is nil, it creates a new HTTP configuration and assigns it to the httpConfig field.
+// It logs that the HTTP configuration was initialized and returns the HTTP configuration.
+func (s *serviceProvider) HTTPConfig() config.HTTPConfig {
+	if s.httpConfig == nil {
+		s.httpConfig = config.NewHTTPConfig(s.cfg)
+	}
+	logger.Debug("HTTP config initialized!")
+
+	return s.httpConfig
+}
+
+// AdminConfig is a method on the serviceProvider struct.
+// It gets the admin server configuration for the service provider.
+// If the adminConfig field of the serviceProvider struct is nil, it creates a new admin configuration and assigns it to the adminConfig field.
+// It logs that the admin configuration was initialized and returns the admin configuration.
+func (s *serviceProvider) AdminConfig() config.AdminConfig {
+	if s.adminConfig == nil {
+		s.adminConfig = config.NewAdminConfig(s.cfg)
+	}
+	logger.Debug("Admin config initialized!")
+
+	return s.adminConfig
+}
+
+// TLSConfig is a method on the serviceProvider struct.
+// It gets the TLS configuration of the gRPC server for the service provider.
+// If the tlsConfig field of the serviceProvider struct is nil, it creates a new TLS configuration and assigns it to the tlsConfig field.
+// It logs that the TLS configuration was initialized and returns the TLS configuration.
+func (s *serviceProvider) TLSConfig() config.TLSConfig {
+	if s.tlsConfig == nil {
+		s.tlsConfig = config.NewTLSConfig(s.cfg)
+	}
+	logger.Debug("TLS config initialized!")
+
+	return s.tlsConfig
+}
+
+// AuthConfig is a method on the serviceProvider struct.
+// It gets the authentication configuration of the gRPC server for the service provider.
+// If the authConfig field of the serviceProvider struct is nil, it creates a new authentication configuration and assigns it to the authConfig field.
+// It logs that the authentication configuration was initialized and returns the authentication configuration.
+func (s *serviceProvider) AuthConfig() config.AuthConfig {
+	if s.authConfig == nil {
+		s.authConfig = config.NewAuthConfig(s.cfg)
+	}
+	logger.Debug("Auth config initialized!")
+
+	return s.authConfig
+}
+
+// JWTConfig is a method on the serviceProvider struct.
+// It gets the bearer token configuration of the gRPC server for the service provider.
+// If the jwtConfig field of the serviceProvider struct is nil, it creates a new bearer token configuration and assigns it to the jwtConfig field.
+// It logs that the bearer token configuration was initialized and returns the bearer token configuration.
+func (s *serviceProvider) JWTConfig() config.JWTConfig {
+	if s.jwtConfig == nil {
+		s.jwtConfig = config.NewJWTConfig(s.cfg)
+	}
+	logger.Debug("JWT config initialized!")
+
+	return s.jwtConfig
+}
+
+// RateLimitConfig is a method on the serviceProvider struct.
+// It gets the rate limit configuration for the service provider.
+// If the rateLimitConfig field of the serviceProvider struct is nil, it creates a new rate limit configuration and assigns it to the rateLimitConfig field.
+// It logs that the rate limit configuration was initialized and returns the rate limit configuration.
+func (s *serviceProvider) RateLimitConfig() config.RateLimitConfig {
+	if s.rateLimitConfig == nil {
+		s.rateLimitConfig = config.NewRateLimitConfig(s.cfg)
+	}
+	logger.Debug("Rate limit config initialized!")
+
+	return s.rateLimitConfig
+}
+
+// HealthServer is a method on the serviceProvider struct.
+// It gets the grpc.health.v1 service for the service provider.
+// If the healthServer field of the serviceProvider struct is nil, it creates a new health server and assigns it to the healthServer field.
+// It logs that the health server was initialized and returns the health server.
+func (s *serviceProvider) HealthServer() *grpcHealth.Server {
+	if s.healthServer == nil {
+		s.healthServer = grpcHealth.NewServer()
+	}
+	logger.Debug("Health server initialized!")
+
+	return s.healthServer
+}
+
+// HealthChecker is a method on the serviceProvider struct.
+// It gets the health checker for the service provider.
+// If the healthChecker field of the serviceProvider struct is nil, it creates a checker that reports to the health server
+// and pings the URL database and the URL cache every health.interval seconds, each ping limited to health.timeout milliseconds.
+// It also reports the state of the circuit breaker around the cache, "ok" while it is closed and the state otherwise.
+// The application is ready only while the database answers; the cache and its breaker are reported on their own,
+// because lookups fall back to the database while the cache is down.
+// It assigns the checker to the healthChecker field, logs that the health checker was initialized and returns the health checker.
+func (s *serviceProvider) HealthChecker(ctx context.Context) health.Checker {
+	if s.healthChecker == nil {
+		s.healthChecker = health.NewChecker(
+			s.HealthServer(),
+			[]string{desc.UrlV1_ServiceDesc.ServiceName, apiKeyDesc.ApiKeyV1_ServiceDesc.ServiceName},
+			time.Duration(s.cfg.Health.Interval)*time.Second,
+			time.Duration(s.cfg.Health.Timeout)*time.Millisecond,
+			health.Probe{Name: "database", Ping: s.URLDatabase(ctx).Ping, Critical: true},
+			health.Probe{Name: "cache", Ping: s.URLCache(ctx).Ping},
+			health.Probe{Name: "cacheBreaker", Ping: breaker.CheckClosed(s.CacheBreaker(ctx))},
+		)
+	}
+	logger.Debug("Health checker initialized!")
+
+	return s.healthChecker
+}
+
+// CacheBreaker is a method on the serviceProvider struct.
+// It gets the circuit breaker around the URL cache for the service provider.
+// If the cacheBreaker field of the serviceProvider struct is nil, it connects to Redis and wraps the Redis cache in a circuit breaker
+// configured from the cache.breaker settings, assigns it to the cacheBreaker field and registers it with the closer.
+// It logs that the cache breaker was initialized and returns the cache breaker.
+func (s *serviceProvider) CacheBreaker(ctx context.Context) breaker.Breaker {
+	if s.cacheBreaker == nil {
+		s.cacheBreaker = breaker.NewBreaker(
+			redisURL.Init(ctx, s.cfg),
+			s.cfg.Cache.Breaker.FailureThreshold,
+			time.Duration(s.cfg.Cache.Breaker.ProbeInterval)*time.Second,
+		)
+		s.closer.add("cache", s.cacheBreaker.Close)
+	}
+	logger.Debug("Cache breaker initialized!", zap.String("state", s.cacheBreaker.State().String()))
+
+	return s.cacheBreaker
+}
+
+// RateLimiter is a method on the serviceProvider struct.
+// It gets the rate limiter for the service provider.
+// If the rateLimiter field of the serviceProvider struct is nil, it creates the limiter of the configured driver:
+// local keeps the buckets in memory, and redis keeps them in the Redis server of the cache, each decision limited to cache.timeout milliseconds,
+// falling back on buckets in memory for the retry interval whenever Redis fails.
+// It assigns the limiter to the rateLimiter field and registers it with the closer.
+// It logs that the rate limiter was initialized and returns the rate limiter.
+func (s *serviceProvider) RateLimiter() ratelimit.Limiter {
+	if s.rateLimiter == nil {
+		cfg := s.RateLimitConfig()
+		switch cfg.Driver() {
+		case "local":
+			s.rateLimiter = localLimiter.NewLimiter()
+		default:
+			s.rateLimiter = fallbackLimiter.NewLimiter(
+				redisLimiter.NewLimiter(
+					redisURL.NewClient(s.cfg),
+					"shortify:ratelimit:",
+					time.Duration(s.cfg.Cache.Timeout)*time.Millisecond,
+				),
+				localLimiter.NewLimiter(),
+				cfg.RetryInterval(),
+			)
+		}
+		s.closer.add("rate limiter", s.rateLimiter.Close)
+	}
+	logger.Debug("Rate limiter initialized!", zap.String("driver", s.RateLimitConfig().Driver()))
+
+	return s.rateLimiter
+}
+
+// RateLimits is a method on the serviceProvider struct.
+// It gets the limits of the create and resolve requests for the service provider.
+// If the rateLimits field of the serviceProvider struct is nil, it creates the limits from the rate limit configuration
+// and updates them whenever the configuration is reloaded, so the requests that arrive after a reload are checked against the new limits.
+// It logs that the rate limits were initialized and returns the rate limits.
+func (s *serviceProvider) RateLimits() *ratelimit.Limits {
+	if s.rateLimits == nil {
+		limitsOf := func(cfg config.RateLimitConfig) map[string]ratelimit.Limit {
+			return map[string]ratelimit.Limit{"create": cfg.Create(), "resolve": cfg.Resolve()}
+		}
+		s.rateLimits = ratelimit.NewLimits(limitsOf(s.RateLimitConfig()))
+		s.reloader.OnReload(func(cfg *config.Config) {
+			s.rateLimits.Set(limitsOf(config.NewRateLimitConfig(cfg)))
+		})
+	}
+	logger.Debug("Rate limits initialized!")
+
+	return s.rateLimits
+}
+
+// URLCache is a method on the serviceProvider struct.
+// It gets the URL cache for the service provider.
+// If the urlCache field of the serviceProvider struct is nil, it uses the cache breaker from the serviceProvider struct,
+// and if the cache.local settings enable it, puts an in-process cache in front of it.
+// It assigns the cache to the urlCache field, logs that the URL cache was initialized and returns the URL cache.
+func (s *serviceProvider) URLCache(ctx context.Context) cache.URLCache {
+	if s.urlCache == nil {
+		s.urlCache = s.CacheBreaker(ctx)
+		if local := s.cfg.Cache.Local; local.Enabled {
+			s.urlCache = tiered.NewCache(
+				memoryURL.NewCache(local.Size),
+				s.urlCache,
+				time.Duration(local.TTL)*time.Second,
+			)
+		}
+	}
+	logger.Debug("URL cache initialized!")
+
+	return s.urlCache
+}
+
+// InvalidationBus is a method on the serviceProvider struct.
+// It gets the cache invalidation bus for the service provider.
+// If the invalidationBus field of the serviceProvider struct is nil, it creates the bus selected by the cache.invalidation.driver setting:
+// "redis" for Redis pub/sub, "postgres" for Postgres LISTEN/NOTIFY, and "none" to disable invalidation between instances.
+// The bus is registered with the closer.
+// If the driver is unknown or the bus cannot be created, it logs the error and exits the application.
+// It logs that the invalidation bus was initialized and returns the invalidation bus.
+func (s *serviceProvider) InvalidationBus() invalidation.Bus {
+	if s.invalidationBus == nil {
+		channel := s.cfg.Cache.Invalidation.Channel
+		switch driver := s.cfg.Cache.Invalidation.Driver; driver {
+		case "redis":
+			s.invalidationBus = redisBus.NewBus(redisURL.NewClient(s.cfg), channel)
+		case "postgres":
+			bus, err := pgBus.NewBus(pgURL.DSN(s.cfg.Postgres), channel)
+			if err != nil {
+				logger.Fatal("failed to create cache invalidation bus", zap.Error(err))
+			}
+			s.invalidationBus = bus
+		case "none":
+			s.invalidationBus = invalidation.NewNopBus()
+		default:
+			logger.Fatal("unknown cache invalidation driver", zap.String("driver", driver))
+		}
+		s.closer.add("cache invalidation bus", s.invalidationBus.Close)
+	}
+	logger.Debug("Cache invalidation bus initialized!")
+
+	return s.invalidationBus
+}
+
+// URLDatabase is a method on the serviceProvider struct.
+// It gets the URL storage for the service provider.
+// If the urlDatabase field of the serviceProvider struct is nil, it opens the storage selected by the storage.driver setting,
+// postgres for a server, sqlite for a single file, or bolt for an embedded key-value file, applies its migrations,
+// assigns it to the urlDatabase field and registers it with the closer.
+// If the driver is unknown or the migrations fail, it logs the error and exits the application.
+// It logs that the URL database was initialized and returns the URL database.
+func (s *serviceProvider) URLDatabase(ctx context.Context) database.URLDatabase {
+	if s.urlDatabase == nil {
+		var db database.URLDatabase
+		switch driver := s.cfg.Storage.Driver; driver {
+		case "postgres":
+			db = pgURL.Init(ctx, s.cfg)
+		case "sqlite":
+			db = sqliteURL.Init(ctx, s.cfg)
+		case "bolt":
+			db = boltURL.Init(ctx, s.cfg)
+		default:
+			logger.Fatal("unknown storage driver", zap.String("driver", driver))
+		}
 		// Apply the database migrations by calling the applyMigration method
-		err := db.ApplyMigrations(viper.GetStringSlice("migrationFiles"))
+		err := db.ApplyMigrations(ctx)
 		// If the applyMigration method returns an error, return the error
 		if err != nil {
 			logger.Fatal("failed to apply migrations", zap.Error(err))
 		}
-		cache := redisURL.Init()
-		s.urlRepository = urlRepository.NewRepository(db, cache)
+		s.urlDatabase = db
+		s.closer.add("database", db.Close)
+	}
+	logger.Debug("URL database initialized!")
+
+	return s.urlDatabase
+}
+
+// URLRepository is a method on the serviceProvider struct.
+// It gets the URL repository for the service provider.
+// If the urlRepository field of the serviceProvider struct is nil, it creates a new URL repository with the URL database, the URL cache and the invalidation bus from the serviceProvider struct,
+// and the lifetime of a cache entry from the app.services.hash.ttlCache setting of the current configuration, and assigns it to the urlRepository field.
+// It logs that the URL repository was initialized and returns the URL repository.
+func (s *serviceProvider) URLRepository(ctx context.Context) repository.URLRepository {
+	if s.urlRepository == nil {
+		s.urlRepository = urlRepository.NewRepository(
+			s.URLDatabase(ctx),
+			s.URLCache(ctx),
+			s.InvalidationBus(),
+			func() time.Duration {
+				return time.Duration(s.reloader.Current().App.Services.Hash.TTLCache) * time.Second
+			},
+		)
 	}
 	logger.Debug("URL repository initialized!")
 
@@ -76,12 +395,15 @@ func (s *serviceProvider) URLRepository() repository.URLRepository {
 
 // URLService is a method on the serviceProvider struct.
 // It gets the URL service for the service provider.
-// If the urlService field of the serviceProvider struct is nil, it creates a new URL service with the URL repository from the serviceProvider struct and assigns it to the urlService field.
+// If the urlService field of the serviceProvider struct is nil, it creates a new URL service with the URL repository from the serviceProvider struct,
+// the hash configuration, and the address of the gRPC server as the base of the short URLs, and assigns it to the urlService field.
 // It logs that the URL service was initialized and returns the URL service.
-func (s *serviceProvider) URLService() service.URLService {
+func (s *serviceProvider) URLService(ctx context.Context) service.URLService {
 	if s.urlService == nil {
 		s.urlService = urlService.NewService(
-			s.URLRepository(),
+			s.URLRepository(ctx),
+			s.cfg.App.Services.Hash,
+			"http://"+s.GRPCConfig().Address(),
 		)
 	}
 	logger.Debug("URL service initialized!")
@@ -93,10 +415,89 @@ func (s *serviceProvider) URLService() service.URLService {
 // It gets the URL implementation for the service provider.
 // If the urlImpl field of the serviceProvider struct is nil, it creates a new URL implementation with the URL service from the serviceProvider struct and assigns it to the urlImpl field.
 // It logs that the URL implementation was initialized and returns the URL implementation.
-func (s *serviceProvider) URLImpl() *url.Implementation {
+func (s *serviceProvider) URLImpl(ctx context.Context) *url.Implementation {
 	if s.urlImpl == nil {
-		s.urlImpl = url.NewImplementation(s.URLService())
+		s.urlImpl = url.NewImplementation(s.URLService(ctx))
 	}
 	logger.Debug("URL implementation initialized!")
 	return s.urlImpl
 }
+
+// APIKeyRepository is a method on the serviceProvider struct.
+// It gets the API key repository for the service provider.
+// If the apiKeyRepository field of the serviceProvider struct is nil, it creates a new API key repository with the URL database from the serviceProvider struct,
+// which keeps the API keys next to the URLs they own, and assigns it to the apiKeyRepository field.
+// If the storage driver does not store API keys, it logs the error and exits the application.
+// It logs that the API key repository was initialized and returns the API key repository.
+func (s *serviceProvider) APIKeyRepository(ctx context.Context) repository.APIKeyRepository {
+	if s.apiKeyRepository == nil {
+		db, ok := s.URLDatabase(ctx).(database.APIKeyDatabase)
+		if !ok {
+			logger.Fatal("storage driver does not support api keys", zap.String("driver", s.cfg.Storage.Driver))
+		}
+		s.apiKeyRepository = apiKeyRepository.NewRepository(db)
+	}
+	logger.Debug("API key repository initialized!")
+
+	return s.apiKeyRepository
+}
+
+// APIKeyService is a method on the serviceProvider struct.
+// It gets the API key service for the service provider.
+// If the apiKeyService field of the serviceProvider struct is nil, it creates a new API key service with the API key repository from the serviceProvider struct and assigns it to the apiKeyService field.
+// It logs that the API key service was initialized and returns the API key service.
+func (s *serviceProvider) APIKeyService(ctx context.Context) service.APIKeyService {
+	if s.apiKeyService == nil {
+		s.apiKeyService = apiKeyService.NewService(
+			s.APIKeyRepository(ctx),
+		)
+	}
+	logger.Debug("API key service initialized!")
+
+	return s.apiKeyService
+}
+
+// APIKeyImpl is a method on the serviceProvider struct.
+// It gets the API key implementation for the service provider.
+// If the apiKeyImpl field of the serviceProvider struct is nil, it creates a new API key implementation with the API key service from the serviceProvider struct and assigns it to the apiKeyImpl field.
+// It logs that the API key implementation was initialized and returns the API key implementation.
+func (s *serviceProvider) APIKeyImpl(ctx context.Context) *apikey.Implementation {
+	if s.apiKeyImpl == nil {
+		s.apiKeyImpl = apikey.NewImplementation(s.APIKeyService(ctx))
+	}
+	logger.Debug("API key implementation initialized!")
+	return s.apiKeyImpl
+}
+
+// AdminService is a method on the serviceProvider struct.
+// It gets the admin service for the service provider.
+// If the adminService field of the serviceProvider struct is nil, it creates a new admin service with the URL repository,
+// the health checker and the cache breaker from the serviceProvider struct, the time when the application started,
+// and the reloader holding the current configuration of the application, and assigns it to the adminService field.
+// It logs that the admin service was initialized and returns the admin service.
+func (s *serviceProvider) AdminService(ctx context.Context) service.AdminService {
+	if s.adminService == nil {
+		s.adminService = adminService.NewService(
+			s.URLRepository(ctx),
+			s.HealthChecker(ctx),
+			s.CacheBreaker(ctx),
+			s.startedAt,
+			s.reloader,
+		)
+	}
+	logger.Debug("Admin service initialized!")
+
+	return s.adminService
+}
+
+// AdminImpl is a method on the serviceProvider struct.
+// It gets the admin implementation for the service provider.
+// If the adminImpl field of the serviceProvider struct is nil, it creates a new admin implementation with the admin service from the serviceProvider struct and assigns it to the adminImpl field.
+// It logs that the admin implementation was initialized and returns the admin implementation.
+func (s *serviceProvider) AdminImpl(ctx context.Context) *admin.Implementation {
+	if s.adminImpl == nil {
+		s.adminImpl = admin.NewImplementation(s.AdminService(ctx))
+	}
+	logger.Debug("Admin implementation initialized!")
+	return s.adminImpl
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// The scopes a caller needs for the RPCs of the URL service
+const (
+	ScopeRead  = "urls:read"  // Finds the URLs of the caller
+	ScopeWrite = "urls:write" // Creates and deletes the URLs of the caller
+)
+
+// Principal is a struct that describes the authenticated caller of an RPC.
+// It is put in the context of the RPC by an authentication interceptor, so the services can tell who is calling
+// without knowing how the caller authenticated.
+type Principal struct {
+	Subject string   // The identifier of the caller, recorded as the owner of the URLs it creates, see KeySubject and TokenSubject
+	Name    string   // The name of the caller, for the logs
+	Scopes  []string // The scopes granted to the caller, like urls:write
+}
+
+// KeySubject is a function that returns the subject of the caller authenticated with the API key of the ID.
+// The subjects are prefixed by where they come from, so an API key never owns the links of a bearer token
+// whose subject happens to be the ID of the key.
+func KeySubject(id string) string {
+	return "key:" + id
+}
+
+// TokenSubject is a function that returns the subject of the caller authenticated with a bearer token
+// of the issuer and the subject. A subject is only unique for its issuer, so both are part of it.
+func TokenSubject(issuer, subject string) string {
+	return "jwt:" + issuer + "|" + subject
+}
+
+// principalKey is the key of the principal in a context.
+type principalKey struct{}
+
+// WithPrincipal is a function that returns a copy of the context holding the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext is a function that returns the principal of the context, and whether there is one.
+// There is none when the RPC did not need authentication or the authentication is disabled.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Owner is a function that returns the owner of the URLs created or managed in the context:
+// the subject of the principal, or an empty string for an anonymous caller.
+func Owner(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
+
+// HasScope is a function that reports whether the caller in the context was granted the scope.
+// An anonymous caller has every scope, because there is none only when the authentication is disabled.
+func HasScope(ctx context.Context, scope string) bool {
+	p, ok := FromContext(ctx)
+	return !ok || slices.Contains(p.Scopes, scope)
+}
@@ -0,0 +1,261 @@
+package bearer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxKeySetSize is the maximum size of a key set fetched from a URL, so a broken endpoint cannot exhaust the memory.
+const maxKeySetSize = 1 << 20
+
+// algorithms are the signature algorithms a token may be signed with.
+// The symmetric ones are left out, since the key set only holds the public keys of the issuer.
+var algorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// Verifier is an interface that verifies the JSON Web Tokens issued by a gateway
+// against the keys of a JSON Web Key Set, and keeps the key set up to date.
+type Verifier interface {
+	// Verify is a method that checks the signature, the issuer, the audience and the times of a token.
+	// It returns the caller the token was issued to, with its subject prefixed by the issuer and its scopes, and an error if the token is not valid.
+	Verify(ctx context.Context, token string) (*auth.Principal, error)
+	// Run is a method that loads the key set again every interval until the context is done, so rotated keys are picked up.
+	// A key set that cannot be loaded is logged and the keys loaded last stay in use.
+	Run(ctx context.Context)
+}
+
+// Ensure that the verifier struct implements the Verifier interface
+var _ Verifier = (*verifier)(nil)
+
+// verifier is a struct that implements the Verifier interface.
+type verifier struct {
+	file     string        // The path of the key set, empty if it is fetched from url
+	url      string        // The URL of the key set, empty if it is read from file
+	issuer   string        // The issuer the tokens must name
+	audience string        // The audience the tokens must name
+	interval time.Duration // The interval between two loads of the key set
+	leeway   time.Duration // The clock skew allowed when checking the times of the tokens
+	client   *http.Client  // The client the key set is fetched with
+
+	keys atomic.Pointer[jose.JSONWebKeySet] // The key set loaded last
+}
+
+// NewVerifier is a function that creates a new token verifier.
+// It takes the context of the first load of the key set, the path of the file or the URL the key set is loaded from, one of them empty,
+// the issuer and the audience the tokens must name, the interval between two loads of the key set,
+// and the clock skew allowed when checking the times of the tokens.
+// It returns the verifier, and an error if the key set cannot be loaded.
+func NewVerifier(ctx context.Context, file, url, issuer, audience string, interval, leeway time.Duration) (Verifier, error) {
+	v := &verifier{
+		file:     file,
+		url:      url,
+		issuer:   issuer,
+		audience: audience,
+		interval: interval,
+		leeway:   leeway,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	keys, err := v.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.keys.Store(keys)
+	logger.Info("JWKS loaded", zap.String("source", v.source()), zap.Strings("kids", keyIDs(keys)))
+	return v, nil
+}
+
+// Verify is a method on the verifier struct.
+// It parses the token, verifies its signature with the key named by its kid header, or with any key without one,
+// and validates its claims at the current time.
+// A token must carry a subject and an expiry. Its scopes are read from the space-separated scope claim
+// or from the scp claim, and its name from the name claim.
+func (v *verifier) Verify(_ context.Context, token string) (*auth.Principal, error) {
+	tok, err := jwt.ParseSigned(token, algorithms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the token: %w", err)
+	}
+
+	var claims jwt.Claims
+	var extra struct {
+		Name  string `json:"name"`
+		Scope string `json:"scope"`
+		Scp   scopes `json:"scp"`
+	}
+	if err := v.verifySignature(tok, &claims, &extra); err != nil {
+		return nil, err
+	}
+
+	err = claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:      v.issuer,
+		AnyAudience: jwt.Audience{v.audience},
+		Time:        time.Now(),
+	}, v.leeway)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Expiry == nil {
+		return nil, errors.New("the token does not expire")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("the token has no subject")
+	}
+
+	principal := &auth.Principal{
+		Subject: auth.TokenSubject(claims.Issuer, claims.Subject),
+		Name:    extra.Name,
+		Scopes:  append(strings.Fields(extra.Scope), extra.Scp...),
+	}
+	if principal.Name == "" {
+		principal.Name = claims.Subject
+	}
+	return principal, nil
+}
+
+// verifySignature is a method on the verifier struct.
+// It decodes the claims of the token with the first key of the key set that verifies its signature.
+// It returns an error if no key does.
+func (v *verifier) verifySignature(tok *jwt.JSONWebToken, claims ...any) error {
+	keys := v.keys.Load()
+	candidates := keys.Keys
+	if kid := tok.Headers[0].KeyID; kid != "" {
+		candidates = keys.Key(kid)
+		if len(candidates) == 0 {
+			return fmt.Errorf("unknown key %q", kid)
+		}
+	}
+	for _, key := range candidates {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if err := tok.Claims(key.Key, claims...); err == nil {
+			return nil
+		}
+	}
+	return errors.New("the signature does not match any key")
+}
+
+// Run is a method on the verifier struct.
+// It loads the key set every interval and swaps it in for the next tokens.
+func (v *verifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v.refresh(ctx)
+		}
+	}
+}
+
+// refresh is a method on the verifier struct.
+// It loads the key set, keeps it if it holds a key, and logs when the key IDs changed.
+func (v *verifier) refresh(ctx context.Context) {
+	keys, err := v.load(ctx)
+	if err != nil {
+		logger.Error("Failed to refresh the JWKS, keeping the current keys", zap.String("source", v.source()), zap.Error(err))
+		return
+	}
+	previous := v.keys.Swap(keys)
+	if kids := keyIDs(keys); !slices.Equal(kids, keyIDs(previous)) {
+		logger.Info("JWKS keys changed", zap.String("source", v.source()), zap.Strings("kids", kids))
+	}
+}
+
+// load is a method on the verifier struct.
+// It reads the key set from the file, or fetches it from the URL, and parses it.
+// It returns the key set, and an error if it cannot be read, is not a key set or holds no key.
+func (v *verifier) load(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	var data []byte
+	var err error
+	if v.url != "" {
+		data, err = v.fetch(ctx)
+	} else {
+		data, err = os.ReadFile(v.file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the JWKS: %w", err)
+	}
+
+	keys := &jose.JSONWebKeySet{}
+	if err := json.Unmarshal(data, keys); err != nil {
+		return nil, fmt.Errorf("failed to parse the JWKS: %w", err)
+	}
+	if len(keys.Keys) == 0 {
+		return nil, errors.New("the JWKS holds no key")
+	}
+	return keys, nil
+}
+
+// fetch is a method on the verifier struct.
+// It gets the key set from the URL and returns its body, and an error if the request fails or does not answer 200 OK.
+func (v *verifier) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxKeySetSize))
+}
+
+// source is a method on the verifier struct. It returns where the key set is loaded from, for the logs.
+func (v *verifier) source() string {
+	if v.url != "" {
+		return v.url
+	}
+	return v.file
+}
+
+// keyIDs is a function that returns the IDs of the keys of a key set, in order.
+func keyIDs(keys *jose.JSONWebKeySet) []string {
+	kids := make([]string, 0, len(keys.Keys))
+	for _, key := range keys.Keys {
+		kids = append(kids, key.KeyID)
+	}
+	return kids
+}
+
+// scopes is a type for the scp claim, which some issuers send as a list and others as a space-separated string.
+type scopes []string
+
+// UnmarshalJSON is a method on the scopes type. It decodes a list of scopes or a space-separated string of them.
+func (s *scopes) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = strings.Fields(str)
+	return nil
+}
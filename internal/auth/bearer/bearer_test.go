@@ -0,0 +1,218 @@
+package bearer_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/auth/bearer"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+const (
+	issuer   = "https://gateway.example.com"
+	audience = "shortify"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// signingKey is a struct that holds a key of the gateway issuing the tokens of the tests.
+type signingKey struct {
+	kid string
+	key *ecdsa.PrivateKey
+}
+
+// newSigningKey is a function that generates a signing key with the key ID.
+func newSigningKey(t *testing.T, kid string) *signingKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &signingKey{kid: kid, key: key}
+}
+
+// keySet is a function that returns the JWKS document of the public parts of the keys.
+func keySet(t *testing.T, keys ...*signingKey) []byte {
+	set := jose.JSONWebKeySet{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, jose.JSONWebKey{Key: &k.key.PublicKey, KeyID: k.kid, Algorithm: string(jose.ES256), Use: "sig"})
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+// sign is a function that returns a token signed with the key, holding the registered and the extra claims.
+func (k *signingKey) sign(t *testing.T, claims jwt.Claims, extra map[string]any) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.ES256, Key: k.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", k.kid),
+	)
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(claims).Claims(extra).Serialize()
+	require.NoError(t, err)
+	return token
+}
+
+// validClaims is a function that returns the claims of a token the verifier accepts.
+func validClaims() jwt.Claims {
+	now := time.Now()
+	return jwt.Claims{
+		Issuer:   issuer,
+		Subject:  "user1",
+		Audience: jwt.Audience{audience, "other"},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+}
+
+// newFileVerifier is a function that writes the JWKS of the keys to a file and returns a verifier reading it.
+func newFileVerifier(t *testing.T, keys ...*signingKey) bearer.Verifier {
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(file, keySet(t, keys...), 0o600))
+	verifier, err := bearer.NewVerifier(context.Background(), file, "", issuer, audience, time.Minute, 0)
+	require.NoError(t, err)
+	return verifier
+}
+
+// TestVerify is a test function that checks that a valid token gives its subject, name and scopes.
+func TestVerify(t *testing.T) {
+	key := newSigningKey(t, "key1")
+	verifier := newFileVerifier(t, key)
+
+	principal, err := verifier.Verify(context.Background(), key.sign(t, validClaims(), map[string]any{
+		"name":  "Alice",
+		"scope": "urls:read urls:write",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "jwt:"+issuer+"|user1", principal.Subject)
+	assert.Equal(t, "Alice", principal.Name)
+	assert.Equal(t, []string{"urls:read", "urls:write"}, principal.Scopes)
+
+	principal, err = verifier.Verify(context.Background(), key.sign(t, validClaims(), map[string]any{
+		"scp": []string{"urls:read"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "user1", principal.Name)
+	assert.Equal(t, []string{"urls:read"}, principal.Scopes)
+}
+
+// TestVerify_LongSubject is a test function that checks that a subject longer than an API key ID is kept whole,
+// prefixed with the issuer, while the name falls back to the bare subject.
+func TestVerify_LongSubject(t *testing.T) {
+	key := newSigningKey(t, "key1")
+	verifier := newFileVerifier(t, key)
+	claims := validClaims()
+	claims.Subject = "auth0|" + strings.Repeat("0123456789abcdef", 8)
+
+	principal, err := verifier.Verify(context.Background(), key.sign(t, claims, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "jwt:"+issuer+"|"+claims.Subject, principal.Subject)
+	assert.Equal(t, claims.Subject, principal.Name)
+}
+
+// TestVerifyRejects is a test function that checks that the tokens with a wrong signature or wrong claims are rejected.
+func TestVerifyRejects(t *testing.T) {
+	key := newSigningKey(t, "key1")
+	verifier := newFileVerifier(t, key)
+
+	claims := func(change func(*jwt.Claims)) jwt.Claims {
+		c := validClaims()
+		change(&c)
+		return c
+	}
+	tokens := map[string]string{
+		"wrong issuer":   key.sign(t, claims(func(c *jwt.Claims) { c.Issuer = "https://evil.example.com" }), nil),
+		"wrong audience": key.sign(t, claims(func(c *jwt.Claims) { c.Audience = jwt.Audience{"other"} }), nil),
+		"expired":        key.sign(t, claims(func(c *jwt.Claims) { c.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }), nil),
+		"no expiry":      key.sign(t, claims(func(c *jwt.Claims) { c.Expiry = nil }), nil),
+		"not yet valid":  key.sign(t, claims(func(c *jwt.Claims) { c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour)) }), nil),
+		"no subject":     key.sign(t, claims(func(c *jwt.Claims) { c.Subject = "" }), nil),
+		"unknown key":    newSigningKey(t, "key2").sign(t, validClaims(), nil),
+		"forged":         (&signingKey{kid: "key1", key: newSigningKey(t, "key1").key}).sign(t, validClaims(), nil),
+		"malformed":      "not.a.token",
+	}
+
+	hmac, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, nil)
+	require.NoError(t, err)
+	tokens["symmetric"], err = jwt.Signed(hmac).Claims(validClaims()).Serialize()
+	require.NoError(t, err)
+
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestRefresh is a test function that checks that the verifier fetches the JWKS from a URL, picks up a rotated key,
+// and keeps the current keys while the URL fails.
+func TestRefresh(t *testing.T) {
+	oldKey := newSigningKey(t, "old")
+	newKey := newSigningKey(t, "new")
+	var document atomic.Pointer[[]byte]
+	initial := keySet(t, oldKey)
+	document.Store(&initial)
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(*document.Load())
+	}))
+	t.Cleanup(server.Close)
+
+	verifier, err := bearer.NewVerifier(context.Background(), "", server.URL, issuer, audience, 10*time.Millisecond, 0)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go verifier.Run(ctx)
+
+	_, err = verifier.Verify(ctx, oldKey.sign(t, validClaims(), nil))
+	require.NoError(t, err)
+
+	failing.Store(true)
+	time.Sleep(50 * time.Millisecond)
+	_, err = verifier.Verify(ctx, oldKey.sign(t, validClaims(), nil))
+	require.NoError(t, err, "the keys loaded last are kept while the JWKS cannot be fetched")
+
+	rotated := keySet(t, newKey)
+	document.Store(&rotated)
+	failing.Store(false)
+	assert.Eventually(t, func() bool {
+		_, err := verifier.Verify(ctx, newKey.sign(t, validClaims(), nil))
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	_, err = verifier.Verify(ctx, oldKey.sign(t, validClaims(), nil))
+	assert.Error(t, err)
+}
+
+// TestNewVerifierFails is a test function that checks that a verifier is not created without a usable JWKS.
+func TestNewVerifierFails(t *testing.T) {
+	dir := t.TempDir()
+	_, err := bearer.NewVerifier(context.Background(), filepath.Join(dir, "missing.json"), "", issuer, audience, time.Minute, 0)
+	assert.Error(t, err)
+
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, []byte(`{"keys": []}`), 0o600))
+	_, err = bearer.NewVerifier(context.Background(), empty, "", issuer, audience, time.Minute, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Reloader is an interface that serves the TLS certificates of a server from files on disk
+// and picks the new ones up when the files are rotated, without restarting the server.
+type Reloader interface {
+	// TLSConfig is a method that returns the TLS configuration to serve with.
+	// Every handshake uses the certificates loaded last, so a rotation applies to the new connections
+	// while the established ones keep going.
+	TLSConfig() *tls.Config
+
+	// Run is a method that checks the files every interval and reloads them when they change, until the context is done.
+	// A rotation that cannot be loaded, like a key that does not match the certificate, is logged
+	// and the certificates loaded last stay in use.
+	Run(ctx context.Context)
+}
+
+// Ensure that the reloader struct implements the Reloader interface
+var _ Reloader = (*reloader)(nil)
+
+// reloader is a struct that implements the Reloader interface.
+type reloader struct {
+	certFile     string        // The path of the PEM certificate chain
+	keyFile      string        // The path of the PEM private key
+	clientCAFile string        // The path of the PEM CA bundle for the client certificates, empty to not ask for one
+	interval     time.Duration // The interval between two checks of the files
+
+	current atomic.Pointer[tls.Config] // The configuration built from the files loaded last
+	stamps  []fileStamp                // The state of the files when they were loaded last, only used by Run
+}
+
+// fileStamp is a struct that identifies the version of a file on disk.
+type fileStamp struct {
+	modTime time.Time // The modification time of the file
+	size    int64     // The size of the file
+}
+
+// NewReloader is a function that creates a new certificate reloader.
+// It takes the paths of the PEM certificate chain and private key of the server,
+// the path of the PEM bundle of the CAs to verify the client certificates with, empty to not ask clients for one,
+// and the interval between two checks of the files.
+// With a CA bundle, a client without a certificate signed by one of its CAs is refused, for mutual TLS.
+// It returns the reloader, and an error if the files cannot be loaded.
+func NewReloader(certFile, keyFile, clientCAFile string, interval time.Duration) (Reloader, error) {
+	r := &reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+		interval:     interval,
+	}
+	cfg, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(cfg)
+	r.stamps = r.stat()
+	logger.Info("TLS certificates loaded", certificateFields(cfg)...)
+	return r, nil
+}
+
+// TLSConfig is a method on the reloader struct.
+// It returns a configuration that hands every handshake the configuration built from the files loaded last.
+func (r *reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Run is a method on the reloader struct.
+// It compares the modification time and size of the files every interval and reloads them when one changed.
+func (r *reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reload()
+		}
+	}
+}
+
+// reload is a method on the reloader struct.
+// It loads the files again if they changed since they were loaded last, and logs the outcome.
+func (r *reloader) reload() {
+	stamps := r.stat()
+	if equalStamps(stamps, r.stamps) {
+		return
+	}
+	cfg, err := r.load()
+	if err != nil {
+		// The files may be caught in the middle of a rotation, the next check tries again
+		logger.Error("Failed to reload the TLS certificates, keeping the current ones", zap.Error(err))
+		return
+	}
+	r.current.Store(cfg)
+	r.stamps = stamps
+	logger.Info("TLS certificates reloaded", certificateFields(cfg)...)
+}
+
+// load is a method on the reloader struct.
+// It reads the certificate, the key and the CA bundle, and builds the configuration to serve with.
+// It returns the configuration, and an error if a file cannot be read or parsed.
+func (r *reloader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2"}, // gRPC runs over HTTP/2, the configuration returned per handshake must announce it
+	}
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("the client CA bundle holds no certificate")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// stat is a method on the reloader struct.
+// It returns the state of the files, with a zero stamp for a file that cannot be read.
+func (r *reloader) stat() []fileStamp {
+	files := []string{r.certFile, r.keyFile, r.clientCAFile}
+	stamps := make([]fileStamp, len(files))
+	for i, file := range files {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file) // Follows the symbolic links, like the ones of a Kubernetes secret volume
+		if err == nil {
+			stamps[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+// equalStamps is a function that reports whether two states of the files are the same.
+func equalStamps(a, b []fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
+
+// certificateFields is a function that returns the log fields describing the certificate of a configuration:
+// its subject and when it expires.
+func certificateFields(cfg *tls.Config) []zap.Field {
+	leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	if err != nil {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("subject", leaf.Subject.String()),
+		zap.Time("notAfter", leaf.NotAfter),
+		zap.Bool("clientAuth", cfg.ClientAuth == tls.RequireAndVerifyClientCert),
+	}
+}
@@ -0,0 +1,210 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/certs"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// authority is a struct that holds a certificate authority issuing the certificates of the tests.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newAuthority is a function that creates a self-signed certificate authority.
+func newAuthority(t *testing.T) *authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue is a method that issues a certificate for the common name and returns it and its key in PEM.
+func (a *authority) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile is a function that writes a file with a modification time that differs from the previous one,
+// so a rewrite within the resolution of the file system is noticed too.
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// handshake is a function that connects a client with the configuration to a server with the configuration over loopback.
+// It returns the common name of the certificate the server presented, and the error of the handshake of the server.
+func handshake(t *testing.T, server, client *tls.Config) (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	commonName := make(chan string, 1)
+	go func() {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), client)
+		if err != nil {
+			commonName <- ""
+			return
+		}
+		defer conn.Close()
+		commonName <- conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		_, _ = conn.Read(make([]byte, 1)) // Waits for the server to finish the handshake and close the connection
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	serverConn := tls.Server(conn, server)
+	err = serverConn.Handshake()
+	serverConn.Close()
+	return <-commonName, err
+}
+
+// TestReloader_Rotation is a test function that checks that the reloader serves the certificate from the files,
+// serves a rotated certificate after the next check, and keeps the current one when the rotated files are broken.
+func TestReloader_Rotation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ca := newAuthority(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	certPEM, keyPEM := ca.issue(t, "first", x509.ExtKeyUsageServerAuth)
+	modTime := time.Now().Add(-time.Minute)
+	writeFile(t, certFile, certPEM, modTime)
+	writeFile(t, keyFile, keyPEM, modTime)
+
+	reloader, err := certs.NewReloader(certFile, keyFile, "", 10*time.Millisecond)
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		reloader.Run(ctx)
+		close(done)
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+	client := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+	commonName, err := handshake(t, reloader.TLSConfig(), client)
+	require.NoError(t, err)
+	assert.Equal(t, "first", commonName)
+
+	certPEM, keyPEM = ca.issue(t, "second", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, modTime.Add(time.Second))
+	writeFile(t, keyFile, keyPEM, modTime.Add(time.Second))
+	assert.Eventually(t, func() bool {
+		commonName, err := handshake(t, reloader.TLSConfig(), client)
+		return err == nil && commonName == "second"
+	}, time.Second, 10*time.Millisecond)
+
+	// A key that does not match the certificate is not loaded
+	_, otherKey := ca.issue(t, "third", x509.ExtKeyUsageServerAuth)
+	writeFile(t, keyFile, otherKey, modTime.Add(2*time.Second))
+	time.Sleep(50 * time.Millisecond) // Let a few checks run
+	commonName, err = handshake(t, reloader.TLSConfig(), client)
+	require.NoError(t, err)
+	assert.Equal(t, "second", commonName)
+
+	cancel()
+	<-done
+}
+
+// TestReloader_ClientAuth is a test function that checks that with a client CA bundle
+// a client is only accepted with a certificate signed by one of its CAs.
+func TestReloader_ClientAuth(t *testing.T) {
+	ca := newAuthority(t)
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	certPEM, keyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+	writeFile(t, caFile, ca.pem, time.Now())
+
+	reloader, err := certs.NewReloader(certFile, keyFile, caFile, time.Minute)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+	_, err = handshake(t, reloader.TLSConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	assert.Error(t, err, "a client without a certificate is accepted")
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	_, err = handshake(t, reloader.TLSConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{clientCert}})
+	assert.NoError(t, err)
+
+	strangerCertPEM, strangerKeyPEM := newAuthority(t).issue(t, "stranger", x509.ExtKeyUsageClientAuth)
+	strangerCert, err := tls.X509KeyPair(strangerCertPEM, strangerKeyPEM)
+	require.NoError(t, err)
+	_, err = handshake(t, reloader.TLSConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{strangerCert}})
+	assert.Error(t, err, "a client with a certificate from another CA is accepted")
+}
+
+// TestNewReloader_Invalid is a test function that checks that the reloader cannot be created from missing or broken files.
+func TestNewReloader_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := certs.NewReloader(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key"), "", time.Minute)
+	assert.Error(t, err)
+
+	ca := newAuthority(t)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	certPEM, keyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+	writeFile(t, caFile, []byte("not a certificate"), time.Now())
+	_, err = certs.NewReloader(certFile, keyFile, caFile, time.Minute)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"net"
+	"strconv"
+)
+
+// AdminConfig is an interface that defines the methods required for the configuration of the admin servers.
+type AdminConfig interface {
+	// Address returns the address of the admin server as a string.
+	Address() string
+	// GRPCAddress returns the address of the admin gRPC server as a string.
+	GRPCAddress() string
+	// Token returns the token that guards the admin gRPC service,
+	// or an empty string if the service is disabled.
+	Token() string
+}
+
+// adminConfig is a struct that holds the host and ports for the admin servers, and the token of the admin gRPC service.
+type adminConfig struct {
+	host     string // host is the hostname of the admin servers.
+	port     int    // port is the port number on which the admin server is running.
+	grpcPort int    // grpcPort is the port number on which the admin gRPC server is running.
+	token    string // token is the token that guards the admin gRPC service.
+}
+
+// NewAdminConfig is a function that creates a new admin server configuration.
+// It takes the validated configuration of the application and reads the admin.host host, falling back to the host of the application,
+// the ports.admin and ports.adminGrpc ports, and the admin.token token from it.
+// It returns an AdminConfig interface.
+func NewAdminConfig(cfg *Config) AdminConfig {
+	host := cfg.Admin.Host
+	if len(host) == 0 {
+		host = cfg.Host
+	}
+
+	return &adminConfig{
+		host:     host,
+		port:     cfg.Ports.Admin,
+		grpcPort: cfg.Ports.AdminGRPC,
+		token:    cfg.Admin.Token,
+	}
+}
+
+// Address is a method on the adminConfig struct.
+// It returns the address of the admin server by joining the host and port.
+func (cfg *adminConfig) Address() string {
+	return net.JoinHostPort(cfg.host, strconv.Itoa(cfg.port))
+}
+
+// GRPCAddress is a method on the adminConfig struct.
+// It returns the address of the admin gRPC server by joining the host and the gRPC port.
+func (cfg *adminConfig) GRPCAddress() string {
+	return net.JoinHostPort(cfg.host, strconv.Itoa(cfg.grpcPort))
+}
+
+// Token is a method on the adminConfig struct. It returns the token that guards the admin gRPC service.
+func (cfg *adminConfig) Token() string {
+	return cfg.token
+}
@@ -0,0 +1,36 @@
+package config
+
+// AuthConfig is an interface that defines the methods required for the authentication configuration of the gRPC server.
+type AuthConfig interface {
+	// APIKeysEnabled returns whether the mutating RPCs require an API key.
+	APIKeysEnabled() bool
+	// AdminToken returns the token that guards the management of the API keys,
+	// or an empty string if the management is disabled.
+	AdminToken() string
+}
+
+// authConfig is a struct that holds the authentication configuration of the gRPC server.
+type authConfig struct {
+	apiKeysEnabled bool   // apiKeysEnabled is whether the mutating RPCs require an API key.
+	adminToken     string // adminToken is the token that guards the management of the API keys.
+}
+
+// NewAuthConfig is a function that creates a new authentication configuration of the gRPC server.
+// It takes the validated configuration of the application and reads auth.apiKey.enabled and auth.adminToken from it.
+// It returns an AuthConfig interface.
+func NewAuthConfig(cfg *Config) AuthConfig {
+	return &authConfig{
+		apiKeysEnabled: cfg.Auth.APIKey.Enabled,
+		adminToken:     cfg.Auth.AdminToken,
+	}
+}
+
+// APIKeysEnabled is a method on the authConfig struct. It returns whether the mutating RPCs require an API key.
+func (cfg *authConfig) APIKeysEnabled() bool {
+	return cfg.apiKeysEnabled
+}
+
+// AdminToken is a method on the authConfig struct. It returns the token that guards the management of the API keys.
+func (cfg *authConfig) AdminToken() string {
+	return cfg.adminToken
+}
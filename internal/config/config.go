@@ -7,36 +7,252 @@ import (
 
 // Config is a struct that holds the configuration for the application.
 // It includes the domain, environment files, logger configuration, application configuration, and port configuration.
+// It is loaded once at startup by LoadConfig, and handed to the parts of the application that need it.
 type Config struct {
-	Host         string   `mapstructure:"host"` // Domain is the domain name for the application.
-	PostgresHost string   `mapstructure:"postgresHost"`
-	RedisHost    string   `mapstructure:"redisHost"`
-	RedisDB      int      `mapstructure:"redisDB"`
-	EnvFiles     []string `mapstructure:"env-files"` // EnvFiles is a list of environment files to be loaded.
-	Logger       Logger   `mapstructure:"logger"`    // Logger is the logger configuration.
-	App          App      `mapstructure:"app"`       // App is the application configuration.
-	Ports        Ports    `mapstructure:"ports"`     // Ports is the port configuration.
+	Env           string     `mapstructure:"env"`           // Env is the environment the application runs in, dev or prod.
+	Host          string     `mapstructure:"host"`          // Host is the domain name for the application.
+	Postgres      Postgres   `mapstructure:"postgres"`      // Postgres is the PostgreSQL database configuration.
+	RedisHost     string     `mapstructure:"redisHost"`     // RedisHost is the host of the Redis server.
+	RedisDB       int        `mapstructure:"redisDB"`       // RedisDB is the number of the Redis database.
+	RedisPassword string     `mapstructure:"redisPassword"` // RedisPassword is the password of the Redis server, empty if it has none.
+	EnvFiles      []string   `mapstructure:"envFiles"`      // EnvFiles is a list of environment files to be loaded.
+	Jobs          Jobs       `mapstructure:"jobs"`          // Jobs is the background jobs configuration.
+	Migrations    Migrations `mapstructure:"migrations"`    // Migrations is the database migrations configuration.
+	Storage       Storage    `mapstructure:"storage"`       // Storage is the storage configuration.
+	Cache         Cache      `mapstructure:"cache"`         // Cache is the cache configuration.
+	GRPC          GRPC       `mapstructure:"grpc"`          // GRPC is the gRPC server configuration.
+	Admin         Admin      `mapstructure:"admin"`         // Admin is the admin server configuration.
+	Auth          Auth       `mapstructure:"auth"`          // Auth is the authentication configuration.
+	RateLimit     RateLimit  `mapstructure:"rateLimit"`     // RateLimit is the rate limit configuration.
+	Health        Health     `mapstructure:"health"`        // Health is the health check configuration.
+	Shutdown      Shutdown   `mapstructure:"shutdown"`      // Shutdown is the graceful shutdown configuration.
+	Tracing       Tracing    `mapstructure:"tracing"`       // Tracing is the tracing configuration.
+	Logger        Logger     `mapstructure:"logger"`        // Logger is the logger configuration.
+	App           App        `mapstructure:"app"`           // App is the application configuration.
+	Ports         Ports      `mapstructure:"ports"`         // Ports is the port configuration.
 }
 
-// Ports is a struct that holds the HTTP and gRPC port numbers.
+// Ports is a struct that holds the HTTP, gRPC, admin, admin gRPC and Redis port numbers.
 type Ports struct {
-	HTTP int `mapstructure:"http"` // HTTP is the HTTP port number.
-	GRPC int `mapstructure:"grpc"` // GRPC is the gRPC port number.
+	HTTP      int `mapstructure:"http"`      // HTTP is the HTTP port number.
+	GRPC      int `mapstructure:"grpc"`      // GRPC is the gRPC port number.
+	Admin     int `mapstructure:"admin"`     // Admin is the port number of the admin server.
+	AdminGRPC int `mapstructure:"adminGrpc"` // AdminGRPC is the port number of the admin gRPC server.
+	Redis     int `mapstructure:"redis"`     // Redis is the port number of the Redis server.
 }
 
-// Logger is a struct that holds the logger name and file syncer configuration.
+// Postgres is a struct that holds the PostgreSQL database configuration.
+type Postgres struct {
+	DSN         string           `mapstructure:"dsn"`         // DSN is a complete connection string that replaces the connection fields.
+	Host        string           `mapstructure:"host"`        // Host is the host of the database.
+	Port        int              `mapstructure:"port"`        // Port is the port of the database.
+	User        string           `mapstructure:"user"`        // User is the user to connect as.
+	Password    string           `mapstructure:"password"`    // Password is the password of the user.
+	DB          string           `mapstructure:"db"`          // DB is the name of the database.
+	SSLMode     string           `mapstructure:"sslmode"`     // SSLMode is the TLS mode of the connection.
+	SSLRootCert string           `mapstructure:"sslrootcert"` // SSLRootCert is the CA certificate to verify the server with.
+	SSLCert     string           `mapstructure:"sslcert"`     // SSLCert is the client certificate.
+	SSLKey      string           `mapstructure:"sslkey"`      // SSLKey is the key of the client certificate.
+	Pool        PostgresPool     `mapstructure:"pool"`        // Pool is the connection pool configuration.
+	Replicas    PostgresReplicas `mapstructure:"replicas"`    // Replicas is the read replica configuration.
+	Retry       PostgresRetry    `mapstructure:"retry"`       // Retry is the startup connection retry configuration.
+}
+
+// PostgresPool is a struct that holds the connection pool configuration of the PostgreSQL database.
+type PostgresPool struct {
+	MaxOpen         int `mapstructure:"maxOpen"`         // MaxOpen is the maximum number of open connections.
+	MaxIdle         int `mapstructure:"maxIdle"`         // MaxIdle is the maximum number of idle connections.
+	ConnMaxLifetime int `mapstructure:"connMaxLifetime"` // ConnMaxLifetime is the maximum lifetime of a connection in seconds.
+}
+
+// PostgresReplicas is a struct that holds the read replica configuration of the PostgreSQL database.
+type PostgresReplicas struct {
+	DSNs           []string `mapstructure:"dsns"`           // DSNs are the connection strings of the replicas.
+	HealthInterval int      `mapstructure:"healthInterval"` // HealthInterval is the interval between two health checks in seconds.
+	ReadAfterWrite int      `mapstructure:"readAfterWrite"` // ReadAfterWrite is how long a written URL is read from the primary in milliseconds.
+}
+
+// PostgresRetry is a struct that holds the startup connection retry configuration of the PostgreSQL database.
+type PostgresRetry struct {
+	Attempts        int `mapstructure:"attempts"`        // Attempts is the number of attempts before giving up, zero for no limit.
+	InitialInterval int `mapstructure:"initialInterval"` // InitialInterval is the delay before the first retry in milliseconds.
+	MaxInterval     int `mapstructure:"maxInterval"`     // MaxInterval is the maximum delay between two attempts in milliseconds.
+}
+
+// GRPC is a struct that holds the gRPC server configuration.
+type GRPC struct {
+	TLS TLS `mapstructure:"tls"` // TLS is the TLS configuration of the gRPC server.
+}
+
+// TLS is a struct that holds the TLS configuration of the gRPC server.
+type TLS struct {
+	Enabled        bool   `mapstructure:"enabled"`        // Enabled indicates whether the gRPC server serves TLS.
+	CertFile       string `mapstructure:"certFile"`       // CertFile is the path of the certificate chain of the server.
+	KeyFile        string `mapstructure:"keyFile"`        // KeyFile is the path of the private key of the server.
+	ClientCAFile   string `mapstructure:"clientCAFile"`   // ClientCAFile is the path of the CA bundle for the client certificates.
+	ReloadInterval int    `mapstructure:"reloadInterval"` // ReloadInterval is the interval between two checks of the files in seconds.
+}
+
+// Admin is a struct that holds the admin server configuration.
+type Admin struct {
+	Host  string `mapstructure:"host"`  // Host is the host the admin servers listen on, the host of the application if empty.
+	Token string `mapstructure:"token"` // Token is the token that guards the admin gRPC service.
+}
+
+// Auth is a struct that holds the authentication configuration of the gRPC server.
+type Auth struct {
+	APIKey     APIKey `mapstructure:"apiKey"`     // APIKey is the API key configuration.
+	JWT        JWT    `mapstructure:"jwt"`        // JWT is the bearer token configuration.
+	AdminToken string `mapstructure:"adminToken"` // AdminToken is the token that guards the management of the API keys.
+}
+
+// APIKey is a struct that holds the API key configuration.
+type APIKey struct {
+	Enabled bool `mapstructure:"enabled"` // Enabled indicates whether the mutating RPCs require an API key.
+}
+
+// JWT is a struct that holds the configuration of the bearer token authentication.
+type JWT struct {
+	Enabled         bool   `mapstructure:"enabled"`         // Enabled indicates whether the gRPC server accepts bearer tokens.
+	JWKSFile        string `mapstructure:"jwksFile"`        // JWKSFile is the path of the key set the tokens are verified with.
+	JWKSURL         string `mapstructure:"jwksURL"`         // JWKSURL is the URL of the key set the tokens are verified with.
+	RefreshInterval int    `mapstructure:"refreshInterval"` // RefreshInterval is the interval between two loads of the key set in seconds.
+	Issuer          string `mapstructure:"issuer"`          // Issuer is the issuer the tokens must name.
+	Audience        string `mapstructure:"audience"`        // Audience is the audience the tokens must name.
+	Leeway          int    `mapstructure:"leeway"`          // Leeway is the clock skew allowed when checking the times of the tokens in seconds.
+}
+
+// RateLimit is a struct that holds the configuration of the rate limits.
+type RateLimit struct {
+	Enabled           bool        `mapstructure:"enabled"`           // Enabled indicates whether the requests are rate limited.
+	Driver            string      `mapstructure:"driver"`            // Driver is where the buckets are kept, redis or local.
+	RetryInterval     int         `mapstructure:"retryInterval"`     // RetryInterval is how long Redis is skipped after it failed in seconds.
+	KeyBy             string      `mapstructure:"keyBy"`             // KeyBy is what the requests are counted by, apiKey, ip or tenant.
+	TrustForwardedFor bool        `mapstructure:"trustForwardedFor"` // TrustForwardedFor indicates whether the client address is read from x-forwarded-for.
+	Create            RateLimitOf `mapstructure:"create"`            // Create is the limit on creating URLs.
+	Resolve           RateLimitOf `mapstructure:"resolve"`           // Resolve is the limit on resolving URLs.
+}
+
+// RateLimitOf is a struct that holds the token bucket of a class of requests.
+type RateLimitOf struct {
+	Rate  float64 `mapstructure:"rate"`  // Rate is the number of requests per second.
+	Burst int     `mapstructure:"burst"` // Burst is the number of requests at once.
+}
+
+// Health is a struct that holds the health check configuration.
+type Health struct {
+	Interval int `mapstructure:"interval"` // Interval is the interval between two pings of the dependencies in seconds.
+	Timeout  int `mapstructure:"timeout"`  // Timeout is the timeout of a single ping in milliseconds.
+}
+
+// Shutdown is a struct that holds the graceful shutdown configuration.
+type Shutdown struct {
+	DrainTimeout int `mapstructure:"drainTimeout"` // DrainTimeout is how long the RPCs in progress may run after a stop signal in seconds.
+}
+
+// Tracing is a struct that holds the tracing configuration.
+type Tracing struct {
+	Exporter    string      `mapstructure:"exporter"`    // Exporter is where the spans go, none, otlp, stdout or file.
+	ServiceName string      `mapstructure:"serviceName"` // ServiceName is the name of the service in the traces.
+	SampleRatio float64     `mapstructure:"sampleRatio"` // SampleRatio is the share of the new traces that are recorded.
+	OTLP        TracingOTLP `mapstructure:"otlp"`        // OTLP is the configuration of the otlp exporter.
+	File        string      `mapstructure:"file"`        // File is the path the file exporter writes the spans to.
+}
+
+// TracingOTLP is a struct that holds the configuration of the OTLP exporter.
+type TracingOTLP struct {
+	Endpoint string `mapstructure:"endpoint"` // Endpoint is the host and port of the collector.
+	Insecure bool   `mapstructure:"insecure"` // Insecure indicates whether the connection to the collector is not encrypted.
+}
+
+// Jobs is a struct that holds the background jobs configuration.
+type Jobs struct {
+	Purge Purge `mapstructure:"purge"` // Purge is the purge job configuration.
+}
+
+// Purge is a struct that holds the configuration of the job that removes expired and deleted URLs.
+type Purge struct {
+	Enabled   bool `mapstructure:"enabled"`   // Enabled indicates whether the job runs.
+	Interval  int  `mapstructure:"interval"`  // Interval is the interval between two runs in seconds.
+	Retention int  `mapstructure:"retention"` // Retention is how long a deleted URL is kept in hours.
+	BatchSize int  `mapstructure:"batchSize"` // BatchSize is the number of rows removed by one statement.
+}
+
+// Migrations is a struct that holds the database migrations configuration.
+type Migrations struct {
+	Dir string `mapstructure:"dir"` // Dir overrides the embedded migrations with a directory holding one sub-directory per storage driver.
+}
+
+// Storage is a struct that holds the storage configuration.
+type Storage struct {
+	Driver    string `mapstructure:"driver"`    // Driver is the storage backend, postgres, sqlite or bolt.
+	Timeout   int    `mapstructure:"timeout"`   // Timeout is the timeout of a single database query in milliseconds.
+	SQLite    SQLite `mapstructure:"sqlite"`    // SQLite is the configuration of the sqlite driver.
+	Bolt      Bolt   `mapstructure:"bolt"`      // Bolt is the configuration of the bolt driver.
+	BackupDir string `mapstructure:"backupDir"` // BackupDir is the directory the backups are written to.
+}
+
+// SQLite is a struct that holds the configuration of the sqlite storage driver.
+type SQLite struct {
+	Path string `mapstructure:"path"` // Path is the path of the database file.
+}
+
+// Bolt is a struct that holds the configuration of the bolt storage driver.
+type Bolt struct {
+	Path string `mapstructure:"path"` // Path is the path of the database file.
+}
+
+// Cache is a struct that holds the cache configuration.
+type Cache struct {
+	Timeout      int          `mapstructure:"timeout"`      // Timeout is the timeout of a single cache operation in milliseconds.
+	Breaker      Breaker      `mapstructure:"breaker"`      // Breaker is the circuit breaker configuration.
+	Local        LocalCache   `mapstructure:"local"`        // Local is the in-process cache configuration.
+	Invalidation Invalidation `mapstructure:"invalidation"` // Invalidation is the cache invalidation configuration.
+}
+
+// LocalCache is a struct that holds the configuration of the in-process cache.
+type LocalCache struct {
+	Enabled bool `mapstructure:"enabled"` // Enabled indicates whether the in-process cache is used.
+	Size    int  `mapstructure:"size"`    // Size is the maximum number of entries.
+	TTL     int  `mapstructure:"ttl"`     // TTL is the maximum lifetime of an entry in seconds.
+}
+
+// Invalidation is a struct that holds the configuration of cache invalidation between instances.
+type Invalidation struct {
+	Driver  string `mapstructure:"driver"`  // Driver is the bus used for invalidation messages: redis, postgres or none.
+	Channel string `mapstructure:"channel"` // Channel is the channel the messages are published on.
+}
+
+// Breaker is a struct that holds the configuration of the circuit breaker around the cache.
+type Breaker struct {
+	FailureThreshold int `mapstructure:"failureThreshold"` // FailureThreshold is the number of consecutive failures that opens the circuit.
+	ProbeInterval    int `mapstructure:"probeInterval"`    // ProbeInterval is the interval between recovery probes in seconds.
+}
+
+// Logger is a struct that holds the logger name, level, encoding, outputs, sampling and file syncer configuration.
 type Logger struct {
-	Name       string     `mapstructure:"name"`       // Name is the name of the logger.
-	FileSyncer FileSyncer `mapstructure:"fileSyncer"` // FileSyncer is the file syncer configuration.
+	Name       string         `mapstructure:"name"`       // Name is the name of the logger.
+	Level      string         `mapstructure:"level"`      // Level is the lowest level logged, empty for the default of the environment.
+	Encoding   string         `mapstructure:"encoding"`   // Encoding is json or console, empty for the default of the environment.
+	Outputs    []string       `mapstructure:"outputs"`    // Outputs are where the logs are written, empty for the default of the environment.
+	Sampling   LoggerSampling `mapstructure:"sampling"`   // Sampling is the sampling of the repeated log entries.
+	FileSyncer FileSyncer     `mapstructure:"fileSyncer"` // FileSyncer is the file syncer configuration.
+}
+
+// LoggerSampling is a struct that holds how the repeated log entries are sampled.
+type LoggerSampling struct {
+	Enabled    bool `mapstructure:"enabled"`    // Enabled indicates whether the repeated log entries are sampled.
+	Initial    int  `mapstructure:"initial"`    // Initial is the number of entries with the same level and message logged every second.
+	Thereafter int  `mapstructure:"thereafter"` // Thereafter is the sampling rate after that, one entry in Thereafter is logged.
 }
 
 // FileSyncer is a struct that holds the file syncer configuration.
 type FileSyncer struct {
-	Filename   string `mapstructure:"Filename"`   // Filename is the name of the log file.
-	MaxSize    int    `mapstructure:"MaxSize"`    // MaxSize is the maximum size of the log file.
-	MaxBackups int    `mapstructure:"MaxBackups"` // MaxBackups is the maximum number of backup log files to keep.
-	Compress   bool   `mapstructure:"Compress"`   // Compress indicates whether to compress the log files.
-	MaxAge     int    `mapstructure:"MaxAge"`     // MaxAge is the maximum age of the log files.
+	Filename   string `mapstructure:"filename"`   // Filename is the name of the log file.
+	MaxSize    int    `mapstructure:"maxSize"`    // MaxSize is the maximum size of the log file in megabytes.
+	MaxBackups int    `mapstructure:"maxBackups"` // MaxBackups is the maximum number of backup log files to keep.
+	Compress   bool   `mapstructure:"compress"`   // Compress indicates whether to compress the log files.
+	MaxAge     int    `mapstructure:"maxAge"`     // MaxAge is the maximum age of the log files in days.
 }
 
 // App is a struct that holds the services configuration.
@@ -52,35 +268,71 @@ type Services struct {
 // Hash is a struct that holds the hash configuration.
 type Hash struct {
 	TTLCache  int    `mapstructure:"ttlCache"`  // TTLCache is the time-to-live for the cache.
+	LinkTTL   int    `mapstructure:"linkTTL"`   // LinkTTL is the default lifetime of a short URL in seconds, zero for no expiry.
 	MinLength int    `mapstructure:"minLength"` // MinLength is the minimum length of the hash.
 	Alphabet  string `mapstructure:"alphabet"`  // Alphabet is the set of characters to use in the hash.
 }
 
 // LoadConfig is a function that loads the configuration for the application.
-// It takes the path, name, and type of the configuration file as parameters and returns an error.
-// It sets the path, name, and type of the configuration file using the viper package.
-// It then automatically uses environment variables if set and replaces "." in environment variables with "_".
-// It reads the configuration file and unmarshals the read configuration into a Config struct.
-// If the read or unmarshal returns an error, LoadConfig returns the error.
-// If the read and unmarshal do not return an error, LoadConfig returns nil.
-func LoadConfig(configPath, configName, configType string) error {
+// It takes the path, name, and type of the configuration file as parameters.
+// It registers the default of every setting, so a setting missing from the file never silently becomes zero.
+// It then automatically uses environment variables if set and replaces "." in environment variables with "_",
+// so every setting can be overridden by its upper-cased path, like POSTGRES_PASSWORD for postgres.password.
+// The gRPC port can also be set by GRPC_PORT and the Redis password by REDIS_PASS, the names written by the dotenv scripts.
+// It reads the configuration file, loads the environment files listed in envFiles,
+// and unmarshals the settings into a Config struct, which it validates.
+// If the read, the load, the unmarshal or the validation returns an error, LoadConfig returns nil and the error.
+// Otherwise, LoadConfig returns the configuration and nil.
+func LoadConfig(configPath, configName, configType string) (*Config, error) {
 	viper.AddConfigPath(configPath) // Set the path for the config file
 	viper.SetConfigName(configName) // Set the name for the config file
 	viper.SetConfigType(configType) // Set the type for the config file
 
+	setDefaults() // Register the defaults of the settings
+
 	viper.AutomaticEnv()                                   // Automatically use environment variables if set
 	viper.SetEnvKeyReplacer(strings.NewReplacer(`.`, `_`)) // Replace "." in env vars with "_"
+	err := bindEnvAliases()                                // Accept the environment variables of the dotenv scripts
+	if err != nil {
+		return nil, err
+	}
 
 	// Read the config file
-	err := viper.ReadInConfig()
+	err = viper.ReadInConfig()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	// Load the environment files before the settings are read, so their variables override the file
+	err = LoadDotEnv(viper.GetStringSlice("envFiles")...)
+	if err != nil {
+		return nil, err
 	}
 	// Unmarshal the read config into a Config struct
-	var cfg *Config
-	err = viper.Unmarshal(&cfg)
+	cfg := &Config{}
+	err = viper.Unmarshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.Validate()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// bindEnvAliases is a function that binds the settings that can also be set by an environment variable
+// other than their upper-cased path. The upper-cased path is listed first, so it wins when both are set.
+// It returns an error if a binding fails.
+func bindEnvAliases() error {
+	aliases := map[string][]string{
+		"ports.grpc":    {"PORTS_GRPC", "GRPC_PORT"},
+		"redisPassword": {"REDISPASSWORD", "REDIS_PASS"},
+	}
+	for key, envs := range aliases {
+		err := viper.BindEnv(append([]string{key}, envs...)...)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,133 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// writeConfig is a function that writes a config.yml with the content to a temporary directory,
+// with an environment file next to it that the config lists in envFiles.
+// It resets viper when the test ends, and returns the directory.
+func writeConfig(t *testing.T, content, dotenv string) string {
+	t.Helper()
+	t.Cleanup(viper.Reset)
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "test.env")
+	require.NoError(t, os.WriteFile(envFile, []byte(dotenv), 0o600))
+	content = "envFiles:\n  - " + envFile + "\n" + content
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yml"), []byte(content), 0o600))
+	return dir
+}
+
+// TestLoadConfig_Defaults is a test function that checks that the settings left out of the file take their defaults.
+func TestLoadConfig_Defaults(t *testing.T) {
+	t.Setenv("ENV", "dev")
+	dir := writeConfig(t, "host: example.com\nstorage:\n  driver: sqlite\n", "")
+
+	cfg, err := config.LoadConfig(dir, "config", "yml")
+
+	require.NoError(t, err)
+	assert.Equal(t, "dev", cfg.Env)
+	assert.Equal(t, "example.com", cfg.Host)
+	assert.Equal(t, "sqlite", cfg.Storage.Driver)
+	assert.Equal(t, "data/shortify.db", cfg.Storage.SQLite.Path)
+	assert.Equal(t, 8001, cfg.Ports.GRPC)
+	assert.Equal(t, 3600, cfg.App.Services.Hash.TTLCache)
+	assert.Equal(t, 32, cfg.Logger.FileSyncer.MaxSize)
+	assert.Equal(t, "shortify_invalidate", cfg.Cache.Invalidation.Channel)
+}
+
+// TestLoadConfig_Environment is a test function that checks that the environment variables override the file,
+// including the legacy names of the dotenv scripts and the variables of the environment files.
+func TestLoadConfig_Environment(t *testing.T) {
+	t.Setenv("ENV", "prod")
+	t.Setenv("GRPC_PORT", "9001")
+	t.Setenv("CACHE_LOCAL_SIZE", "42")
+	t.Cleanup(func() { _ = os.Unsetenv("REDIS_PASS") })
+	dir := writeConfig(t, "storage:\n  driver: bolt\ncache:\n  local:\n    size: 7\n", "REDIS_PASS=from-dotenv\n")
+
+	cfg, err := config.LoadConfig(dir, "config", "yml")
+
+	require.NoError(t, err)
+	assert.Equal(t, 9001, cfg.Ports.GRPC)
+	assert.Equal(t, 42, cfg.Cache.Local.Size)
+	assert.Equal(t, "from-dotenv", cfg.RedisPassword)
+}
+
+// TestLoadConfig_Invalid is a test function that checks that LoadConfig reports every invalid setting at once.
+func TestLoadConfig_Invalid(t *testing.T) {
+	t.Setenv("ENV", "staging")
+	dir := writeConfig(t, "storage:\n  driver: mysql\nports:\n  http: 8001\ntracing:\n  sampleRatio: 2\nlogger:\n  outputs: [stdout, syslog]\n", "")
+
+	_, err := config.LoadConfig(dir, "config", "yml")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `env (the ENV environment variable) must be one of dev, development, prod, production, got "staging"`)
+	assert.Contains(t, err.Error(), `storage.driver must be one of postgres, sqlite, bolt, got "mysql"`)
+	assert.Contains(t, err.Error(), "ports.grpc must differ from ports.http, both are 8001")
+	assert.Contains(t, err.Error(), "tracing.sampleRatio must be from 0 to 1, got 2")
+	assert.Contains(t, err.Error(), `logger.outputs must be one of stdout, stderr, file, got "syslog"`)
+}
+
+// TestSettings is a test function that checks that the settings are keyed by their names in the configuration file.
+func TestSettings(t *testing.T) {
+	cfg := &config.Config{Ports: config.Ports{GRPC: 8001}}
+	cfg.RateLimit.Create.Burst = 20
+
+	settings := cfg.Settings()
+
+	assert.Equal(t, 8001, settings["ports"].(map[string]any)["grpc"])
+	assert.Equal(t, 20, settings["rateLimit"].(map[string]any)["create"].(map[string]any)["burst"])
+}
+
+// TestReloader is a test function that checks that a reload applies a change to the reloadable settings and calls the listeners,
+// and keeps the current configuration when the new one is invalid or changes a setting that needs a restart.
+func TestReloader(t *testing.T) {
+	t.Setenv("ENV", "dev")
+	dir := writeConfig(t, "storage:\n  driver: bolt\nrateLimit:\n  create:\n    rate: 1\n", "")
+	cfg, err := config.LoadConfig(dir, "config", "yml")
+	require.NoError(t, err)
+	reloader := config.NewReloader(cfg)
+	var reloaded *config.Config
+	reloader.OnReload(func(cfg *config.Config) { reloaded = cfg })
+	rewrite := func(content string) {
+		file := filepath.Join(dir, "config.yml")
+		previous, err := os.ReadFile(file)
+		require.NoError(t, err)
+		envFiles, _, _ := strings.Cut(string(previous), "storage:")
+		require.NoError(t, os.WriteFile(file, []byte(envFiles+content), 0o600))
+	}
+
+	rewrite("storage:\n  driver: bolt\nrateLimit:\n  create:\n    rate: 2\napp:\n  services:\n    hash:\n      ttlCache: 60\n")
+	require.NoError(t, reloader.Reload())
+	assert.Equal(t, 2.0, reloader.Current().RateLimit.Create.Rate)
+	assert.Equal(t, 60, reloader.Current().App.Services.Hash.TTLCache)
+	assert.Same(t, reloader.Current(), reloaded)
+	assert.Equal(t, 1.0, cfg.RateLimit.Create.Rate, "the configuration is swapped, not modified")
+
+	rewrite("storage:\n  driver: sqlite\nports:\n  grpc: 9001\nrateLimit:\n  create:\n    rate: 3\n")
+	err = reloader.Reload()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "restart to change ports.grpc, storage.driver")
+	assert.Equal(t, 2.0, reloader.Current().RateLimit.Create.Rate)
+
+	rewrite("storage:\n  driver: bolt\nrateLimit:\n  create:\n    rate: -1\n")
+	err = reloader.Reload()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rateLimit.create.rate must not be negative")
+	assert.Equal(t, 2.0, reloader.Current().RateLimit.Create.Rate)
+}
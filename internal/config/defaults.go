@@ -0,0 +1,129 @@
+package config
+
+import "github.com/spf13/viper"
+
+// defaults are the values of the settings that are missing from the configuration file and the environment.
+// They match config/config.yml, so a setting left out of a trimmed-down file never silently becomes zero.
+// Every setting of Config has a default, which also lets viper find the environment variable of every setting.
+var defaults = map[string]any{
+	"env":           "",
+	"host":          "localhost",
+	"redisHost":     "0.0.0.0",
+	"redisDB":       0,
+	"redisPassword": "",
+	"envFiles":      []string{".env"},
+
+	"ports.http":      8000,
+	"ports.grpc":      8001,
+	"ports.admin":     9090,
+	"ports.adminGrpc": 9091,
+	"ports.redis":     6379,
+
+	"postgres.dsn":                     "",
+	"postgres.host":                    "0.0.0.0",
+	"postgres.port":                    5432,
+	"postgres.user":                    "",
+	"postgres.password":                "",
+	"postgres.db":                      "",
+	"postgres.sslmode":                 "disable",
+	"postgres.sslrootcert":             "",
+	"postgres.sslcert":                 "",
+	"postgres.sslkey":                  "",
+	"postgres.pool.maxOpen":            20,
+	"postgres.pool.maxIdle":            10,
+	"postgres.pool.connMaxLifetime":    1800,
+	"postgres.replicas.dsns":           []string{},
+	"postgres.replicas.healthInterval": 5,
+	"postgres.replicas.readAfterWrite": 5000,
+	"postgres.retry.attempts":          10,
+	"postgres.retry.initialInterval":   500,
+	"postgres.retry.maxInterval":       10000,
+
+	"jobs.purge.enabled":   true,
+	"jobs.purge.interval":  300,
+	"jobs.purge.retention": 168,
+	"jobs.purge.batchSize": 1000,
+
+	"migrations.dir": "",
+
+	"storage.driver":      "postgres",
+	"storage.timeout":     3000,
+	"storage.sqlite.path": "data/shortify.db",
+	"storage.bolt.path":   "data/shortify.bolt",
+	"storage.backupDir":   "data/backups",
+
+	"cache.timeout":                  500,
+	"cache.breaker.failureThreshold": 5,
+	"cache.breaker.probeInterval":    5,
+	"cache.local.enabled":            true,
+	"cache.local.size":               10000,
+	"cache.local.ttl":                60,
+	"cache.invalidation.driver":      "redis",
+	"cache.invalidation.channel":     "shortify_invalidate",
+
+	"grpc.tls.enabled":        false,
+	"grpc.tls.certFile":       "",
+	"grpc.tls.keyFile":        "",
+	"grpc.tls.clientCAFile":   "",
+	"grpc.tls.reloadInterval": 60,
+
+	"admin.host":  "",
+	"admin.token": "",
+
+	"auth.apiKey.enabled":      true,
+	"auth.jwt.enabled":         false,
+	"auth.jwt.jwksFile":        "",
+	"auth.jwt.jwksURL":         "",
+	"auth.jwt.refreshInterval": 300,
+	"auth.jwt.issuer":          "",
+	"auth.jwt.audience":        "shortify",
+	"auth.jwt.leeway":          30,
+	"auth.adminToken":          "",
+
+	"rateLimit.enabled":           true,
+	"rateLimit.driver":            "redis",
+	"rateLimit.retryInterval":     5,
+	"rateLimit.keyBy":             "apiKey",
+	"rateLimit.trustForwardedFor": false,
+	"rateLimit.create.rate":       1.0,
+	"rateLimit.create.burst":      20,
+	"rateLimit.resolve.rate":      100.0,
+	"rateLimit.resolve.burst":     200,
+
+	"health.interval": 5,
+	"health.timeout":  1000,
+
+	"shutdown.drainTimeout": 25,
+
+	"tracing.exporter":      "none",
+	"tracing.serviceName":   "shortify",
+	"tracing.sampleRatio":   1.0,
+	"tracing.otlp.endpoint": "localhost:4317",
+	"tracing.otlp.insecure": true,
+	"tracing.file":          "logs/traces.json",
+
+	"logger.name":                  "Shortify",
+	"logger.level":                 "",
+	"logger.encoding":              "",
+	"logger.outputs":               []string{},
+	"logger.sampling.enabled":      false,
+	"logger.sampling.initial":      100,
+	"logger.sampling.thereafter":   100,
+	"logger.fileSyncer.filename":   "logs/stdout.log",
+	"logger.fileSyncer.maxSize":    32,
+	"logger.fileSyncer.maxBackups": 3,
+	"logger.fileSyncer.compress":   true,
+	"logger.fileSyncer.maxAge":     7,
+
+	"app.services.hash.ttlCache":  3600,
+	"app.services.hash.linkTTL":   0,
+	"app.services.hash.minLength": 10,
+	"app.services.hash.alphabet":  "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890_",
+}
+
+// setDefaults is a function that registers the defaults of the settings with viper.
+func setDefaults() {
+	for key, value := range defaults {
+		viper.SetDefault(key, value)
+	}
+}
@@ -1,10 +1,7 @@
 package config
 
 import (
-	"github.com/pkg/errors"
-	"github.com/spf13/viper"
 	"net"
-	"os"
 	"strconv"
 )
 
@@ -21,24 +18,13 @@ type grpcConfig struct {
 }
 
 // NewGRPCConfig is a function that creates a new gRPC configuration.
-// It reads the host and port from the environment variables using viper.
-// If the host is not found, it returns an error.
-// Otherwise, it returns a GRPCConfig interface and nil error.
-func NewGRPCConfig() (GRPCConfig, error) {
-	host := viper.GetString("host")
-	if len(host) == 0 {
-		return nil, errors.New("grpc host not found")
-	}
-
-	port, err := strconv.Atoi(os.Getenv("GRPC_PORT"))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to convert grpc port to int")
-	}
-
+// It takes the validated configuration of the application and reads the host and the ports.grpc port from it.
+// It returns a GRPCConfig interface.
+func NewGRPCConfig(cfg *Config) GRPCConfig {
 	return &grpcConfig{
-		host: host,
-		port: port,
-	}, nil
+		host: cfg.Host,
+		port: cfg.Ports.GRPC,
+	}
 }
 
 // Address is a method on the grpcConfig struct.
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"net"
+	"strconv"
+)
+
+// HTTPConfig is an interface that defines the methods required for an HTTP configuration.
+type HTTPConfig interface {
+	// Address returns the address of the HTTP server as a string.
+	Address() string
+}
+
+// httpConfig is a struct that holds the host and port for an HTTP server.
+type httpConfig struct {
+	host string // host is the hostname of the HTTP server.
+	port int    // port is the port number on which the HTTP server is running.
+}
+
+// NewHTTPConfig is a function that creates a new HTTP configuration.
+// It takes the validated configuration of the application and reads the host and the ports.http port from it.
+// It returns an HTTPConfig interface.
+func NewHTTPConfig(cfg *Config) HTTPConfig {
+	return &httpConfig{
+		host: cfg.Host,
+		port: cfg.Ports.HTTP,
+	}
+}
+
+// Address is a method on the httpConfig struct.
+// It returns the address of the HTTP server by joining the host and port.
+func (cfg *httpConfig) Address() string {
+	return net.JoinHostPort(cfg.host, strconv.Itoa(cfg.port))
+}
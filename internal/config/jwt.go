@@ -0,0 +1,84 @@
+package config
+
+import "time"
+
+// JWTConfig is an interface that defines the methods required for the configuration of the bearer token authentication.
+type JWTConfig interface {
+	// Enabled returns whether the gRPC server accepts bearer tokens.
+	Enabled() bool
+	// JWKSFile returns the path of the JSON Web Key Set the tokens are verified with, or an empty string if it is fetched from a URL.
+	JWKSFile() string
+	// JWKSURL returns the URL of the JSON Web Key Set the tokens are verified with, or an empty string if it is read from a file.
+	JWKSURL() string
+	// RefreshInterval returns the interval between two loads of the key set, so rotated keys are picked up.
+	RefreshInterval() time.Duration
+	// Issuer returns the issuer the tokens must name.
+	Issuer() string
+	// Audience returns the audience the tokens must name.
+	Audience() string
+	// Leeway returns the clock skew allowed when checking the times of the tokens.
+	Leeway() time.Duration
+}
+
+// jwtConfig is a struct that holds the configuration of the bearer token authentication.
+type jwtConfig struct {
+	enabled         bool          // enabled is whether the gRPC server accepts bearer tokens.
+	jwksFile        string        // jwksFile is the path of the key set.
+	jwksURL         string        // jwksURL is the URL of the key set.
+	refreshInterval time.Duration // refreshInterval is the interval between two loads of the key set.
+	issuer          string        // issuer is the issuer the tokens must name.
+	audience        string        // audience is the audience the tokens must name.
+	leeway          time.Duration // leeway is the clock skew allowed when checking the times of the tokens.
+}
+
+// NewJWTConfig is a function that creates a new configuration of the bearer token authentication.
+// It takes the validated configuration of the application and reads auth.jwt.enabled, auth.jwt.jwksFile, auth.jwt.jwksURL,
+// auth.jwt.refreshInterval in seconds, auth.jwt.issuer, auth.jwt.audience and auth.jwt.leeway in seconds from it.
+// It returns a JWTConfig interface.
+func NewJWTConfig(cfg *Config) JWTConfig {
+	jwt := cfg.Auth.JWT
+	return &jwtConfig{
+		enabled:         jwt.Enabled,
+		jwksFile:        jwt.JWKSFile,
+		jwksURL:         jwt.JWKSURL,
+		refreshInterval: time.Duration(jwt.RefreshInterval) * time.Second,
+		issuer:          jwt.Issuer,
+		audience:        jwt.Audience,
+		leeway:          time.Duration(jwt.Leeway) * time.Second,
+	}
+}
+
+// Enabled is a method on the jwtConfig struct. It returns whether the gRPC server accepts bearer tokens.
+func (cfg *jwtConfig) Enabled() bool {
+	return cfg.enabled
+}
+
+// JWKSFile is a method on the jwtConfig struct. It returns the path of the key set.
+func (cfg *jwtConfig) JWKSFile() string {
+	return cfg.jwksFile
+}
+
+// JWKSURL is a method on the jwtConfig struct. It returns the URL of the key set.
+func (cfg *jwtConfig) JWKSURL() string {
+	return cfg.jwksURL
+}
+
+// RefreshInterval is a method on the jwtConfig struct. It returns the interval between two loads of the key set.
+func (cfg *jwtConfig) RefreshInterval() time.Duration {
+	return cfg.refreshInterval
+}
+
+// Issuer is a method on the jwtConfig struct. It returns the issuer the tokens must name.
+func (cfg *jwtConfig) Issuer() string {
+	return cfg.issuer
+}
+
+// Audience is a method on the jwtConfig struct. It returns the audience the tokens must name.
+func (cfg *jwtConfig) Audience() string {
+	return cfg.audience
+}
+
+// Leeway is a method on the jwtConfig struct. It returns the clock skew allowed when checking the times of the tokens.
+func (cfg *jwtConfig) Leeway() time.Duration {
+	return cfg.leeway
+}
@@ -0,0 +1,29 @@
+package config
+
+import "github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+
+// NewLoggerOptions is a function that creates the options of the logger.
+// It takes the validated configuration of the application and reads logger.name, logger.level, logger.encoding,
+// logger.outputs, logger.sampling, and the log file of logger.fileSyncer from it.
+// It returns the options, whose empty values the logger replaces with the defaults of the environment.
+func NewLoggerOptions(cfg *Config) logger.Options {
+	l := cfg.Logger
+	return logger.Options{
+		Name:     l.Name,
+		Level:    l.Level,
+		Encoding: l.Encoding,
+		Outputs:  l.Outputs,
+		Sampling: logger.Sampling{
+			Enabled:    l.Sampling.Enabled,
+			Initial:    l.Sampling.Initial,
+			Thereafter: l.Sampling.Thereafter,
+		},
+		File: logger.File{
+			Filename:   l.FileSyncer.Filename,
+			MaxSize:    l.FileSyncer.MaxSize,
+			MaxBackups: l.FileSyncer.MaxBackups,
+			Compress:   l.FileSyncer.Compress,
+			MaxAge:     l.FileSyncer.MaxAge,
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"time"
+)
+
+// RateLimitConfig is an interface that defines the methods required for the configuration of the rate limits.
+type RateLimitConfig interface {
+	// Enabled returns whether the requests are rate limited.
+	Enabled() bool
+	// Driver returns where the buckets are kept: redis, shared by the instances, or local, per instance.
+	Driver() string
+	// RetryInterval returns how long Redis is skipped after it failed, while the buckets are kept per instance.
+	RetryInterval() time.Duration
+	// Policy returns how the clients are told apart.
+	Policy() ratelimit.Policy
+	// Create returns the limit of every client on creating URLs.
+	Create() ratelimit.Limit
+	// Resolve returns the limit of every client on resolving URLs.
+	Resolve() ratelimit.Limit
+}
+
+// rateLimitConfig is a struct that holds the configuration of the rate limits.
+type rateLimitConfig struct {
+	enabled       bool             // enabled is whether the requests are rate limited.
+	driver        string           // driver is where the buckets are kept.
+	retryInterval time.Duration    // retryInterval is how long Redis is skipped after it failed.
+	policy        ratelimit.Policy // policy is how the clients are told apart.
+	create        ratelimit.Limit  // create is the limit on creating URLs.
+	resolve       ratelimit.Limit  // resolve is the limit on resolving URLs.
+}
+
+// NewRateLimitConfig is a function that creates a new configuration of the rate limits.
+// It takes the validated configuration of the application and reads rateLimit.enabled, rateLimit.driver,
+// rateLimit.retryInterval in seconds, rateLimit.keyBy, rateLimit.trustForwardedFor,
+// and the rate per second and burst of rateLimit.create and rateLimit.resolve from it.
+// It returns a RateLimitConfig interface.
+func NewRateLimitConfig(cfg *Config) RateLimitConfig {
+	rl := cfg.RateLimit
+	return &rateLimitConfig{
+		enabled:       rl.Enabled,
+		driver:        rl.Driver,
+		retryInterval: time.Duration(rl.RetryInterval) * time.Second,
+		policy: ratelimit.Policy{
+			KeyBy:             ratelimit.KeyBy(rl.KeyBy),
+			TrustForwardedFor: rl.TrustForwardedFor,
+		},
+		create:  ratelimit.Limit{Rate: rl.Create.Rate, Burst: rl.Create.Burst},
+		resolve: ratelimit.Limit{Rate: rl.Resolve.Rate, Burst: rl.Resolve.Burst},
+	}
+}
+
+// Enabled is a method on the rateLimitConfig struct. It returns whether the requests are rate limited.
+func (cfg *rateLimitConfig) Enabled() bool {
+	return cfg.enabled
+}
+
+// Driver is a method on the rateLimitConfig struct. It returns where the buckets are kept.
+func (cfg *rateLimitConfig) Driver() string {
+	return cfg.driver
+}
+
+// RetryInterval is a method on the rateLimitConfig struct. It returns how long Redis is skipped after it failed.
+func (cfg *rateLimitConfig) RetryInterval() time.Duration {
+	return cfg.retryInterval
+}
+
+// Policy is a method on the rateLimitConfig struct. It returns how the clients are told apart.
+func (cfg *rateLimitConfig) Policy() ratelimit.Policy {
+	return cfg.policy
+}
+
+// Create is a method on the rateLimitConfig struct. It returns the limit on creating URLs.
+func (cfg *rateLimitConfig) Create() ratelimit.Limit {
+	return cfg.create
+}
+
+// Resolve is a method on the rateLimitConfig struct. It returns the limit on resolving URLs.
+func (cfg *rateLimitConfig) Resolve() ratelimit.Limit {
+	return cfg.resolve
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// reloadable are the settings, or the sections of settings, that can change while the application runs.
+// A change to any other setting, like a port or the storage driver, needs a restart.
+var reloadable = []string{
+	"app.services.hash.ttlCache",
+	"rateLimit.create",
+	"rateLimit.resolve",
+}
+
+// Reloader is a struct that holds the current configuration of the application and replaces it when the configuration file changes.
+// Only the reloadable settings may change; a new configuration that is invalid or changes any other setting is rejected as a whole.
+type Reloader struct {
+	current   atomic.Pointer[Config] // The current configuration
+	m         sync.Mutex             // Serializes the reloads and the registration of the listeners
+	listeners []func(cfg *Config)    // The functions called with every new configuration
+}
+
+// NewReloader is a function that creates a new reloader holding the configuration loaded at startup.
+// It returns a pointer to the Reloader.
+func NewReloader(cfg *Config) *Reloader {
+	r := &Reloader{}
+	r.current.Store(cfg)
+	return r
+}
+
+// Current is a method on the Reloader struct. It returns the current configuration, which must not be modified.
+// It is safe to call while a reload is applied; the caller gets either the old or the new configuration as a whole.
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// OnReload is a method on the Reloader struct. It registers a function that is called with every new configuration,
+// after it replaced the current one, so the parts of the application that copied a reloadable setting can update it.
+func (r *Reloader) OnReload(fn func(cfg *Config)) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Watch is a method on the Reloader struct. It watches the configuration file and reloads it whenever it is written.
+// A rejected reload is logged and the current configuration is kept.
+func (r *Reloader) Watch() {
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		logger.Info("Configuration file changed, reloading it...", zap.String("file", event.Name))
+		err := r.Reload()
+		if err != nil {
+			logger.Error("Configuration was not reloaded", zap.Error(err))
+		}
+	})
+	viper.WatchConfig()
+	logger.Info("Watching the configuration file for changes", zap.String("file", viper.ConfigFileUsed()), zap.Strings("reloadable", reloadable))
+}
+
+// Reload is a method on the Reloader struct. It reads the configuration file again and replaces the current configuration with it.
+// The environment variables still override the file, as they do on startup.
+// It returns an error and keeps the current configuration if the file cannot be read, the new configuration is invalid,
+// or a setting that needs a restart changed.
+// Otherwise it swaps the configuration, logs every setting that changed with its old and new value, and calls the listeners.
+func (r *Reloader) Reload() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	err := viper.ReadInConfig()
+	if err != nil {
+		return err
+	}
+	next := &Config{}
+	err = viper.Unmarshal(next)
+	if err != nil {
+		return err
+	}
+	err = next.Validate()
+	if err != nil {
+		return err
+	}
+
+	changes := diff(r.Current().Settings(), next.Settings())
+	var restart []string
+	for _, change := range changes {
+		if !isReloadable(change.key) {
+			restart = append(restart, change.key)
+		}
+	}
+	if len(restart) > 0 {
+		return errors.Errorf("restart to change %s", strings.Join(restart, ", "))
+	}
+	if len(changes) == 0 {
+		logger.Info("Configuration unchanged")
+		return nil
+	}
+
+	r.current.Store(next)
+	described := make([]string, 0, len(changes))
+	for _, change := range changes {
+		described = append(described, change.String())
+	}
+	logger.Info("Configuration reloaded", zap.Strings("changes", described))
+	for _, fn := range r.listeners {
+		fn(next)
+	}
+	return nil
+}
+
+// change is a struct that holds a setting whose value changed, by its path like "rateLimit.create.rate".
+type change struct {
+	key           string // The path of the setting
+	before, after any    // The values of the setting before and after the change
+}
+
+// String is a method on the change struct. It describes the change like "rateLimit.create.rate: 1 -> 2".
+func (c change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.key, c.before, c.after)
+}
+
+// diff is a function that returns the settings whose values differ between the settings before and after a change, sorted by their paths.
+func diff(before, after map[string]any) []change {
+	beforeValues, afterValues := map[string]any{}, map[string]any{}
+	flatten("", before, beforeValues)
+	flatten("", after, afterValues)
+	var changes []change
+	for key, value := range afterValues {
+		if !reflect.DeepEqual(beforeValues[key], value) {
+			changes = append(changes, change{key: key, before: beforeValues[key], after: value})
+		}
+	}
+	slices.SortFunc(changes, func(a, b change) int { return strings.Compare(a.key, b.key) })
+	return changes
+}
+
+// flatten is a function that copies the nested settings into out, keyed by their paths joined by dots and starting with the prefix.
+func flatten(prefix string, settings map[string]any, out map[string]any) {
+	for name, value := range settings {
+		if section, ok := value.(map[string]any); ok {
+			flatten(prefix+name+".", section, out)
+			continue
+		}
+		out[prefix+name] = value
+	}
+}
+
+// isReloadable is a function that reports whether the setting at key is one of the reloadable settings or in one of their sections.
+func isReloadable(key string) bool {
+	for _, r := range reloadable {
+		if key == r || strings.HasPrefix(key, r+".") {
+			return true
+		}
+	}
+	return false
+}
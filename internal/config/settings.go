@@ -0,0 +1,27 @@
+package config
+
+import (
+	"reflect"
+)
+
+// Settings is a method on the Config struct that returns the settings by section, keyed by their names in the configuration file,
+// like {"ports": {"grpc": 8001}}. Lists are kept as they are.
+func (c *Config) Settings() map[string]any {
+	return settings(reflect.ValueOf(c).Elem())
+}
+
+// settings is a function that returns the fields of a struct as a map keyed by their mapstructure tags,
+// with the struct fields turned into maps recursively.
+func settings(v reflect.Value) map[string]any {
+	out := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("mapstructure")
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			out[name] = settings(field)
+			continue
+		}
+		out[name] = field.Interface()
+	}
+	return out
+}
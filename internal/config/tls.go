@@ -0,0 +1,67 @@
+package config
+
+import "time"
+
+// TLSConfig is an interface that defines the methods required for the TLS configuration of the gRPC server.
+type TLSConfig interface {
+	// Enabled returns whether the gRPC server serves TLS instead of plaintext.
+	Enabled() bool
+	// CertFile returns the path of the PEM certificate chain of the server.
+	CertFile() string
+	// KeyFile returns the path of the PEM private key of the server.
+	KeyFile() string
+	// ClientCAFile returns the path of the PEM bundle of the CAs the client certificates are verified with,
+	// or an empty string if the clients are not asked for a certificate.
+	ClientCAFile() string
+	// ReloadInterval returns the interval between two checks of the files for a rotated certificate.
+	ReloadInterval() time.Duration
+}
+
+// tlsConfig is a struct that holds the TLS configuration of the gRPC server.
+type tlsConfig struct {
+	enabled        bool          // enabled is whether the gRPC server serves TLS.
+	certFile       string        // certFile is the path of the certificate chain of the server.
+	keyFile        string        // keyFile is the path of the private key of the server.
+	clientCAFile   string        // clientCAFile is the path of the CA bundle for the client certificates.
+	reloadInterval time.Duration // reloadInterval is the interval between two checks of the files.
+}
+
+// NewTLSConfig is a function that creates a new TLS configuration of the gRPC server.
+// It takes the validated configuration of the application and reads grpc.tls.enabled, grpc.tls.certFile, grpc.tls.keyFile,
+// grpc.tls.clientCAFile and grpc.tls.reloadInterval in seconds from it.
+// It returns a TLSConfig interface.
+func NewTLSConfig(cfg *Config) TLSConfig {
+	tls := cfg.GRPC.TLS
+	return &tlsConfig{
+		enabled:        tls.Enabled,
+		certFile:       tls.CertFile,
+		keyFile:        tls.KeyFile,
+		clientCAFile:   tls.ClientCAFile,
+		reloadInterval: time.Duration(tls.ReloadInterval) * time.Second,
+	}
+}
+
+// Enabled is a method on the tlsConfig struct. It returns whether the gRPC server serves TLS.
+func (cfg *tlsConfig) Enabled() bool {
+	return cfg.enabled
+}
+
+// CertFile is a method on the tlsConfig struct. It returns the path of the certificate chain of the server.
+func (cfg *tlsConfig) CertFile() string {
+	return cfg.certFile
+}
+
+// KeyFile is a method on the tlsConfig struct. It returns the path of the private key of the server.
+func (cfg *tlsConfig) KeyFile() string {
+	return cfg.keyFile
+}
+
+// ClientCAFile is a method on the tlsConfig struct. It returns the path of the CA bundle for the client certificates.
+func (cfg *tlsConfig) ClientCAFile() string {
+	return cfg.clientCAFile
+}
+
+// ReloadInterval is a method on the tlsConfig struct. It returns the interval between two checks of the files.
+func (cfg *tlsConfig) ReloadInterval() time.Duration {
+	return cfg.reloadInterval
+}
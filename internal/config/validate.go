@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/speps/go-hashids"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"slices"
+	"strings"
+)
+
+// minAdminTokenLength is the minimum length of the admin tokens, so a short token cannot be guessed.
+const minAdminTokenLength = 16
+
+// problems is a list of the settings that are invalid, each described by the path of the setting and what is wrong with it.
+type problems []string
+
+// addf is a method on the problems type. It adds a problem formatted with the arguments.
+func (p *problems) addf(format string, args ...any) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+// port is a method on the problems type. It adds a problem if the port of the setting at key is not a TCP port.
+func (p *problems) port(key string, port int) {
+	if port < 1 || port > 65535 {
+		p.addf("%s must be a port from 1 to 65535, got %d", key, port)
+	}
+}
+
+// positive is a method on the problems type. It adds a problem if the value of the setting at key is not above zero.
+func (p *problems) positive(key string, value int) {
+	if value <= 0 {
+		p.addf("%s must be greater than 0, got %d", key, value)
+	}
+}
+
+// notNegative is a method on the problems type. It adds a problem if the value of the setting at key is below zero.
+func (p *problems) notNegative(key string, value int) {
+	if value < 0 {
+		p.addf("%s must not be negative, got %d", key, value)
+	}
+}
+
+// oneOf is a method on the problems type. It adds a problem if the value of the setting at key is not one of the allowed values.
+func (p *problems) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	p.addf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value)
+}
+
+// required is a method on the problems type. It adds a problem if the value of the setting at key is empty.
+func (p *problems) required(key, value, reason string) {
+	if len(value) == 0 {
+		p.addf("%s is required %s", key, reason)
+	}
+}
+
+// Validate is a method on the Config struct that checks the settings the application cannot run with,
+// so a mistake is reported at startup rather than when the setting is first used.
+// It checks the required settings, the ranges of the numbers, and the values of the settings that pick one of a few options.
+// It returns an error that lists every invalid setting by its path, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var p problems
+
+	p.oneOf("env (the ENV environment variable)", c.Env, "dev", "development", "prod", "production")
+	p.required("host", c.Host, "to serve and build the short URLs")
+	p.port("ports.http", c.Ports.HTTP)
+	p.port("ports.grpc", c.Ports.GRPC)
+	p.port("ports.admin", c.Ports.Admin)
+	p.port("ports.adminGrpc", c.Ports.AdminGRPC)
+	p.port("ports.redis", c.Ports.Redis)
+	seen := map[int]string{}
+	for _, port := range []struct {
+		key   string
+		value int
+	}{{"ports.http", c.Ports.HTTP}, {"ports.grpc", c.Ports.GRPC}, {"ports.admin", c.Ports.Admin}, {"ports.adminGrpc", c.Ports.AdminGRPC}} {
+		if other, ok := seen[port.value]; ok {
+			p.addf("%s must differ from %s, both are %d", port.key, other, port.value)
+		}
+		seen[port.value] = port.key
+	}
+	p.notNegative("redisDB", c.RedisDB)
+
+	p.oneOf("storage.driver", c.Storage.Driver, "postgres", "sqlite", "bolt")
+	p.notNegative("storage.timeout", c.Storage.Timeout)
+	switch c.Storage.Driver {
+	case "postgres":
+		c.Postgres.validate(&p)
+	case "sqlite":
+		p.required("storage.sqlite.path", c.Storage.SQLite.Path, "by the sqlite storage")
+	case "bolt":
+		p.required("storage.bolt.path", c.Storage.Bolt.Path, "by the bolt storage")
+	}
+
+	if c.Jobs.Purge.Enabled {
+		p.positive("jobs.purge.interval", c.Jobs.Purge.Interval)
+		p.notNegative("jobs.purge.retention", c.Jobs.Purge.Retention)
+		p.positive("jobs.purge.batchSize", c.Jobs.Purge.BatchSize)
+	}
+
+	p.notNegative("cache.timeout", c.Cache.Timeout)
+	p.positive("cache.breaker.failureThreshold", c.Cache.Breaker.FailureThreshold)
+	p.positive("cache.breaker.probeInterval", c.Cache.Breaker.ProbeInterval)
+	if c.Cache.Local.Enabled {
+		p.positive("cache.local.size", c.Cache.Local.Size)
+		p.positive("cache.local.ttl", c.Cache.Local.TTL)
+	}
+	p.oneOf("cache.invalidation.driver", c.Cache.Invalidation.Driver, "redis", "postgres", "none")
+	if c.Cache.Invalidation.Driver != "none" {
+		p.required("cache.invalidation.channel", c.Cache.Invalidation.Channel, "by the invalidation bus")
+	}
+
+	if c.GRPC.TLS.Enabled {
+		p.required("grpc.tls.certFile", c.GRPC.TLS.CertFile, "when grpc.tls.enabled is set")
+		p.required("grpc.tls.keyFile", c.GRPC.TLS.KeyFile, "when grpc.tls.enabled is set")
+	}
+	p.positive("grpc.tls.reloadInterval", c.GRPC.TLS.ReloadInterval)
+
+	for _, token := range []struct{ key, value string }{{"admin.token", c.Admin.Token}, {"auth.adminToken", c.Auth.AdminToken}} {
+		if len(token.value) > 0 && len(token.value) < minAdminTokenLength {
+			p.addf("%s must be at least %d characters long", token.key, minAdminTokenLength)
+		}
+	}
+
+	if c.Auth.JWT.Enabled {
+		if (len(c.Auth.JWT.JWKSFile) == 0) == (len(c.Auth.JWT.JWKSURL) == 0) {
+			p.addf("auth.jwt needs exactly one of jwksFile and jwksURL when auth.jwt.enabled is set")
+		}
+		p.required("auth.jwt.issuer", c.Auth.JWT.Issuer, "when auth.jwt.enabled is set")
+		p.required("auth.jwt.audience", c.Auth.JWT.Audience, "when auth.jwt.enabled is set")
+	}
+	p.positive("auth.jwt.refreshInterval", c.Auth.JWT.RefreshInterval)
+	p.notNegative("auth.jwt.leeway", c.Auth.JWT.Leeway)
+
+	p.oneOf("rateLimit.driver", c.RateLimit.Driver, "redis", "local")
+	if !ratelimit.KeyBy(c.RateLimit.KeyBy).Valid() {
+		p.addf("rateLimit.keyBy must be one of apiKey, ip, tenant, got %q", c.RateLimit.KeyBy)
+	}
+	p.positive("rateLimit.retryInterval", c.RateLimit.RetryInterval)
+	for _, limit := range []struct {
+		name string
+		RateLimitOf
+	}{{"create", c.RateLimit.Create}, {"resolve", c.RateLimit.Resolve}} {
+		if limit.Rate < 0 {
+			p.addf("rateLimit.%s.rate must not be negative, got %g", limit.name, limit.Rate)
+		}
+		p.notNegative("rateLimit."+limit.name+".burst", limit.Burst)
+	}
+
+	p.positive("health.interval", c.Health.Interval)
+	p.positive("health.timeout", c.Health.Timeout)
+	p.positive("shutdown.drainTimeout", c.Shutdown.DrainTimeout)
+
+	p.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "otlp", "stdout", "file")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		p.addf("tracing.sampleRatio must be from 0 to 1, got %g", c.Tracing.SampleRatio)
+	}
+	switch c.Tracing.Exporter {
+	case "otlp":
+		p.required("tracing.otlp.endpoint", c.Tracing.OTLP.Endpoint, "by the otlp exporter")
+	case "file":
+		p.required("tracing.file", c.Tracing.File, "by the file exporter")
+	}
+
+	p.oneOf("logger.level", c.Logger.Level, "", "debug", "info", "warn", "error")
+	p.oneOf("logger.encoding", c.Logger.Encoding, "", logger.EncodingJSON, logger.EncodingConsole)
+	for _, output := range c.Logger.Outputs {
+		p.oneOf("logger.outputs", output, logger.OutputStdout, logger.OutputStderr, logger.OutputFile)
+	}
+	if c.Logger.Sampling.Enabled {
+		p.positive("logger.sampling.initial", c.Logger.Sampling.Initial)
+		p.positive("logger.sampling.thereafter", c.Logger.Sampling.Thereafter)
+	}
+	if slices.Contains(c.Logger.Outputs, logger.OutputFile) || (len(c.Logger.Outputs) == 0 && (c.Env == "prod" || c.Env == "production")) {
+		p.required("logger.fileSyncer.filename", c.Logger.FileSyncer.Filename, "to write the logs to a file")
+	}
+	p.positive("logger.fileSyncer.maxSize", c.Logger.FileSyncer.MaxSize)
+	p.notNegative("logger.fileSyncer.maxBackups", c.Logger.FileSyncer.MaxBackups)
+	p.notNegative("logger.fileSyncer.maxAge", c.Logger.FileSyncer.MaxAge)
+
+	hash := c.App.Services.Hash
+	p.positive("app.services.hash.ttlCache", hash.TTLCache)
+	p.notNegative("app.services.hash.linkTTL", hash.LinkTTL)
+	p.notNegative("app.services.hash.minLength", hash.MinLength)
+	hd := hashids.NewData()
+	hd.Alphabet = hash.Alphabet
+	if _, err := hashids.NewWithData(hd); err != nil {
+		p.addf("app.services.hash.alphabet is invalid: %s", err)
+	}
+
+	if len(p) > 0 {
+		return errors.Errorf("invalid configuration: %s", strings.Join(p, "; "))
+	}
+	return nil
+}
+
+// validate is a method on the Postgres struct that checks the settings of the postgres storage.
+// The connection settings are only checked when no complete connection string is set.
+func (pg *Postgres) validate(p *problems) {
+	if len(pg.DSN) == 0 {
+		p.required("postgres.host", pg.Host, "by the postgres storage unless postgres.dsn is set")
+		p.port("postgres.port", pg.Port)
+		p.oneOf("postgres.sslmode", pg.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	}
+	p.notNegative("postgres.pool.maxOpen", pg.Pool.MaxOpen)
+	p.notNegative("postgres.pool.maxIdle", pg.Pool.MaxIdle)
+	p.notNegative("postgres.pool.connMaxLifetime", pg.Pool.ConnMaxLifetime)
+	p.positive("postgres.replicas.healthInterval", pg.Replicas.HealthInterval)
+	p.notNegative("postgres.replicas.readAfterWrite", pg.Replicas.ReadAfterWrite)
+	p.notNegative("postgres.retry.attempts", pg.Retry.Attempts)
+	p.positive("postgres.retry.initialInterval", pg.Retry.InitialInterval)
+	p.positive("postgres.retry.maxInterval", pg.Retry.MaxInterval)
+}
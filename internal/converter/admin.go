@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"encoding/json"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/admin_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// ToStatsFromService is a function that converts the runtime statistics of an instance to a StatsResponse protobuf message.
+// It takes a pointer to a statistics model as a parameter and returns a pointer to a StatsResponse protobuf message.
+// The uptime of the message is the time since the instance started.
+func ToStatsFromService(stats *models.Stats) *desc.StatsResponse {
+	return &desc.StatsResponse{
+		StartedAt:  timestamppb.New(stats.StartedAt),
+		Uptime:     durationpb.New(time.Since(stats.StartedAt)),
+		GoVersion:  stats.GoVersion,
+		Goroutines: int64(stats.Goroutines),
+		HeapBytes:  stats.HeapBytes,
+		GcCycles:   stats.GCCycles,
+		Ready:      stats.Ready,
+		CacheState: stats.CacheState,
+	}
+}
+
+// ToConfigFromService is a function that converts the settings of an instance to a ConfigResponse protobuf message.
+// It takes the settings by section as a parameter and returns a pointer to a ConfigResponse protobuf message.
+// The settings go through JSON, so every value that has a JSON form, like a list of strings or a number, is accepted.
+// It returns an error if the settings cannot be encoded.
+func ToConfigFromService(settings map[string]any) (*desc.ConfigResponse, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	config := &structpb.Struct{}
+	err = protojson.Unmarshal(data, config)
+	if err != nil {
+		return nil, err
+	}
+	return &desc.ConfigResponse{Config: config}, nil
+}
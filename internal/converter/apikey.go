@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	desc "github.com/t1ltxz-gxd/shortify/pkg/apikey_v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToAPIKeyFromService is a function that converts an API key model to an ApiKey protobuf message.
+// It takes a pointer to an API key model as a parameter and returns a pointer to an ApiKey protobuf message.
+// It creates a timestamp for the RevokedAt field of the message if the key was revoked.
+// The hash of the key is not part of the message.
+func ToAPIKeyFromService(key *models.APIKey) *desc.ApiKey {
+	var revokedAt *timestamppb.Timestamp
+	if key.RevokedAt != nil {
+		revokedAt = timestamppb.New(*key.RevokedAt)
+	}
+
+	return &desc.ApiKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+		RevokedAt: revokedAt,
+	}
+}
@@ -8,19 +8,25 @@ import (
 
 // ToURLFromService is a function that converts a URL model to a URL protobuf message.
 // It takes a pointer to a URL model as a parameter and returns a pointer to a URL protobuf message.
-// It creates a timestamp for the UpdatedAt field of the URL protobuf message if the UpdatedAt field of the URL model is not nil.
-// It then creates a new URL protobuf message with the OriginalUrl, ShortUrl, CreatedAt, and UpdatedAt fields from the URL model and returns it.
+// It creates a timestamp for the UpdatedAt and ExpiresAt fields of the URL protobuf message if the same fields of the URL model are not nil.
+// It then creates a new URL protobuf message with the OriginalUrl, ShortUrl, CreatedAt, UpdatedAt, ExpiresAt, and Owner fields from the URL model and returns it.
 func ToURLFromService(url *models.URL) *desc.Url {
 	var updatedAt *timestamppb.Timestamp
 	if url.UpdatedAt != nil {
 		updatedAt = timestamppb.New(*url.UpdatedAt)
 	}
+	var expiresAt *timestamppb.Timestamp
+	if url.ExpiresAt != nil {
+		expiresAt = timestamppb.New(*url.ExpiresAt)
+	}
 
 	return &desc.Url{
 		OriginalUrl: url.Original,
 		ShortUrl:    url.Hash,
 		CreatedAt:   timestamppb.New(url.AddedAt),
 		UpdatedAt:   updatedAt,
+		ExpiresAt:   expiresAt,
+		Owner:       url.Owner,
 	}
 }
 
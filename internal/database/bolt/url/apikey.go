@@ -0,0 +1,133 @@
+package url
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	bolt "go.etcd.io/bbolt"
+	"sort"
+	"time"
+)
+
+// CreateAPIKey is a method that adds a new API key to the database.
+// It takes a context for managing the lifecycle of the operation, and the key with its ID, name, hash and creation time.
+// It writes the record and its entry in the index by hash in one transaction.
+// It returns an error if the ID or the hash is already taken, and if the operation fails.
+func (d *database) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(keyRecord{
+		Name:      key.Name,      // Set the name of the holder of the key
+		Hash:      key.Hash,      // Set the hash of the key
+		CreatedAt: key.CreatedAt, // Set the time when the key was created
+	})
+	if err != nil {
+		return err
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		keys := tx.Bucket(keysBucket)
+		index := tx.Bucket(keyHashBucket)
+		if keys.Get([]byte(key.ID)) != nil || index.Get([]byte(key.Hash)) != nil {
+			return errors.New("API key already exists")
+		}
+		err := keys.Put([]byte(key.ID), value)
+		if err != nil {
+			return err
+		}
+		return index.Put([]byte(key.Hash), []byte(key.ID))
+	})
+}
+
+// GetAPIKey is a method that retrieves an active API key using the hash of the key.
+// It takes a context for managing the lifecycle of the operation, and the hex-encoded SHA-256 hash of the key.
+// It returns the key, nil for both the key and the error if there is no active key with the hash,
+// and an error if the record cannot be read.
+func (d *database) GetAPIKey(ctx context.Context, hash string) (*models.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var key *models.APIKey
+	err := d.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(keyHashBucket).Get([]byte(hash))
+		if id == nil {
+			return nil
+		}
+		var r keyRecord
+		err := json.Unmarshal(tx.Bucket(keysBucket).Get(id), &r)
+		if err != nil {
+			return err
+		}
+		if r.RevokedAt == nil {
+			key = r.toAPIKey(string(id))
+		}
+		return nil
+	})
+	return key, err
+}
+
+// ListAPIKeys is a method that retrieves every API key, the revoked ones included.
+// It takes a context for managing the lifecycle of the operation.
+// It returns the keys, the newest first, and an error if a record cannot be read.
+func (d *database) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var keys []*models.APIKey
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(keysBucket).ForEach(func(k, v []byte) error {
+			var r keyRecord
+			err := json.Unmarshal(v, &r)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, r.toAPIKey(string(k)))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// RevokeAPIKey is a method that marks an API key as revoked, so it no longer authenticates.
+// It takes a context for managing the lifecycle of the operation, and the ID of the key.
+// The entry in the index by hash is kept, so the hash of a revoked key cannot be taken again.
+// It returns models.ErrorAPIKeyNotFound if there is no active key with the ID, and an error if the operation fails.
+func (d *database) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		keys := tx.Bucket(keysBucket)
+		value := keys.Get([]byte(id))
+		if value == nil {
+			return models.ErrorAPIKeyNotFound
+		}
+		var r keyRecord
+		err := json.Unmarshal(value, &r)
+		if err != nil {
+			return err
+		}
+		if r.RevokedAt != nil {
+			return models.ErrorAPIKeyNotFound
+		}
+		now := time.Now()
+		r.RevokedAt = &now
+		value, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return keys.Put([]byte(id), value)
+	})
+}
@@ -0,0 +1,36 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"os"
+)
+
+// Backup is a method that copies a consistent snapshot of the database to a file while it keeps serving.
+// It takes a context for managing the lifecycle of the operation, and the path of the backup file.
+// The copy runs in a read transaction, so writers are not blocked while it is made.
+// It writes to a temporary file next to path and renames it when the copy is complete,
+// so a failed backup never leaves a truncated file behind.
+// It returns an error if the copy cannot be written.
+func (d *database) Backup(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(tmp, 0o600)
+	})
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return err
+	}
+	logger.Info("Database is backed up", zap.String("path", path))
+	return nil
+}
@@ -0,0 +1,8 @@
+package url
+
+// Close is a method that closes the database file and releases its lock, so another process can open it.
+// It waits for the transactions in progress to finish.
+// It returns an error if the file could not be closed.
+func (d *database) Close() error {
+	return d.db.Close()
+}
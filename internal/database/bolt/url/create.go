@@ -0,0 +1,66 @@
+package url
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Create is a method that adds a new URL to the database.
+// It takes a context for managing the lifecycle of the operation,
+// a url which is the actual URL string, a hash which is the unique identifier for the URL,
+// the subject of the caller that owns the URL, and the time when the URL expires, or nil if it never expires.
+// It writes the record and its entry in the secondary index in one transaction,
+// replacing the record of a deleted or expired URL with the same hash together with its index entry.
+// It returns models.ErrorURLExists if the hash is taken by a URL that was neither deleted nor has expired,
+// and an error if the operation fails.
+func (d *database) Create(ctx context.Context, url, hash, owner string, expiresAt *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	value, err := json.Marshal(record{
+		Original:  url,       // Set the original URL
+		AddedAt:   now,       // Set the time when the URL was added
+		UpdatedAt: &now,      // Set the time when the URL was updated
+		ExpiresAt: expiresAt, // Set the time when the URL expires
+		Owner:     owner,     // Set the owner of the URL
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		urls := tx.Bucket(urlsBucket)
+		index := tx.Bucket(originalBucket)
+		if current := urls.Get([]byte(hash)); current != nil {
+			var r record
+			err := json.Unmarshal(current, &r)
+			if err != nil {
+				return err
+			}
+			if r.live(now) {
+				return models.ErrorURLExists
+			}
+			err = index.Delete(originalKey(r.Original, hash))
+			if err != nil {
+				return err
+			}
+		}
+		err := urls.Put([]byte(hash), value)
+		if err != nil {
+			return err
+		}
+		return index.Put(originalKey(url, hash), nil)
+	})
+	if err != nil {
+		logger.Error("Failed to insert URL into the database", zap.Error(err)) // Log the error if the creation fails
+		return err
+	}
+	return nil
+}
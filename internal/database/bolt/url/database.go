@@ -0,0 +1,167 @@
+package url
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	def "github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	_ def.URLDatabase    = (*database)(nil)
+	_ def.Purger         = (*database)(nil)
+	_ def.Backuper       = (*database)(nil)
+	_ def.Disabler       = (*database)(nil)
+	_ def.APIKeyDatabase = (*database)(nil)
+)
+
+// Names of the buckets and keys in the database file
+var (
+	urlsBucket     = []byte("urls")             // The records keyed by hash
+	originalBucket = []byte("urls_by_original") // The secondary index keyed by the original URL and the hash
+	keysBucket     = []byte("api_keys")         // The API keys keyed by ID
+	keyHashBucket  = []byte("api_keys_by_hash") // The secondary index of the API keys keyed by hash, holding the ID
+	metaBucket     = []byte("meta")             // The metadata of the file
+	versionKey     = []byte("schema_version")   // The version of the layout of the file in metaBucket
+)
+
+// schemaVersion is the version of the layout of the buckets written by this build.
+// It is bumped whenever the layout changes, together with a step in ApplyMigrations that converts older files.
+// Version 2 added the API key buckets and the owner of the records, which is empty in the records of older files.
+// Version 3 prefixed the owners by where they come from; the bare owners of version 2 are API key IDs.
+// Version 4 keeps deleted records, marked with the time of the deletion, until they are purged.
+// Older records were never deleted, so the files need no conversion, but older builds must not resurrect the deleted records.
+const schemaVersion uint64 = 4
+
+// openTimeout is how long Open waits for the lock on a file that another process has open.
+const openTimeout = 5 * time.Second
+
+type database struct {
+	db *bolt.DB // The database file
+}
+
+// Open is a function that opens the bbolt database file at path and returns it as a URLDatabase.
+// It creates the file and its directory if they do not exist.
+// A file can only be open in one process at a time, so it gives up after openTimeout if another process holds it.
+// It returns an error if the file cannot be opened.
+func Open(path string) (def.URLDatabase, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+	return &database{db: db}, nil
+}
+
+// Init is a function that opens the bbolt database.
+// It takes a context for managing the lifecycle of the operation, and the configuration of the application.
+// It reads the path of the database file from the storage.bolt.path setting.
+// If the database cannot be opened, it logs a fatal error.
+func Init(_ context.Context, cfg *config.Config) def.URLDatabase {
+	db, err := Open(cfg.Storage.Bolt.Path)
+	if err != nil {
+		logger.Fatal("failed to open database", zap.Error(err))
+	}
+	return db
+}
+
+// ApplyMigrations is a method on the database struct.
+// The file has no SQL schema, so it creates the buckets that are missing and records the layout version.
+// It takes a context for managing the lifecycle of the operation.
+// It returns an error if the file was written by a newer build with a layout this build does not know.
+func (d *database) ApplyMigrations(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		var version uint64
+		if v := meta.Get(versionKey); v != nil {
+			version = binary.BigEndian.Uint64(v)
+		}
+		if version > schemaVersion {
+			return fmt.Errorf("database layout version %d is unknown, the file is newer than this build", version)
+		}
+
+		for _, name := range [][]byte{urlsBucket, originalBucket, keysBucket, keyHashBucket} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		if version == 2 {
+			err := prefixOwners(tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		if version < schemaVersion {
+			logger.Info("Applied migration", zap.Uint64("version", schemaVersion))
+		}
+		return meta.Put(versionKey, binary.BigEndian.AppendUint64(nil, schemaVersion))
+	})
+}
+
+// prefixOwners is a function that converts the bare owners of the records written by version 2 of the layout,
+// which are API key IDs, to the subjects of the keys.
+// An owner holding a '|' already belongs to a bearer token, as no API key ID holds one, so it is kept.
+// It takes the transaction of the migration and returns an error if a record cannot be rewritten.
+func prefixOwners(tx *bolt.Tx) error {
+	urls := tx.Bucket(urlsBucket)
+	updated := make(map[string][]byte)
+	err := urls.ForEach(func(hash, value []byte) error {
+		var r record
+		err := json.Unmarshal(value, &r)
+		if err != nil || r.Owner == "" || strings.Contains(r.Owner, "|") {
+			return err
+		}
+		r.Owner = auth.KeySubject(r.Owner)
+		value, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		updated[string(hash)] = value
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// The bucket must not be changed while ForEach walks it
+	for hash, value := range updated {
+		err := urls.Put([]byte(hash), value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// originalKey is a function that builds the key of the secondary index for a URL.
+// The key is the original URL and the hash separated by a zero byte,
+// so that all hashes of the same URL are next to each other and can be found with a prefix scan.
+func originalKey(original, hash string) []byte {
+	key := make([]byte, 0, len(original)+1+len(hash))
+	key = append(key, original...)
+	key = append(key, 0)
+	return append(key, hash...)
+}
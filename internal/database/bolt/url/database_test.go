@@ -0,0 +1,166 @@
+package url_test
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	boltURL "github.com/t1ltxz-gxd/shortify/internal/database/bolt/url"
+	"github.com/t1ltxz-gxd/shortify/internal/database/databasetest"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	bolt "go.etcd.io/bbolt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// TestConformance is a test function that runs the storage conformance suite against a bolt database file per case.
+func TestConformance(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T) database.URLDatabase {
+		db, err := boltURL.Open(filepath.Join(t.TempDir(), "urls.bolt"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		require.NoError(t, db.ApplyMigrations(context.Background()))
+		return db
+	})
+}
+
+// indexer is the lookup by original URL of the bolt database.
+type indexer interface {
+	HashesByOriginal(ctx context.Context, original string) ([]string, error)
+}
+
+// TestHashesByOriginal is a test function that checks that the index by original URL lists the hashes of the URLs that resolve in order,
+// and skips a hash once its URL is deleted, disabled or has expired.
+func TestHashesByOriginal(t *testing.T) {
+	ctx := context.Background()
+	db, err := boltURL.Open(filepath.Join(t.TempDir(), "urls.bolt"))
+	require.NoError(t, err)
+	require.NoError(t, db.ApplyMigrations(ctx))
+
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, db.Create(ctx, "https://example.com", "b", "", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com", "a", "", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com", "d", "", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com", "e", "", &past))
+	require.NoError(t, db.Create(ctx, "https://example.com/other", "c", "", nil))
+
+	hashes, err := db.(indexer).HashesByOriginal(ctx, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "d"}, hashes)
+
+	require.NoError(t, db.Delete(ctx, "a", ""))
+	require.NoError(t, db.(database.Disabler).SetDisabled(ctx, "d", true))
+	hashes, err = db.(indexer).HashesByOriginal(ctx, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, hashes)
+}
+
+// TestPurge_Index is a test function that checks that a purge removes the entries of the purged URLs from the index by original URL.
+func TestPurge_Index(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "urls.bolt")
+	db, err := boltURL.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, db.ApplyMigrations(ctx))
+
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, db.Create(ctx, "https://example.com", "a", "", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com", "b", "", &past))
+	require.NoError(t, db.Create(ctx, "https://example.com", "c", "", nil))
+	require.NoError(t, db.Delete(ctx, "c", ""))
+
+	n, err := db.(database.Purger).Purge(ctx, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+	require.NoError(t, db.Close())
+
+	raw, err := bolt.Open(path, 0o600, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = raw.Close() })
+	require.NoError(t, raw.View(func(tx *bolt.Tx) error {
+		require.Equal(t, 1, tx.Bucket([]byte("urls")).Stats().KeyN)
+		require.Equal(t, 1, tx.Bucket([]byte("urls_by_original")).Stats().KeyN)
+		return nil
+	}))
+}
+
+// TestMigrateOwners is a test function that checks that upgrading a file of layout version 2,
+// whose owners are bare API key IDs, gives the URLs to the subjects of the keys and keeps the owners of bearer tokens.
+func TestMigrateOwners(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "urls.bolt")
+	db, err := boltURL.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, db.ApplyMigrations(ctx))
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "a", "key1", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com/b", "b", "", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com/c", "c", "jwt:https://issuer.example.com|user1", nil))
+	require.NoError(t, db.Close())
+
+	raw, err := bolt.Open(path, 0o600, nil)
+	require.NoError(t, err)
+	require.NoError(t, raw.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("meta")).Put([]byte("schema_version"), binary.BigEndian.AppendUint64(nil, 2))
+	}))
+	require.NoError(t, raw.Close())
+
+	db, err = boltURL.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, db.ApplyMigrations(ctx))
+	url, err := db.Get(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, "key:key1", url.Owner)
+	url, err = db.Get(ctx, "b")
+	require.NoError(t, err)
+	require.Empty(t, url.Owner)
+	url, err = db.Get(ctx, "c")
+	require.NoError(t, err)
+	require.Equal(t, "jwt:https://issuer.example.com|user1", url.Owner)
+}
+
+// TestBackup is a test function that checks that a backup is a database file of its own holding the URLs.
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	db, err := boltURL.Open(filepath.Join(dir, "urls.bolt"))
+	require.NoError(t, err)
+	require.NoError(t, db.ApplyMigrations(ctx))
+	require.NoError(t, db.Create(ctx, "https://example.com", "abc", "", nil))
+
+	path := filepath.Join(dir, "backup.bolt")
+	require.NoError(t, db.(database.Backuper).Backup(ctx, path))
+
+	restored, err := boltURL.Open(path)
+	require.NoError(t, err)
+	url, err := restored.Get(ctx, "abc")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", url.Original)
+}
+
+// TestClose is a test function that checks that Close releases the lock on the file, so it can be opened again at once.
+func TestClose(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "urls.bolt")
+	db, err := boltURL.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, db.ApplyMigrations(ctx))
+	require.NoError(t, db.Create(ctx, "https://example.com", "abc", "", nil))
+	require.NoError(t, db.Close())
+
+	// The lock on the file is released, so it opens again at once.
+	reopened, err := boltURL.Open(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+	url, err := reopened.Get(ctx, "abc")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", url.Original)
+}
@@ -0,0 +1,56 @@
+package url
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Delete is a method that marks a URL as deleted using its hash.
+// It takes a context for managing the lifecycle of the operation,
+// the hash of the URL to delete, and the owner the URL must belong to.
+// The record and its entry in the secondary index are kept until Purge removes them.
+// It returns models.ErrorURLNotFound if there is no URL with the hash owned by the owner, or it was deleted or has expired,
+// and an error if the operation fails.
+func (d *database) Delete(ctx context.Context, hash, owner string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		urls := tx.Bucket(urlsBucket)
+		value := urls.Get([]byte(hash))
+		if value == nil {
+			return models.ErrorURLNotFound
+		}
+		var r record
+		err := json.Unmarshal(value, &r)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if r.Owner != owner || !r.live(now) {
+			return models.ErrorURLNotFound
+		}
+		r.DeletedAt = &now
+		r.UpdatedAt = &now
+		value, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return urls.Put([]byte(hash), value)
+	})
+	if err != nil {
+		if !errors.Is(err, models.ErrorURLNotFound) {
+			logger.Error("Failed to delete URL from the database", zap.String("hash", hash), zap.Error(err))
+		}
+		return err
+	}
+	logger.Debug("URL is deleted from the database", zap.String("hash", hash))
+	return nil
+}
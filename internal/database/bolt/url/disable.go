@@ -0,0 +1,60 @@
+package url
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SetDisabled is a method that disables a URL, so it no longer resolves, or enables it again.
+// It takes a context for managing the lifecycle of the operation, the hash of the URL, and whether to disable it.
+// It rewrites the record in one transaction; a URL disabled twice keeps the time when it was first disabled.
+// It returns models.ErrorURLNotFound if there is no URL with the hash that was neither deleted nor has expired,
+// and an error if the operation fails.
+func (d *database) SetDisabled(ctx context.Context, hash string, disabled bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		urls := tx.Bucket(urlsBucket)
+		value := urls.Get([]byte(hash))
+		if value == nil {
+			return models.ErrorURLNotFound
+		}
+		var r record
+		err := json.Unmarshal(value, &r)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if !r.live(now) {
+			return models.ErrorURLNotFound
+		}
+		switch {
+		case disabled && r.DisabledAt == nil:
+			r.DisabledAt = &now
+		case !disabled:
+			r.DisabledAt = nil
+		}
+		r.UpdatedAt = &now
+		value, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return urls.Put([]byte(hash), value)
+	})
+	if err != nil {
+		if !errors.Is(err, models.ErrorURLNotFound) {
+			logger.Error("Failed to disable URL in the database", zap.String("hash", hash), zap.Bool("disabled", disabled), zap.Error(err))
+		}
+		return err
+	}
+	logger.Debug("URL is disabled in the database", zap.String("hash", hash), zap.Bool("disabled", disabled))
+	return nil
+}
@@ -0,0 +1,50 @@
+package url
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Get is a method that retrieves a URL from the database using its hash.
+// It takes a context for managing the lifecycle of the operation,
+// and the hash of the URL to retrieve.
+// If the URL is not found, has expired or was disabled, it returns nil for both the URL and the error.
+// It returns a pointer to the URL model if the operation is successful, and an error if the record cannot be read.
+func (d *database) Get(ctx context.Context, hash string) (*models.URL, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Fetching URL from database", zap.String("hash", hash))
+	var url *models.URL
+	err := d.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(urlsBucket).Get([]byte(hash))
+		if value == nil {
+			return nil
+		}
+		var r record
+		err := json.Unmarshal(value, &r)
+		if err != nil {
+			return err
+		}
+		if r.resolves(time.Now()) {
+			url = r.toURL(hash)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to fetch URL from the database", zap.String("hash", hash), zap.Error(err))
+		return nil, err
+	}
+	if url == nil {
+		logger.Error("URL is not found in the database", zap.String("hash", hash))
+		return nil, nil
+	}
+	logger.Debug("URL is fetched from the database", zap.String("url", url.Original))
+	return url, nil
+}
@@ -0,0 +1,45 @@
+package url
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	bolt "go.etcd.io/bbolt"
+	"time"
+)
+
+// HashesByOriginal is a method that finds the hashes of an original URL using the secondary index.
+// It takes a context for managing the lifecycle of the operation, and the original URL.
+// The index keeps the entries of the deleted and expired URLs until they are purged, so it skips the hashes whose URL does not resolve,
+// the disabled ones included.
+// It returns the hashes in ascending order, or an empty slice if the URL has none.
+func (d *database) HashesByOriginal(ctx context.Context, original string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	prefix := append([]byte(original), 0)
+	var hashes []string
+	err := d.db.View(func(tx *bolt.Tx) error {
+		urls := tx.Bucket(urlsBucket)
+		c := tx.Bucket(originalBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			hash := k[len(prefix):]
+			value := urls.Get(hash)
+			if value == nil {
+				continue
+			}
+			var r record
+			err := json.Unmarshal(value, &r)
+			if err != nil {
+				return err
+			}
+			if r.resolves(now) {
+				hashes = append(hashes, string(hash))
+			}
+		}
+		return nil
+	})
+	return hashes, err
+}
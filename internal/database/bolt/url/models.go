@@ -0,0 +1,72 @@
+package url
+
+import (
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"time"
+)
+
+// record is a struct that represents a URL as it is stored in the urls bucket.
+// The hash is the key of the record, so it is not stored in the value.
+type record struct {
+	Original   string     `json:"original_url"`          // The original URL
+	AddedAt    time.Time  `json:"added_at"`              // The time when the URL was added
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`  // The time when the URL was last updated, nil if not updated
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`  // The time when the URL expires, nil if it never expires
+	Owner      string     `json:"owner,omitempty"`       // The subject of the caller that created the URL, empty if created anonymously
+	DisabledAt *time.Time `json:"disabled_at,omitempty"` // The time when the URL was disabled, nil while it resolves
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`  // The time when the URL was deleted, nil if it was not deleted
+}
+
+// live is a method on the record struct.
+// It reports whether the URL was not deleted and has not expired at now.
+func (r record) live(now time.Time) bool {
+	return r.DeletedAt == nil && (r.ExpiresAt == nil || now.Before(*r.ExpiresAt))
+}
+
+// purgeable is a method on the record struct.
+// It reports whether the URL has expired at now or was deleted before deletedBefore, so a purge removes it.
+func (r record) purgeable(now, deletedBefore time.Time) bool {
+	expired := r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+	deleted := r.DeletedAt != nil && !deletedBefore.Before(*r.DeletedAt)
+	return expired || deleted
+}
+
+// resolves is a method on the record struct.
+// It reports whether the URL has not expired at now and was not disabled, so Get finds it.
+func (r record) resolves(now time.Time) bool {
+	return r.live(now) && r.DisabledAt == nil
+}
+
+// toURL is a method on the record struct.
+// It converts the record stored under hash to the application model.
+func (r record) toURL(hash string) *models.URL {
+	return &models.URL{
+		Original:  r.Original,  // Set the original URL
+		Hash:      hash,        // Set the hash
+		AddedAt:   r.AddedAt,   // Set the time when the URL was added
+		UpdatedAt: r.UpdatedAt, // Set the time when the URL was last updated
+		ExpiresAt: r.ExpiresAt, // Set the time when the URL expires
+		Owner:     r.Owner,     // Set the owner of the URL
+	}
+}
+
+// keyRecord is a struct that represents an API key as it is stored in the api_keys bucket.
+// The ID is the key of the record, so it is not stored in the value.
+type keyRecord struct {
+	Name      string     `json:"name"`                 // The name of the holder of the key
+	Hash      string     `json:"key_hash"`             // The SHA-256 hash of the key
+	CreatedAt time.Time  `json:"created_at"`           // The time when the key was created
+	RevokedAt *time.Time `json:"revoked_at,omitempty"` // The time when the key was revoked, nil while it is active
+}
+
+// toAPIKey is a method on the keyRecord struct.
+// It converts the record stored under id to the application model.
+func (r keyRecord) toAPIKey(id string) *models.APIKey {
+	return &models.APIKey{
+		ID:        id,          // Set the identifier of the key
+		Name:      r.Name,      // Set the name of the holder of the key
+		Hash:      r.Hash,      // Set the hash of the key
+		CreatedAt: r.CreatedAt, // Set the time when the key was created
+		RevokedAt: r.RevokedAt, // Set the time when the key was revoked
+	}
+}
@@ -0,0 +1,23 @@
+package url
+
+import (
+	"context"
+	"errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Ping is a method that checks whether the database file is open and has its buckets.
+// It takes a context for managing the lifecycle of the operation.
+// It returns an error if the file is closed or the buckets are missing.
+func (d *database) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return d.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(urlsBucket) == nil {
+			return errors.New("the urls bucket is missing")
+		}
+		return nil
+	})
+}
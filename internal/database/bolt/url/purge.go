@@ -0,0 +1,70 @@
+package url
+
+import (
+	"context"
+	"encoding/json"
+	bolt "go.etcd.io/bbolt"
+	"time"
+)
+
+// Purge is a method that removes the expired URLs and the URLs deleted longer than retention ago for good.
+// It takes a context for managing the lifecycle of the operation, the retention of deleted URLs,
+// and the number of records to remove in one transaction.
+// The database file belongs to a single process, so no lock between instances is needed.
+// It removes the records together with their entries in the secondary index in batches until a batch comes back short,
+// so every transaction holds the write lock briefly; each batch carries on the scan where the previous one stopped.
+// It returns the number of records removed, and an error if a transaction fails.
+func (d *database) Purge(ctx context.Context, retention time.Duration, batchSize int) (int64, error) {
+	var total int64
+	var after []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		now := time.Now()
+		var n int
+		err := d.db.Update(func(tx *bolt.Tx) error {
+			urls := tx.Bucket(urlsBucket)
+			index := tx.Bucket(originalBucket)
+
+			// Collect the batch first, the bucket must not be changed while the cursor walks it
+			dead := make(map[string]string, batchSize)
+			c := urls.Cursor()
+			k, v := c.First()
+			if after != nil {
+				k, v = c.Seek(after)
+			}
+			for ; k != nil && len(dead) < batchSize; k, v = c.Next() {
+				var r record
+				err := json.Unmarshal(v, &r)
+				if err != nil {
+					return err
+				}
+				if r.purgeable(now, now.Add(-retention)) {
+					dead[string(k)] = r.Original
+				}
+				after = append(append(after[:0], k...), 0) // The smallest key after k
+			}
+
+			for hash, original := range dead {
+				err := index.Delete(originalKey(original, hash))
+				if err != nil {
+					return err
+				}
+				err = urls.Delete([]byte(hash))
+				if err != nil {
+					return err
+				}
+			}
+			n = len(dead)
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += int64(n)
+		if n < batchSize {
+			return total, nil
+		}
+	}
+}
@@ -3,25 +3,124 @@ package database
 import (
 	"context"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"time"
 )
 
 // URLDatabase is an interface that defines the methods for URL database operations.
 type URLDatabase interface {
-	// ApplyMigrations is a method that applies database migrations.
-	// It takes a slice of strings representing the migration files to be applied.
-	// It returns an error if the operation fails.
-	ApplyMigrations(migrationFiles []string) error
+	// ApplyMigrations is a method that brings the database schema up to the newest version.
+	// It takes a context for managing the lifecycle of the operation.
+	// It returns an error if the operation fails or if the applied schema does not match the known migrations.
+	ApplyMigrations(ctx context.Context) error
 
 	// Create is a method that adds a new URL to the database.
 	// It takes a context for managing the lifecycle of the operation,
-	// a url which is the actual URL string, and a hash which is the unique identifier for the URL.
-	// It returns an error if the operation fails.
-	Create(ctx context.Context, url, hash string) error
+	// a url which is the actual URL string, a hash which is the unique identifier for the URL,
+	// the subject of the caller that owns the URL, empty for an anonymous URL,
+	// and the time when the URL expires, or nil if it never expires.
+	// A hash whose URL was deleted or has expired can be taken again.
+	// It returns models.ErrorURLExists if the hash is taken by a live URL, and an error if the operation fails.
+	Create(ctx context.Context, url, hash, owner string, expiresAt *time.Time) error
 
 	// Get is a method that retrieves a URL from the database using its hash.
 	// It takes a context for managing the lifecycle of the operation,
 	// and the hash of the URL to retrieve.
+	// A URL that was deleted, has expired or was disabled is not found.
 	// It returns a pointer to a URL model if the operation is successful,
 	// and an error if the operation fails or if the URL is not found in the database.
 	Get(ctx context.Context, hash string) (*models.URL, error)
+
+	// Delete is a method that removes a URL from the database using its hash.
+	// It takes a context for managing the lifecycle of the operation,
+	// the hash of the URL to remove, and the owner the URL must belong to.
+	// Databases that support purging keep the row, marked as deleted, until the purge job removes it.
+	// It returns models.ErrorURLNotFound if there is no live URL with the hash owned by the owner,
+	// so a caller cannot tell the URLs of others from missing ones, and an error if the operation fails.
+	Delete(ctx context.Context, hash, owner string) error
+
+	// Ping is a method that checks whether the database is reachable.
+	// It takes a context for managing the lifecycle of the operation.
+	// It returns an error if the database cannot be reached.
+	Ping(ctx context.Context) error
+
+	// Close is a method that releases the connections or the file of the database.
+	// It is called once the application has stopped serving, so no other method is called after it.
+	// It returns an error if the database could not be closed cleanly.
+	Close() error
+}
+
+// Purger is an interface implemented by the URL databases that keep deleted and expired URLs until they are purged.
+type Purger interface {
+	// Purge is a method that removes the expired URLs and the URLs deleted longer than retention ago for good.
+	// It takes a context for managing the lifecycle of the operation, the retention of deleted URLs,
+	// and the number of URLs to remove in one statement, so a large purge does not hold long locks.
+	// It returns the number of URLs removed, and an error if the operation fails.
+	// The number is zero without an error if another instance is purging at the same time.
+	Purge(ctx context.Context, retention time.Duration, batchSize int) (int64, error)
+}
+
+// Backuper is an interface implemented by the URL databases that can copy themselves to a file while they keep serving.
+type Backuper interface {
+	// Backup is a method that writes a consistent snapshot of the database to a file.
+	// It takes a context for managing the lifecycle of the operation, and the path of the backup file.
+	// It returns an error if the snapshot cannot be written.
+	Backup(ctx context.Context, path string) error
+}
+
+// Searcher is an interface implemented by the URL databases that can search the URLs by text.
+type Searcher interface {
+	// Search is a method that finds the live URLs of an owner whose original URL or host contains the query, ignoring case.
+	// It takes a context for managing the lifecycle of the operation, the owner, the query,
+	// and the number of URLs to return and to skip, for pagination.
+	// It returns the matching URLs, the most relevant first, and an error if the operation fails.
+	Search(ctx context.Context, owner, query string, limit, offset int) ([]*models.URL, error)
+}
+
+// Disabler is an interface implemented by the URL databases that can stop a URL from resolving without deleting it.
+type Disabler interface {
+	// SetDisabled is a method that disables a URL, so Get no longer finds it, or enables it again.
+	// It takes a context for managing the lifecycle of the operation, the hash of the URL, and whether to disable it.
+	// A disabled URL keeps its hash and its owner: it cannot be created again, and its owner can still delete it.
+	// Disabling a disabled URL or enabling an enabled one is not an error.
+	// It returns models.ErrorURLNotFound if there is no URL with the hash that was neither deleted nor has expired,
+	// and an error if the operation fails.
+	SetDisabled(ctx context.Context, hash string, disabled bool) error
+}
+
+// Invalidator is an interface implemented by the URL databases that need to hear about the URLs changed by the other instances,
+// such as the ones that read from replicas lagging behind. It has the methods of invalidation.Handler,
+// so the database is handed the messages of the cache invalidation bus before the caches are evicted.
+type Invalidator interface {
+	// Invalidate is a method that records that another instance changed the URL with the hash.
+	Invalidate(ctx context.Context, hash string)
+
+	// Flush is a method that records that other instances may have changed any URL.
+	// It is called when the bus reconnects and messages may have been missed.
+	Flush(ctx context.Context)
+}
+
+// APIKeyDatabase is an interface implemented by the URL databases that store the API keys next to the URLs.
+// Only the hashes of the keys are stored, never the keys themselves.
+type APIKeyDatabase interface {
+	// CreateAPIKey is a method that adds a new API key to the database.
+	// It takes a context for managing the lifecycle of the operation, and the key with its ID, name, hash and creation time.
+	// It returns an error if the operation fails, or if the ID or the hash is already taken.
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+
+	// GetAPIKey is a method that retrieves an active API key from the database using the hash of the key.
+	// It takes a context for managing the lifecycle of the operation, and the hex-encoded SHA-256 hash of the key.
+	// A key that was revoked is not found.
+	// It returns the key, nil for both the key and the error if it is not found, and an error if the operation fails.
+	GetAPIKey(ctx context.Context, hash string) (*models.APIKey, error)
+
+	// ListAPIKeys is a method that retrieves every API key from the database, the revoked ones included.
+	// It takes a context for managing the lifecycle of the operation.
+	// It returns the keys, the newest first, and an error if the operation fails.
+	ListAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+
+	// RevokeAPIKey is a method that revokes an API key, so it no longer authenticates.
+	// It takes a context for managing the lifecycle of the operation, and the ID of the key.
+	// The key is kept, so the URLs it owns still tell who created them.
+	// It returns models.ErrorAPIKeyNotFound if there is no active key with the ID, and an error if the operation fails.
+	RevokeAPIKey(ctx context.Context, id string) error
 }
@@ -0,0 +1,353 @@
+// Package databasetest is a conformance suite for implementations of database.URLDatabase.
+// Every storage backend runs it from its own tests, so they all behave the same way towards the repository.
+package databasetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+)
+
+// Factory is a function that returns an empty, migrated database for a single test.
+// It registers the cleanup of the database with t.Cleanup.
+type Factory func(t *testing.T) database.URLDatabase
+
+// Run is a function that runs the conformance suite against the databases returned by newDB.
+// Every case gets a database of its own.
+func Run(t *testing.T, newDB Factory) {
+	cases := []struct {
+		name string
+		test func(t *testing.T, db database.URLDatabase)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"DuplicateHash", testDuplicateHash},
+		{"NotFound", testNotFound},
+		{"Ping", testPing},
+		{"Delete", testDelete},
+		{"MigrationsAreIdempotent", testMigrationsAreIdempotent},
+		{"ConcurrentCreate", testConcurrentCreate},
+		{"ConcurrentDuplicate", testConcurrentDuplicate},
+		{"Expired", testExpired},
+		{"RecreateAfterDelete", testRecreateAfterDelete},
+		{"Purge", testPurge},
+		{"Ownership", testOwnership},
+		{"LongOwner", testLongOwner},
+		{"Disable", testDisable},
+		{"APIKeys", testAPIKeys},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.test(t, newDB(t))
+		})
+	}
+}
+
+// testCreateAndGet checks that a created URL is returned with its hash and timestamps.
+func testCreateAndGet(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", "", nil))
+
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, "https://example.com/a", url.Original)
+	assert.Equal(t, "hashA", url.Hash)
+	assert.False(t, url.AddedAt.IsZero(), "AddedAt is not set")
+}
+
+// testDuplicateHash checks that a hash cannot be taken twice and the first URL is kept.
+func testDuplicateHash(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", "", nil))
+
+	err := db.Create(ctx, "https://example.com/b", "hashA", "", nil)
+	require.ErrorIs(t, err, models.ErrorURLExists)
+
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, "https://example.com/a", url.Original)
+}
+
+// testNotFound checks that a missing hash is reported as nil without an error.
+func testNotFound(t *testing.T, db database.URLDatabase) {
+	url, err := db.Get(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Nil(t, url)
+}
+
+// testPing checks that an open database answers a ping.
+func testPing(t *testing.T, db database.URLDatabase) {
+	assert.NoError(t, db.Ping(context.Background()))
+}
+
+// testDelete checks that a deleted URL is gone and that deleting it again reports models.ErrorURLNotFound.
+func testDelete(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", "", nil))
+
+	require.NoError(t, db.Delete(ctx, "hashA", ""))
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	assert.Nil(t, url)
+
+	require.ErrorIs(t, db.Delete(ctx, "hashA", ""), models.ErrorURLNotFound)
+}
+
+// testMigrationsAreIdempotent checks that applying the migrations again keeps the data.
+func testMigrationsAreIdempotent(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", "", nil))
+	require.NoError(t, db.ApplyMigrations(ctx))
+
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+}
+
+// testConcurrentCreate checks that URLs created from many goroutines at once are all stored.
+func testConcurrentCreate(t *testing.T, db database.URLDatabase) {
+	const n = 50
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, db.Create(ctx, fmt.Sprintf("https://example.com/%d", i), fmt.Sprintf("hash%d", i), "", nil))
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		url, err := db.Get(ctx, fmt.Sprintf("hash%d", i))
+		require.NoError(t, err)
+		require.NotNil(t, url)
+		assert.Equal(t, fmt.Sprintf("https://example.com/%d", i), url.Original)
+	}
+}
+
+// testConcurrentDuplicate checks that exactly one of many goroutines creating the same hash wins
+// and the others get models.ErrorURLExists.
+func testConcurrentDuplicate(t *testing.T, db database.URLDatabase) {
+	const n = 20
+	ctx := context.Background()
+
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := db.Create(ctx, fmt.Sprintf("https://example.com/%d", i), "shared", "", nil)
+			if err == nil {
+				created.Add(1)
+				return
+			}
+			assert.ErrorIs(t, err, models.ErrorURLExists)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), created.Load())
+}
+
+// testExpired checks that an expired URL is not found, cannot be deleted, and frees its hash.
+func testExpired(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, db.Create(ctx, "https://example.com/old", "hashA", "", &past))
+	require.NoError(t, db.Create(ctx, "https://example.com/new", "hashB", "", &future))
+
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	assert.Nil(t, url)
+	require.ErrorIs(t, db.Delete(ctx, "hashA", ""), models.ErrorURLNotFound)
+
+	url, err = db.Get(ctx, "hashB")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	require.NotNil(t, url.ExpiresAt)
+	assert.WithinDuration(t, future, *url.ExpiresAt, time.Second)
+
+	require.NoError(t, db.Create(ctx, "https://example.com/again", "hashA", "", nil))
+	url, err = db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, "https://example.com/again", url.Original)
+	assert.Nil(t, url.ExpiresAt)
+}
+
+// testRecreateAfterDelete checks that the hash of a deleted URL can be taken again.
+func testRecreateAfterDelete(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", "", nil))
+	require.NoError(t, db.Delete(ctx, "hashA", ""))
+
+	require.NoError(t, db.Create(ctx, "https://example.com/b", "hashA", "", nil))
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, "https://example.com/b", url.Original)
+}
+
+// testPurge checks that a purge removes the deleted and expired URLs in batches and keeps the live ones.
+// It is skipped for the databases that do not implement database.Purger.
+func testPurge(t *testing.T, db database.URLDatabase) {
+	purger, ok := db.(database.Purger)
+	if !ok {
+		t.Skip("the database does not keep deleted URLs")
+	}
+	ctx := context.Background()
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, db.Create(ctx, "https://example.com/live", "live", "", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com/expired", "expired", "", &past))
+	for i := 0; i < 3; i++ {
+		hash := fmt.Sprintf("deleted%d", i)
+		require.NoError(t, db.Create(ctx, "https://example.com/deleted", hash, "", nil))
+		require.NoError(t, db.Delete(ctx, hash, ""))
+	}
+
+	// A long retention keeps the deleted URLs, the expired one goes at once
+	n, err := purger.Purge(ctx, time.Hour, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = purger.Purge(ctx, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	url, err := db.Get(ctx, "live")
+	require.NoError(t, err)
+	assert.NotNil(t, url)
+}
+
+// testOwnership checks that a URL records its owner, that another owner cannot delete it,
+// and that the mismatch is reported as models.ErrorURLNotFound so the URLs of others cannot be told apart from missing ones.
+func testOwnership(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", "owner1", nil))
+
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, "owner1", url.Owner)
+
+	require.ErrorIs(t, db.Delete(ctx, "hashA", "owner2"), models.ErrorURLNotFound)
+	require.ErrorIs(t, db.Delete(ctx, "hashA", ""), models.ErrorURLNotFound)
+	url, err = db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	assert.NotNil(t, url)
+
+	require.NoError(t, db.Delete(ctx, "hashA", "owner1"))
+	url, err = db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	assert.Nil(t, url)
+}
+
+// testLongOwner checks that an owner longer than an API key ID, like the issuer and subject of a bearer token,
+// is stored whole and can delete its URL.
+func testLongOwner(t *testing.T, db database.URLDatabase) {
+	ctx := context.Background()
+	owner := "jwt:https://auth.example.com/realms/customers|" + strings.Repeat("f", 64)
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", owner, nil))
+
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, owner, url.Owner)
+
+	require.ErrorIs(t, db.Delete(ctx, "hashA", owner[:32]), models.ErrorURLNotFound)
+	require.NoError(t, db.Delete(ctx, "hashA", owner))
+}
+
+// testDisable checks that a disabled URL is not found but keeps its hash and its owner, that it resolves again once enabled,
+// and that a disabled URL that was deleted frees its hash for a URL that resolves.
+// It is skipped for the databases that do not implement database.Disabler.
+func testDisable(t *testing.T, db database.URLDatabase) {
+	disabler, ok := db.(database.Disabler)
+	if !ok {
+		t.Skip("the database cannot disable URLs")
+	}
+	ctx := context.Background()
+	require.NoError(t, db.Create(ctx, "https://example.com/a", "hashA", "owner1", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com/b", "hashB", "owner1", nil))
+
+	require.NoError(t, disabler.SetDisabled(ctx, "hashA", true))
+	require.NoError(t, disabler.SetDisabled(ctx, "hashA", true))
+	url, err := db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	assert.Nil(t, url)
+	require.ErrorIs(t, db.Create(ctx, "https://example.com/other", "hashA", "", nil), models.ErrorURLExists)
+
+	require.NoError(t, disabler.SetDisabled(ctx, "hashA", false))
+	url, err = db.Get(ctx, "hashA")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, "https://example.com/a", url.Original)
+	assert.Equal(t, "owner1", url.Owner)
+
+	require.NoError(t, disabler.SetDisabled(ctx, "hashB", true))
+	require.NoError(t, db.Delete(ctx, "hashB", "owner1"))
+	require.ErrorIs(t, disabler.SetDisabled(ctx, "hashB", false), models.ErrorURLNotFound)
+	require.NoError(t, db.Create(ctx, "https://example.com/new", "hashB", "owner2", nil))
+	url, err = db.Get(ctx, "hashB")
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, "https://example.com/new", url.Original)
+	assert.Equal(t, "owner2", url.Owner)
+	require.ErrorIs(t, disabler.SetDisabled(ctx, "missing", true), models.ErrorURLNotFound)
+}
+
+// testAPIKeys checks that API keys are found by their hash until they are revoked, and listed newest first with the revoked ones.
+// It is skipped for the databases that do not implement database.APIKeyDatabase.
+func testAPIKeys(t *testing.T, db database.URLDatabase) {
+	keys, ok := db.(database.APIKeyDatabase)
+	if !ok {
+		t.Skip("the database does not store API keys")
+	}
+	ctx := context.Background()
+	now := time.Now().UTC()
+	first := &models.APIKey{ID: "key1", Name: "first", Hash: "hash1", CreatedAt: now.Add(-time.Hour)}
+	second := &models.APIKey{ID: "key2", Name: "second", Hash: "hash2", CreatedAt: now}
+	require.NoError(t, keys.CreateAPIKey(ctx, first))
+	require.NoError(t, keys.CreateAPIKey(ctx, second))
+
+	key, err := keys.GetAPIKey(ctx, "hash1")
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	assert.Equal(t, "key1", key.ID)
+	assert.Equal(t, "first", key.Name)
+	assert.WithinDuration(t, first.CreatedAt, key.CreatedAt, time.Second)
+	assert.Nil(t, key.RevokedAt)
+
+	key, err = keys.GetAPIKey(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	require.NoError(t, keys.RevokeAPIKey(ctx, "key1"))
+	key, err = keys.GetAPIKey(ctx, "hash1")
+	require.NoError(t, err)
+	assert.Nil(t, key)
+	require.ErrorIs(t, keys.RevokeAPIKey(ctx, "key1"), models.ErrorAPIKeyNotFound)
+	require.ErrorIs(t, keys.RevokeAPIKey(ctx, "missing"), models.ErrorAPIKeyNotFound)
+
+	list, err := keys.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "key2", list[0].ID)
+	assert.Nil(t, list[0].RevokedAt)
+	assert.Equal(t, "key1", list[1].ID)
+	assert.NotNil(t, list[1].RevokedAt)
+}
@@ -0,0 +1,235 @@
+package url
+
+import (
+	"context"
+	"errors"
+	def "github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ def.URLDatabase    = (*database)(nil)
+	_ def.Disabler       = (*database)(nil)
+	_ def.APIKeyDatabase = (*database)(nil)
+)
+
+// database is a struct that keeps the URLs and the API keys in maps guarded by a mutex.
+// It loses everything when the process exits, so it is meant for tests and local experiments.
+type database struct {
+	m        sync.RWMutex             // Guards urls, disabled and keys
+	urls     map[string]models.URL    // The URLs keyed by hash
+	disabled map[string]struct{}      // The hashes of the disabled URLs
+	keys     map[string]models.APIKey // The API keys keyed by ID
+}
+
+// NewDatabase is a function that creates an empty in-memory URL database.
+func NewDatabase() def.URLDatabase {
+	return &database{
+		urls:     make(map[string]models.URL),    // Create the map of URLs
+		disabled: make(map[string]struct{}),      // Create the set of disabled URLs
+		keys:     make(map[string]models.APIKey), // Create the map of API keys
+	}
+}
+
+// ApplyMigrations is a method on the database struct.
+// The map has no schema, so there is nothing to migrate.
+func (d *database) ApplyMigrations(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Create is a method that adds a new URL to the database.
+// It takes a context for managing the lifecycle of the operation,
+// a url which is the actual URL string, a hash which is the unique identifier for the URL,
+// the subject of the caller that owns the URL, and the time when the URL expires, or nil if it never expires.
+// It returns models.ErrorURLExists if the hash is taken by a URL that has not expired.
+func (d *database) Create(ctx context.Context, url, hash, owner string, expiresAt *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	now := time.Now()
+	if current, ok := d.urls[hash]; ok && live(current, now) {
+		return models.ErrorURLExists
+	}
+	delete(d.disabled, hash)
+	d.urls[hash] = models.URL{
+		Original:  url,       // Set the original URL
+		Hash:      hash,      // Set the hash
+		AddedAt:   now,       // Set the time when the URL was added
+		UpdatedAt: &now,      // Set the time when the URL was updated
+		ExpiresAt: expiresAt, // Set the time when the URL expires
+		Owner:     owner,     // Set the owner of the URL
+	}
+	return nil
+}
+
+// Get is a method that retrieves a URL from the database using its hash.
+// It takes a context for managing the lifecycle of the operation,
+// and the hash of the URL to retrieve.
+// It returns a copy of the URL, or nil for both the URL and the error if the URL is not found, has expired or was disabled.
+func (d *database) Get(ctx context.Context, hash string) (*models.URL, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.m.RLock()
+	defer d.m.RUnlock()
+
+	url, ok := d.urls[hash]
+	if !ok || !live(url, time.Now()) {
+		return nil, nil
+	}
+	if _, disabled := d.disabled[hash]; disabled {
+		return nil, nil
+	}
+	return &url, nil
+}
+
+// Delete is a method that removes a URL from the database using its hash.
+// It takes a context for managing the lifecycle of the operation,
+// the hash of the URL to remove, and the owner the URL must belong to.
+// It returns models.ErrorURLNotFound if there is no URL with the hash owned by the owner or it has expired.
+func (d *database) Delete(ctx context.Context, hash, owner string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if url, ok := d.urls[hash]; !ok || url.Owner != owner || !live(url, time.Now()) {
+		return models.ErrorURLNotFound
+	}
+	delete(d.urls, hash)
+	delete(d.disabled, hash)
+	return nil
+}
+
+// SetDisabled is a method that disables a URL, so Get no longer finds it, or enables it again.
+// It takes a context for managing the lifecycle of the operation, the hash of the URL, and whether to disable it.
+// It returns models.ErrorURLNotFound if there is no URL with the hash or it has expired.
+func (d *database) SetDisabled(ctx context.Context, hash string, disabled bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if url, ok := d.urls[hash]; !ok || !live(url, time.Now()) {
+		return models.ErrorURLNotFound
+	}
+	if disabled {
+		d.disabled[hash] = struct{}{}
+	} else {
+		delete(d.disabled, hash)
+	}
+	return nil
+}
+
+// CreateAPIKey is a method that adds a new API key to the database.
+// It takes a context for managing the lifecycle of the operation, and the key with its ID, name, hash and creation time.
+// It returns an error if the ID or the hash is already taken.
+func (d *database) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	for _, k := range d.keys {
+		if k.ID == key.ID || k.Hash == key.Hash {
+			return errors.New("API key already exists")
+		}
+	}
+	d.keys[key.ID] = *key
+	return nil
+}
+
+// GetAPIKey is a method that retrieves an active API key using the hash of the key.
+// It takes a context for managing the lifecycle of the operation, and the hex-encoded SHA-256 hash of the key.
+// It returns a copy of the key, or nil for both the key and the error if there is no active key with the hash.
+func (d *database) GetAPIKey(ctx context.Context, hash string) (*models.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.m.RLock()
+	defer d.m.RUnlock()
+
+	for _, key := range d.keys {
+		if key.Hash == hash && key.RevokedAt == nil {
+			return &key, nil
+		}
+	}
+	return nil, nil
+}
+
+// ListAPIKeys is a method that retrieves copies of every API key, the revoked ones included.
+// It takes a context for managing the lifecycle of the operation.
+// It returns the keys, the newest first.
+func (d *database) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.m.RLock()
+	defer d.m.RUnlock()
+
+	keys := make([]*models.APIKey, 0, len(d.keys))
+	for _, key := range d.keys {
+		keys = append(keys, &key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+// RevokeAPIKey is a method that marks an API key as revoked, so it no longer authenticates.
+// It takes a context for managing the lifecycle of the operation, and the ID of the key.
+// It returns models.ErrorAPIKeyNotFound if there is no active key with the ID.
+func (d *database) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	key, ok := d.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return models.ErrorAPIKeyNotFound
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	d.keys[id] = key
+	return nil
+}
+
+// Ping is a method on the database struct.
+// The map is always reachable, so it only reports whether the context is done.
+func (d *database) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Close is a method on the database struct.
+// The map holds no resources, so there is nothing to release.
+func (d *database) Close() error {
+	return nil
+}
+
+// live is a function that reports whether the URL has not expired at now.
+func live(url models.URL, now time.Time) bool {
+	return url.ExpiresAt == nil || now.Before(*url.ExpiresAt)
+}
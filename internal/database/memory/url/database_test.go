@@ -0,0 +1,16 @@
+package url_test
+
+import (
+	"testing"
+
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/database/databasetest"
+	memoryURL "github.com/t1ltxz-gxd/shortify/internal/database/memory/url"
+)
+
+// TestConformance is a test function that runs the storage conformance suite against an in-memory database per case.
+func TestConformance(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T) database.URLDatabase {
+		return memoryURL.NewDatabase()
+	})
+}
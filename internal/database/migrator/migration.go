@@ -0,0 +1,93 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Names of the files that hold the statements of a migration
+const (
+	upFile   = "up.sql"   // The statements that apply the migration
+	downFile = "down.sql" // The statements that revert the migration
+)
+
+// Migration is a struct that represents a single version of the database schema.
+// It has five fields: Version, Name, Up, Down, and Checksum.
+// Version is the number that orders the migrations.
+// Name is the descriptive part of the directory name.
+// Up holds the statements that apply the migration, and Down the statements that revert it.
+// Checksum is the SHA-256 of Up and is used to detect migrations changed after they were applied.
+type Migration struct {
+	Version  int    // The version of the migration
+	Name     string // The name of the migration
+	Up       string // The statements that apply the migration
+	Down     string // The statements that revert the migration
+	Checksum string // The checksum of the up statements
+}
+
+// Load is a function that reads the migrations from a file system.
+// Every migration is a directory named like 001_initial_schema holding an up.sql and a down.sql file.
+// Entries that do not start with a version number are ignored.
+// It returns the migrations ordered by version,
+// and an error if a file cannot be read, up.sql is missing, or two directories share a version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]string)
+	var migrations []Migration
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		version, name, ok := parseDirName(entry.Name())
+		if !ok {
+			continue
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		up, err := fs.ReadFile(fsys, path.Join(entry.Name(), upFile))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		down, err := fs.ReadFile(fsys, path.Join(entry.Name(), downFile))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+
+		sum := sha256.Sum256(up)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     name,
+			Up:       string(up),
+			Down:     string(down),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseDirName is a function that splits a migration directory name like 001_initial_schema
+// into its version and name. It reports false if the name does not start with a version number.
+func parseDirName(dir string) (int, string, bool) {
+	prefix, name, _ := strings.Cut(dir, "_")
+	version, err := strconv.Atoi(prefix)
+	if err != nil || version <= 0 {
+		return 0, "", false
+	}
+	return version, name, true
+}
@@ -0,0 +1,61 @@
+package migrator_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/database/migrator"
+)
+
+// TestLoad_OrdersByVersion is a test function that checks that migrations are loaded in version order,
+// that unrelated entries are ignored, and that the checksum only depends on the up statements.
+func TestLoad_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_add_index/up.sql":         {Data: []byte("CREATE INDEX i ON t (c);")},
+		"010_add_index/down.sql":       {Data: []byte("DROP INDEX i;")},
+		"002_create_table/up.sql":      {Data: []byte("CREATE TABLE t (c INT);")},
+		"002_create_table/down.sql":    {Data: []byte("DROP TABLE t;")},
+		"README.md":                    {Data: []byte("not a migration")},
+		"scratch/up.sql":               {Data: []byte("not a migration either")},
+		"003_no_down_migration/up.sql": {Data: []byte("SELECT 1;")},
+	}
+
+	migrations, err := migrator.Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+
+	assert.Equal(t, 2, migrations[0].Version)
+	assert.Equal(t, "create_table", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE t;", migrations[0].Down)
+	assert.Equal(t, 3, migrations[1].Version)
+	assert.Empty(t, migrations[1].Down)
+	assert.Equal(t, 10, migrations[2].Version)
+
+	fsys["002_create_table/down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE IF EXISTS t;")}
+	reloaded, err := migrator.Load(fsys)
+	require.NoError(t, err)
+	assert.Equal(t, migrations[0].Checksum, reloaded[0].Checksum)
+}
+
+// TestLoad_DuplicateVersion is a test function that checks that two migrations with the same version are rejected.
+func TestLoad_DuplicateVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_first/up.sql":  {Data: []byte("SELECT 1;")},
+		"001_second/up.sql": {Data: []byte("SELECT 2;")},
+	}
+
+	_, err := migrator.Load(fsys)
+	assert.Error(t, err)
+}
+
+// TestLoad_MissingUp is a test function that checks that a migration without up.sql is rejected.
+func TestLoad_MissingUp(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_first/down.sql": {Data: []byte("SELECT 1;")},
+	}
+
+	_, err := migrator.Load(fsys)
+	assert.Error(t, err)
+}
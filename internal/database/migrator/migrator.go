@@ -0,0 +1,280 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"io/fs"
+)
+
+// Latest is the target version that stands for the newest known migration.
+const Latest = -1
+
+// lockKey is the key of the Postgres advisory lock held while migrating.
+// It keeps instances that start at the same time from applying the same migration twice.
+// Other drivers have no such lock: SQLite serializes writers on the database file itself.
+const lockKey int64 = 7_283_462_111
+
+// Migrator is a struct that applies and reverts versioned migrations.
+// It records every applied version with its checksum in the schema_migrations table.
+// It works with any driver sqlx knows the placeholders of, so the same migrator serves Postgres and SQLite.
+type Migrator struct {
+	db         *sqlx.DB    // The database connection
+	migrations []Migration // The known migrations ordered by version
+}
+
+// New is a function that creates a new Migrator.
+// It takes the database connection and the file system holding the migrations.
+// It returns an error if the migrations cannot be loaded.
+func New(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up is a method on the Migrator struct.
+// It applies every migration up to and including the target version, in order.
+// A target of Latest applies every known migration.
+// Each migration runs in its own transaction together with its schema_migrations row.
+// It returns an error without applying anything if an applied migration was changed or is unknown to this binary.
+func (m *Migrator) Up(ctx context.Context, target int) error {
+	return m.locked(ctx, func(conn *sqlx.Conn, applied map[int]string) error {
+		for _, migration := range m.migrations {
+			if target != Latest && migration.Version > target {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := m.apply(ctx, conn, migration)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down is a method on the Migrator struct.
+// It reverts every applied migration newer than the target version, from the newest to the oldest.
+// A target of 0 reverts every migration.
+// It returns an error if an applied migration was changed, is unknown to this binary, or has no down statements.
+func (m *Migrator) Down(ctx context.Context, target int) error {
+	return m.locked(ctx, func(conn *sqlx.Conn, applied map[int]string) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= target {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err := m.revert(ctx, conn, migration)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Pending is a method on the Migrator struct.
+// It returns the known migrations that are not applied yet, ordered by version.
+// It does not take the migration lock and does not change the schema except for creating the schema_migrations table.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	err = m.ensureTable(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// locked is a method on the Migrator struct.
+// It takes the migration lock on a dedicated connection, makes sure the schema_migrations table exists,
+// verifies the applied migrations against the known ones, and calls fn with the applied versions and their checksums.
+// The lock is released when fn returns.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sqlx.Conn, applied map[int]string) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer unlock()
+
+	err = m.ensureTable(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	err = m.verify(applied)
+	if err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+// lock is a method on the Migrator struct.
+// It takes the Postgres advisory lock on the connection and returns the function that releases it.
+// For other drivers it takes no lock and returns a function that does nothing.
+func (m *Migrator) lock(ctx context.Context, conn *sqlx.Conn) (func(), error) {
+	if m.db.DriverName() != "postgres" {
+		return func() {}, nil
+	}
+	_, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		// Use a fresh context so the lock is released even if ctx is already cancelled
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+		if err != nil {
+			logger.Error("Failed to release the migration lock", zap.Error(err))
+		}
+	}, nil
+}
+
+// ensureTable is a method on the Migrator struct.
+// It creates the schema_migrations table if it does not exist yet.
+func (m *Migrator) ensureTable(ctx context.Context, conn *sqlx.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create the schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// applied is a method on the Migrator struct.
+// It returns the applied versions and their checksums from the schema_migrations table.
+func (m *Migrator) applied(ctx context.Context, conn *sqlx.Conn) (map[int]string, error) {
+	rows, err := conn.QueryxContext(ctx, `SELECT version, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+// verify is a method on the Migrator struct.
+// It checks that every applied version is known to this binary and that its up statements were not changed since.
+// It returns an error describing the first drift it finds.
+func (m *Migrator) verify(applied map[int]string) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	for version, checksum := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("applied migration %d is unknown, the database is newer than this build", version)
+		}
+		if migration.Checksum != checksum {
+			return fmt.Errorf("migration %d_%s was changed after it was applied: checksum %s, applied %s",
+				version, migration.Name, migration.Checksum, checksum)
+		}
+	}
+	return nil
+}
+
+// apply is a method on the Migrator struct.
+// It runs the up statements of a migration and records it in schema_migrations in one transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	err := inTx(ctx, conn, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, migration.Up)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			m.db.Rebind(`INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)`),
+			migration.Version, migration.Name, migration.Checksum)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	logger.Info("Applied migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	return nil
+}
+
+// revert is a method on the Migrator struct.
+// It runs the down statements of a migration and removes it from schema_migrations in one transaction.
+func (m *Migrator) revert(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %d_%s cannot be reverted, it has no %s", migration.Version, migration.Name, downFile)
+	}
+	err := inTx(ctx, conn, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, migration.Down)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, m.db.Rebind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	logger.Info("Reverted migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	return nil
+}
+
+// inTx is a function that runs fn in a transaction on the connection.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+func inTx(ctx context.Context, conn *sqlx.Conn, fn func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
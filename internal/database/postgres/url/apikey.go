@@ -0,0 +1,96 @@
+package url
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/database/postgres/url/converter"
+	repoModel "github.com/t1ltxz-gxd/shortify/internal/database/postgres/url/models"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// CreateAPIKey is a method that adds a new API key to the api_keys table.
+// It takes a context for managing the lifecycle of the operation, and the key with its ID, name, hash and creation time.
+// It returns an error if the operation fails, or if the ID or the hash is already taken.
+func (d *database) CreateAPIKey(ctx context.Context, key *models.APIKey) (err error) {
+	ctx, span := tracing.Start(ctx, "postgres.CreateAPIKey", semconv.DBSystemPostgreSQL, semconv.DBOperation("INSERT"))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	_, err = d.db.ExecContext(ctx, `INSERT INTO api_keys (id, name, key_hash, created_at) VALUES ($1, $2, $3, $4)`,
+		key.ID, key.Name, key.Hash, key.CreatedAt)
+	return err
+}
+
+// GetAPIKey is a method that retrieves an active API key using the hash of the key.
+// It takes a context for managing the lifecycle of the operation, and the hex-encoded SHA-256 hash of the key.
+// It always reads the primary, so a revoked key stops authenticating at once, even while the replicas lag behind.
+// It returns the key, nil for both the key and the error if there is no active key with the hash,
+// and an error if the query fails.
+func (d *database) GetAPIKey(ctx context.Context, hash string) (_ *models.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "postgres.GetAPIKey", semconv.DBSystemPostgreSQL, semconv.DBOperation("SELECT"))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	var key repoModel.APIKey
+	err = d.db.GetContext(ctx, &key, `SELECT * FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return converter.ToAPIKeyFromRepo(key), nil
+}
+
+// ListAPIKeys is a method that retrieves every API key, the revoked ones included.
+// It takes a context for managing the lifecycle of the operation.
+// It returns the keys, the newest first, and an error if the query fails.
+func (d *database) ListAPIKeys(ctx context.Context) (_ []*models.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "postgres.ListAPIKeys", semconv.DBSystemPostgreSQL, semconv.DBOperation("SELECT"))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	var rows []repoModel.APIKey
+	err = d.db.SelectContext(ctx, &rows, `SELECT * FROM api_keys ORDER BY created_at DESC, id`)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*models.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, converter.ToAPIKeyFromRepo(row))
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey is a method that marks an API key as revoked, so it no longer authenticates.
+// It takes a context for managing the lifecycle of the operation, and the ID of the key.
+// It returns models.ErrorAPIKeyNotFound if there is no active key with the ID, and an error if the query fails.
+func (d *database) RevokeAPIKey(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "postgres.RevokeAPIKey", semconv.DBSystemPostgreSQL, semconv.DBOperation("UPDATE"))
+	defer func() { tracing.End(span, err, models.ErrorAPIKeyNotFound) }()
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	res, err := d.db.ExecContext(ctx, `UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrorAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package url
+
+import (
+	"errors"
+)
+
+// Close is a method that closes the connection pools of the read replicas and of the primary.
+// It waits for the queries in progress to finish.
+// It returns the errors of the pools that could not be closed.
+func (d *database) Close() error {
+	var errs []error
+	if d.replicas != nil {
+		for _, r := range d.replicas.replicas {
+			errs = append(errs, r.db.Close())
+		}
+	}
+	errs = append(errs, d.db.Close())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package url
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DSN is a function that builds the connection string of the Postgres database from the postgres settings.
+// If postgres.dsn is set, it is returned as is.
+// Otherwise the string is built from postgres.host, postgres.port, postgres.user, postgres.password and postgres.db,
+// which can also be set by the POSTGRES_HOST, POSTGRES_PORT, POSTGRES_USER, POSTGRES_PASSWORD and POSTGRES_DB environment variables,
+// and from the TLS settings postgres.sslmode, postgres.sslrootcert, postgres.sslcert and postgres.sslkey.
+func DSN(cfg config.Postgres) string {
+	if cfg.DSN != "" {
+		return cfg.DSN
+	}
+
+	query := url.Values{}
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	query.Set("sslmode", sslMode)
+	for key, value := range map[string]string{"sslrootcert": cfg.SSLRootCert, "sslcert": cfg.SSLCert, "sslkey": cfg.SSLKey} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:     "/" + cfg.DB,
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
+}
+
+// Connect is a function that opens a pool of connections to the Postgres database at dsn.
+// It takes a context for managing the lifecycle of the connection attempts, the connection string, and the postgres settings.
+// It sizes the pool from the postgres.pool.maxOpen, postgres.pool.maxIdle and postgres.pool.connMaxLifetime settings.
+// While the database cannot be reached, it retries with an interval that starts at postgres.retry.initialInterval
+// and doubles up to postgres.retry.maxInterval, both in milliseconds, so the application survives Postgres starting after it.
+// It gives up after postgres.retry.attempts attempts, or never if that is zero, and when the context is done.
+// It returns the error of the last attempt if it gives up.
+func Connect(ctx context.Context, dsn string, cfg config.Postgres) (*sqlx.DB, error) {
+	attempts := cfg.Retry.Attempts
+	interval := time.Duration(cfg.Retry.InitialInterval) * time.Millisecond
+	maxInterval := time.Duration(cfg.Retry.MaxInterval) * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		db, err := sqlx.ConnectContext(ctx, "postgres", dsn)
+		if err == nil {
+			configurePool(db, cfg.Pool)
+			return db, nil
+		}
+		if attempts > 0 && attempt >= attempts {
+			return nil, fmt.Errorf("failed to connect after %d attempts: %w", attempt, err)
+		}
+
+		logger.Warn("Failed to connect to the database, retrying",
+			zap.Int("attempt", attempt), zap.Duration("retryIn", interval), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up connecting after %d attempts: %w", attempt, err)
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if maxInterval > 0 && interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// configurePool is a function that sizes the connection pool of db from the postgres.pool settings.
+// A setting of zero keeps the default of database/sql.
+func configurePool(db *sqlx.DB, pool config.PostgresPool) {
+	if pool.MaxOpen > 0 {
+		db.SetMaxOpenConns(pool.MaxOpen)
+	}
+	if pool.MaxIdle > 0 {
+		db.SetMaxIdleConns(pool.MaxIdle)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(time.Duration(pool.ConnMaxLifetime) * time.Second)
+	}
+}
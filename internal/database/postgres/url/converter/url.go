@@ -5,6 +5,7 @@ import (
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
 	"go.uber.org/zap"
+	"time"
 )
 
 // ToURLFromRepo is a function that converts a URL from the repository model to the service model.
@@ -14,12 +15,19 @@ import (
 // It returns a pointer to a URL from the service model.
 // The URL from the service model has the original URL, the hash, the time when the URL was added, and a pointer to the time when the URL was last updated.
 // If the URL from the repository model has not been updated, the pointer to the time when the URL was last updated is nil.
+// If the URL from the repository model never expires, the pointer to the time when it expires is nil.
 func ToURLFromRepo(url repoModels.URL) *models.URL {
 	logger.Debug("Converting URL from repository to service", zap.String("original", url.Original), zap.String("short", url.Hash)) // Log the conversion
+	var expiresAt *time.Time
+	if url.ExpiresAt.Valid {
+		expiresAt = &url.ExpiresAt.Time
+	}
 	return &models.URL{
 		Original:  url.Original,        // Set the original URL
 		Hash:      url.Hash,            // Set the hash
 		AddedAt:   url.AddedAt,         // Set the time when the URL was added
 		UpdatedAt: &url.UpdatedAt.Time, // Set the pointer to the time when the URL was last updated
+		ExpiresAt: expiresAt,           // Set the pointer to the time when the URL expires
+		Owner:     url.Owner,           // Set the owner of the URL
 	}
 }
@@ -2,32 +2,53 @@ package url
 
 import (
 	"context"
-	"database/sql"
-	repoModel "github.com/t1ltxz-gxd/shortify/internal/database/postgres/url/models"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.uber.org/zap"
 	"time"
 )
 
 // Create is a method that adds a new URL to the database.
 // It takes a context for managing the lifecycle of the operation,
-// a url which is the actual URL string, and a hash which is the unique identifier for the URL.
-// It first constructs the SQL query to insert the URL into the database.
-// It then executes the query, passing in a new URL model with the provided URL, hash, and the current time for the added and updated timestamps.
+// a url which is the actual URL string, a hash which is the unique identifier for the URL,
+// the subject of the caller that owns the URL, and the time when the URL expires, or nil if it never expires.
+// If the hash belongs to a URL that was deleted or has expired but is not purged yet, the row is taken over by the new URL.
+// If the hash is taken by a live URL, it returns models.ErrorURLExists.
 // If an error occurs during the execution of the query, it logs an error message and returns the error.
-// If the operation is successful, it returns nil.
-func (d *database) Create(_ context.Context, url string, hash string) error {
-	// The SQL query to insert the URL into the database
-	query := `INSERT INTO urls (original_url, hash) VALUES (:original_url, :hash)`
-	_, err := d.db.NamedExec(query, &repoModel.URL{
-		Original:  url,                                         // Set the original URL
-		Hash:      hash,                                        // Set the hash
-		AddedAt:   time.Now(),                                  // Set the time when the URL was added
-		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true}, // Set the time when the URL was updated
-	})
+// If the operation is successful, it reads the hash from the primary for a while and returns nil.
+func (d *database) Create(ctx context.Context, url, hash, owner string, expiresAt *time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "postgres.Create", semconv.DBSystemPostgreSQL, semconv.DBOperation("INSERT"), tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err, models.ErrorURLExists) }()
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	// The SQL query to insert the URL into the database, or to reuse the row of a dead URL with the same hash
+	query := `INSERT INTO urls (original_url, hash, owner, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (hash) DO UPDATE SET
+			original_url = EXCLUDED.original_url,
+			owner = EXCLUDED.owner,
+			added_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP,
+			expires_at = EXCLUDED.expires_at,
+			deleted_at = NULL,
+			disabled_at = NULL
+		WHERE urls.deleted_at IS NOT NULL OR urls.expires_at <= now()`
+	res, err := d.db.ExecContext(ctx, query, url, hash, owner, expiresAt)
 	if err != nil {
 		logger.Error("Failed to insert URL into the database", zap.Error(err)) // Log the error if the creation fails
 		return err
 	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// The conflicting row belongs to a live URL
+		return models.ErrorURLExists
+	}
+	d.wrote(hash)
 	return nil
 }
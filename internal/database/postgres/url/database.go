@@ -1,58 +1,116 @@
 package url
 
 import (
-	"fmt"
+	"context"
 	"github.com/jmoiron/sqlx"
-	"github.com/spf13/viper"
+	// reviving the pq driver
+	_ "github.com/lib/pq"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
 	def "github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/database/migrator"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/migrations"
 	"go.uber.org/zap"
-	"os"
+	"time"
 )
 
-var _ def.URLDatabase = (*database)(nil)
+var (
+	_ def.URLDatabase    = (*database)(nil)
+	_ def.Purger         = (*database)(nil)
+	_ def.Searcher       = (*database)(nil)
+	_ def.Disabler       = (*database)(nil)
+	_ def.APIKeyDatabase = (*database)(nil)
+	_ def.Invalidator    = (*database)(nil)
+)
 
 type database struct {
-	db *sqlx.DB // The database connection
+	db            *sqlx.DB      // The connection to the primary, used for writes and as the fallback for reads
+	replicas      *replicaSet   // The read replicas, nil if none are configured
+	recent        *recentWrites // The hashes recently written through this instance or another one, read from the primary
+	timeout       time.Duration // The timeout of a single query
+	migrationsDir string        // The directory to read the migrations from, empty for the embedded ones
 }
 
-func Init() def.URLDatabase {
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		viper.GetString("postgresHost"),
-		os.Getenv("POSTGRES_PORT"),
-		os.Getenv("POSTGRES_DB"))
-	db, err := sqlx.Connect("postgres", dsn)
+// Open is a function that connects to the Postgres database at dsn and returns it as a URLDatabase.
+// It takes a context for managing the lifecycle of the connection attempt,
+// the connection string, and the timeout of a single query.
+// It returns an error if the connection fails.
+func Open(ctx context.Context, dsn string, timeout time.Duration) (def.URLDatabase, error) {
+	db, err := sqlx.ConnectContext(ctx, "postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &database{
+		db:      db,      // Set the connection to the primary
+		timeout: timeout, // Set the query timeout
+	}, nil
+}
+
+// Init is a function that connects to the Postgres database.
+// It takes a context for managing the lifecycle of the connection attempts and of the replica health checks,
+// and the configuration of the application.
+// It builds the connection string and the pool from the postgres settings, retrying with backoff while the database is not up yet,
+// and reads the timeout of a single query from the storage.timeout setting in milliseconds
+// and the directory of the migrations from the migrations.dir setting.
+// If postgres.replicas.dsns lists read replicas, lookups are spread over the healthy ones,
+// which are checked every postgres.replicas.healthInterval seconds, while the hashes written through this instance,
+// or through another one as announced on the cache invalidation bus,
+// are read from the primary for postgres.replicas.readAfterWrite milliseconds after the write.
+// If every connection attempt fails, it logs a fatal error.
+func Init(ctx context.Context, cfg *config.Config) def.URLDatabase {
+	db, err := Connect(ctx, DSN(cfg.Postgres), cfg.Postgres)
 	if err != nil {
 		logger.Fatal("failed to connect to database", zap.Error(err))
 	}
+	replicas, err := newReplicaSet(ctx,
+		cfg.Postgres.Replicas.DSNs,
+		time.Duration(cfg.Postgres.Replicas.HealthInterval)*time.Second,
+		cfg.Postgres.Pool)
+	if err != nil {
+		logger.Fatal("failed to open read replicas", zap.Error(err))
+	}
+	readAfterWrite := time.Duration(cfg.Postgres.Replicas.ReadAfterWrite) * time.Millisecond
 	return &database{
-		db: db, // Set the database connection
+		db:            db,                                                    // Set the connection to the primary
+		replicas:      replicas,                                              // Set the read replicas
+		recent:        newRecentWrites(readAfterWrite),                       // Set the read-your-own-write window
+		timeout:       time.Duration(cfg.Storage.Timeout) * time.Millisecond, // Set the query timeout
+		migrationsDir: cfg.Migrations.Dir,                                    // Set the directory of the migrations
+	}
+}
+
+// withTimeout is a method on the database struct.
+// It derives a context that is cancelled when the timeout of a query expires,
+// or when the parent context is done, whichever happens first.
+// A timeout of zero leaves the parent context unchanged.
+func (d *database) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, d.timeout)
 }
 
-// ApplyMigrations is a method on the App struct.
-// It applies the database migrations for the application.
-// It takes a sqlx.DB pointer and a slice of migration file paths as parameters and returns an error.
-// It iterates over the slice of migration file paths and reads each file.
-// If the read returns an error, applyMigration returns the error.
-// If the read does not return an error, applyMigration executes the migration on the database.
-// If the execution returns an error, applyMigration returns the error.
-// If the execution does not return an error, applyMigration logs that the migration was applied successfully.
-// After all migrations have been applied, applyMigration returns nil.
-func (d *database) ApplyMigrations(migrationFiles []string) error {
-	for _, migrationFile := range migrationFiles {
-		migration, err := os.ReadFile(migrationFile)
-		if err != nil {
-			return err
-		}
-		_, err = d.db.Exec(string(migration))
-		if err != nil {
-			return err
-		}
-
-		logger.Info("Applied migration from file %s", zap.String("file", migrationFile))
-	}
-	return nil
+// ApplyMigrations is a method on the database struct.
+// It applies every pending Postgres migration embedded in the binary,
+// or from the postgres sub-directory of the migrations.dir setting if it is set.
+// It takes a context for managing the lifecycle of the operation.
+// It logs the pending versions before applying them.
+// The migrator holds an advisory lock while it runs, so instances starting together do not race,
+// and it refuses to migrate if an applied migration was changed since it was applied.
+// It returns an error if the migrations cannot be loaded or applied.
+func (d *database) ApplyMigrations(ctx context.Context) error {
+	m, err := migrator.New(d.db, migrations.Source(d.migrationsDir, migrations.Postgres))
+	if err != nil {
+		return err
+	}
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range pending {
+		logger.Info("Pending migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	}
+
+	return m.Up(ctx, migrator.Latest)
 }
@@ -0,0 +1,80 @@
+package url_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/database/databasetest"
+	pgURL "github.com/t1ltxz-gxd/shortify/internal/database/postgres/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+// dsnEnv is the environment variable holding the connection string of a Postgres database the tests may wipe.
+const dsnEnv = "SHORTIFY_TEST_POSTGRES_DSN"
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// TestConformance is a test function that runs the storage conformance suite against the Postgres database of SHORTIFY_TEST_POSTGRES_DSN,
+// emptied before every case. It is skipped if the variable is not set.
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+
+	// A second connection empties the table between the cases
+	raw, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = raw.Close() })
+
+	databasetest.Run(t, func(t *testing.T) database.URLDatabase {
+		ctx := context.Background()
+		db, err := pgURL.Open(ctx, dsn, 0)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		require.NoError(t, db.ApplyMigrations(ctx))
+		_, err = raw.ExecContext(ctx, `TRUNCATE urls, api_keys`)
+		require.NoError(t, err)
+		return db
+	})
+}
+
+// TestConformance_Replicas is a test function that runs the storage conformance suite with the lookups routed through a read replica,
+// the primary itself, so the replica routing behaves like a single database. It is skipped if SHORTIFY_TEST_POSTGRES_DSN is not set.
+func TestConformance_Replicas(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+
+	// The primary doubles as its own replica, so the lookups go through the replica routing
+	cfg := &config.Config{Postgres: config.Postgres{
+		DSN:      dsn,
+		Replicas: config.PostgresReplicas{DSNs: []string{dsn}, HealthInterval: 5, ReadAfterWrite: 100},
+		Retry:    config.PostgresRetry{Attempts: 1, InitialInterval: 100, MaxInterval: 100},
+	}}
+
+	raw, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = raw.Close() })
+
+	databasetest.Run(t, func(t *testing.T) database.URLDatabase {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		db := pgURL.Init(ctx, cfg)
+		t.Cleanup(func() { _ = db.Close() })
+		require.NoError(t, db.ApplyMigrations(ctx))
+		_, err := raw.ExecContext(ctx, `TRUNCATE urls, api_keys`)
+		require.NoError(t, err)
+		return db
+	})
+}
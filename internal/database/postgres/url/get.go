@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"github.com/jmoiron/sqlx"
 	"github.com/t1ltxz-gxd/shortify/internal/database/postgres/url/converter"
 	repoModel "github.com/t1ltxz-gxd/shortify/internal/database/postgres/url/models"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.uber.org/zap"
 )
 
@@ -23,10 +26,21 @@ import (
 // and it converts the retrieved URL from the repository model to the application model.
 // It returns a pointer to the URL model if the operation is successful,
 // and an error if the operation fails or if the URL is not found in the database.
-func (d *database) Get(_ context.Context, hash string) (*models.URL, error) {
+// A URL that was deleted, has expired or was disabled is not found.
+// It reads from a healthy replica if there is one and the hash was not written recently through this instance,
+// and from the primary otherwise, or if the replica fails or does not have the URL.
+func (d *database) Get(ctx context.Context, hash string) (_ *models.URL, err error) {
+	ctx, span := tracing.Start(ctx, "postgres.Get", semconv.DBSystemPostgreSQL, semconv.DBOperation("SELECT"), tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
 	var url repoModel.URL
 	logger.Debug("Fetching URL from database", zap.String("hash", hash))
-	err := d.db.Get(&url, "SELECT * FROM urls WHERE hash = $1", hash)
+	err = d.readFrom(ctx, d.reader(hash), func(db *sqlx.DB) error {
+		return db.GetContext(ctx, &url, selectLive, hash)
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// If the URL is not in the database, return nil
@@ -39,3 +53,7 @@ func (d *database) Get(_ context.Context, hash string) (*models.URL, error) {
 	logger.Debug("URL is fetched from the database", zap.String("url", url.Original))
 	return converter.ToURLFromRepo(url), nil
 }
+
+// selectLive is the query of a URL that is neither deleted, expired nor disabled.
+const selectLive = `SELECT * FROM urls
+	WHERE hash = $1 AND deleted_at IS NULL AND disabled_at IS NULL AND (expires_at IS NULL OR expires_at > now())`
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// APIKey is a struct that represents a row of the api_keys table.
+// ID is a string that identifies the key.
+// Name is a string that describes who the key was issued to.
+// Hash is a string that holds the hex-encoded SHA-256 hash of the key.
+// CreatedAt is a time.Time value that holds the time when the key was created.
+// RevokedAt is a sql.NullTime value that holds the time when the key was revoked, nil while it is active.
+type APIKey struct {
+	ID        string       `db:"id"`         // The identifier of the key
+	Name      string       `db:"name"`       // The name of the holder of the key
+	Hash      string       `db:"key_hash"`   // The SHA-256 hash of the key
+	CreatedAt time.Time    `db:"created_at"` // The time when the key was created
+	RevokedAt sql.NullTime `db:"revoked_at"` // The time when the key was revoked, nil while it is active
+}
@@ -12,9 +12,20 @@ import (
 // AddedAt is a time.Time value that holds the time when the URL was added to the application.
 // UpdatedAt is a sql.NullTime value that holds the time when the URL was last updated in the application.
 // If the URL has not been updated, UpdatedAt is nil.
+// ExpiresAt is a sql.NullTime value that holds the time after which the URL no longer resolves, nil if it never expires.
+// DeletedAt is a sql.NullTime value that holds the time when the URL was deleted, nil while it is live.
+// DisabledAt is a sql.NullTime value that holds the time when the URL was disabled by an operator, nil while it resolves.
+// Host is a sql.NullString value that holds the host of the original URL, generated by the database for the searches.
+// Owner is a string that holds the subject of the caller that created the URL, empty if it was created anonymously.
+// The subject is key:<id> for an API key and jwt:<iss>|<sub> for a bearer token.
 type URL struct {
-	Original  string       `db:"original_url"` // The original URL
-	Hash      string       `db:"hash"`         // The hashed version of the original URL
-	AddedAt   time.Time    `db:"added_at"`     // The time when the URL was added
-	UpdatedAt sql.NullTime `db:"updated_at"`   // The time when the URL was last updated, nil if not updated
+	Original   string         `db:"original_url"` // The original URL
+	Hash       string         `db:"hash"`         // The hashed version of the original URL
+	AddedAt    time.Time      `db:"added_at"`     // The time when the URL was added
+	UpdatedAt  sql.NullTime   `db:"updated_at"`   // The time when the URL was last updated, nil if not updated
+	ExpiresAt  sql.NullTime   `db:"expires_at"`   // The time when the URL expires, nil if it never expires
+	DeletedAt  sql.NullTime   `db:"deleted_at"`   // The time when the URL was deleted, nil while it is live
+	DisabledAt sql.NullTime   `db:"disabled_at"`  // The time when the URL was disabled, nil while it resolves
+	Host       sql.NullString `db:"host"`         // The host of the original URL, only stored by PostgreSQL
+	Owner      string         `db:"owner"`        // The subject of the caller that created the URL
 }
@@ -0,0 +1,16 @@
+package url
+
+import (
+	"context"
+)
+
+// Ping is a method that checks whether the primary database is reachable.
+// The read replicas are left out, they have health checks of their own and the reads fall back to the primary.
+// It takes a context for managing the lifecycle of the operation.
+// It returns an error if the primary does not answer within the query timeout.
+func (d *database) Ping(ctx context.Context) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	return d.db.PingContext(ctx)
+}
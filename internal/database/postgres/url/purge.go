@@ -0,0 +1,69 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.uber.org/zap"
+	"time"
+)
+
+// purgeLockKey is the key of the Postgres advisory lock held while purging.
+// It keeps the instances from purging at the same time and deleting the same rows twice.
+const purgeLockKey int64 = 7_283_462_112
+
+// Purge is a method that removes the expired URLs and the URLs deleted longer than retention ago for good.
+// It takes a context for managing the lifecycle of the operation, the retention of deleted URLs,
+// and the number of rows to remove in one statement.
+// It holds an advisory lock on a dedicated connection while it runs; if another instance holds it, it returns at once.
+// It removes the rows in batches until a batch comes back short, so every statement holds its locks briefly.
+// It returns the number of rows removed, and an error if a statement fails.
+func (d *database) Purge(ctx context.Context, retention time.Duration, batchSize int) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "postgres.Purge", semconv.DBSystemPostgreSQL, semconv.DBOperation("DELETE"))
+	defer func() { tracing.End(span, err) }()
+
+	conn, err := d.db.Connx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	err = conn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock($1)`, purgeLockKey)
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		logger.Debug("Another instance is purging the database")
+		return 0, nil
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx is already cancelled
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, purgeLockKey)
+		if err != nil {
+			logger.Error("Failed to release the purge lock", zap.Error(err))
+		}
+	}()
+
+	var total int64
+	for {
+		queryCtx, cancel := d.withTimeout(ctx)
+		res, err := conn.ExecContext(queryCtx, `DELETE FROM urls WHERE hash IN (
+			SELECT hash FROM urls
+			WHERE expires_at <= now() OR deleted_at <= now() - make_interval(secs => $1)
+			LIMIT $2)`, retention.Seconds(), batchSize)
+		cancel()
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package url
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultHealthInterval is the interval between two health checks of the replicas if none is configured.
+const defaultHealthInterval = 5 * time.Second
+
+// replica is a struct that holds a connection pool to a read replica and whether it passed its last health check.
+type replica struct {
+	index   int         // The position of the replica in the postgres.replicas.dsns setting, used in logs
+	db      *sqlx.DB    // The connection pool of the replica
+	healthy atomic.Bool // Whether the replica answered its last health check
+}
+
+// replicaSet is a struct that spreads reads over the healthy read replicas in turn.
+// A background loop pings every replica, taking failed ones out of rotation and bringing recovered ones back.
+type replicaSet struct {
+	replicas []*replica    // The read replicas
+	next     atomic.Uint32 // The position of the next replica to try
+	interval time.Duration // The interval between two health checks
+}
+
+// newReplicaSet is a function that opens a pool to every replica in dsns and starts checking their health.
+// The pools are opened lazily, so an unreachable replica does not hold up the startup; it joins the rotation
+// as soon as it answers a health check.
+// The health checks run until the context is done.
+// An interval of zero checks them every defaultHealthInterval.
+// The pools are sized like the pool of the primary.
+// It returns nil if dsns is empty.
+func newReplicaSet(ctx context.Context, dsns []string, interval time.Duration, pool config.PostgresPool) (*replicaSet, error) {
+	if len(dsns) == 0 {
+		return nil, nil
+	}
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	set := &replicaSet{interval: interval}
+	for i, dsn := range dsns {
+		db, err := sqlx.Open("postgres", dsn)
+		if err != nil {
+			return nil, err
+		}
+		configurePool(db, pool)
+		set.replicas = append(set.replicas, &replica{index: i, db: db})
+	}
+
+	set.check(ctx)
+	go set.run(ctx)
+
+	return set, nil
+}
+
+// run is a method on the replicaSet struct.
+// It checks the health of the replicas every interval until the context is done.
+func (s *replicaSet) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.check(ctx)
+		}
+	}
+}
+
+// check is a method on the replicaSet struct.
+// It pings every replica at once and records which ones answered, logging every change.
+func (s *replicaSet) check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, r := range s.replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, s.interval)
+			defer cancel()
+			err := r.db.PingContext(pingCtx)
+			if err != nil {
+				s.markDown(r, err)
+				return
+			}
+			if !r.healthy.Swap(true) {
+				logger.Info("Read replica is up", zap.Int("replica", r.index))
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+// markDown is a method on the replicaSet struct.
+// It takes a replica out of rotation until it passes a health check again.
+func (s *replicaSet) markDown(r *replica, err error) {
+	if r.healthy.Swap(false) {
+		logger.Warn("Read replica is down, reading from the primary", zap.Int("replica", r.index), zap.Error(err))
+	}
+}
+
+// pick is a method on the replicaSet struct.
+// It returns the next healthy replica in turn, or nil if no replica is healthy or the set is nil because none are configured.
+func (s *replicaSet) pick() *replica {
+	if s == nil {
+		return nil
+	}
+	n := len(s.replicas)
+	start := int(s.next.Add(1))
+	for i := 0; i < n; i++ {
+		r := s.replicas[(start+i)%n]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// recentWrites is a struct that remembers the hashes written through this instance or another one for a short window.
+// Reads of those hashes go to the primary, so a client sees its own write even if the replicas lag behind,
+// and a cache evicted on behalf of another instance is not filled again with the stale row of a replica.
+type recentWrites struct {
+	m      sync.Mutex           // Guards until and all
+	until  map[string]time.Time // The time until which each hash is read from the primary
+	all    time.Time            // The time until which every hash is read from the primary
+	window time.Duration        // How long a hash is read from the primary after it was written
+	pruned time.Time            // The last time the expired hashes were forgotten
+}
+
+// newRecentWrites is a function that creates an empty set of recent writes with the given window.
+func newRecentWrites(window time.Duration) *recentWrites {
+	return &recentWrites{
+		until:  make(map[string]time.Time),
+		window: window,
+	}
+}
+
+// add is a method on the recentWrites struct.
+// It records that the hash was just written.
+// Once per window it also forgets the hashes whose window has passed, so the map only holds the recent writes.
+func (w *recentWrites) add(hash string) {
+	now := time.Now()
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.until[hash] = now.Add(w.window)
+	if now.Sub(w.pruned) < w.window {
+		return
+	}
+	for h, until := range w.until {
+		if now.After(until) {
+			delete(w.until, h)
+		}
+	}
+	w.pruned = now
+}
+
+// addAll is a method on the recentWrites struct.
+// It records that any hash may just have been written, so every hash is read from the primary for a window.
+func (w *recentWrites) addAll() {
+	now := time.Now()
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.all = now.Add(w.window)
+}
+
+// has is a method on the recentWrites struct.
+// It reports whether the hash, or any hash, was written within the window.
+func (w *recentWrites) has(hash string) bool {
+	now := time.Now()
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if now.Before(w.all) {
+		return true
+	}
+	until, ok := w.until[hash]
+	return ok && now.Before(until)
+}
+
+// wrote is a method on the database struct.
+// It records a write of the hash, so the lookups of the hash read from the primary for a while.
+// It does nothing if there are no replicas.
+func (d *database) wrote(hash string) {
+	if d.replicas != nil {
+		d.recent.add(hash)
+	}
+}
+
+// Invalidate is a method that records a write of the hash by another instance, delivered by the cache invalidation bus,
+// so the lookups of the hash read from the primary for a while and do not cache the stale row of a lagging replica again.
+// It does nothing if there are no replicas.
+func (d *database) Invalidate(_ context.Context, hash string) {
+	d.wrote(hash)
+}
+
+// Flush is a method that reads every hash from the primary for a while,
+// because the cache invalidation bus may have missed writes by other instances.
+// It does nothing if there are no replicas.
+func (d *database) Flush(_ context.Context) {
+	if d.replicas != nil {
+		d.recent.addAll()
+	}
+}
+
+// reader is a method on the database struct.
+// It returns the replica a lookup of the hash should read from,
+// or nil if it should read from the primary because there is no healthy replica or the hash was written recently.
+func (d *database) reader(hash string) *replica {
+	if d.replicas == nil || d.recent.has(hash) {
+		return nil
+	}
+	return d.replicas.pick()
+}
+
+// readFrom is a method on the database struct.
+// It runs the read on the replica, and again on the primary if the replica is nil, fails,
+// or does not have the row yet because it lags behind.
+// A replica that fails for another reason than a missing row is taken out of rotation.
+// The read is not repeated on the primary once the context is done.
+// It returns the error of the last read.
+func (d *database) readFrom(ctx context.Context, r *replica, read func(db *sqlx.DB) error) error {
+	if r != nil {
+		err := read(r.db)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			d.replicas.markDown(r, err)
+		}
+	}
+	return read(d.db)
+}
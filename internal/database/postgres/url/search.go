@@ -0,0 +1,59 @@
+package url
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/t1ltxz-gxd/shortify/internal/database/postgres/url/converter"
+	repoModel "github.com/t1ltxz-gxd/shortify/internal/database/postgres/url/models"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"strings"
+)
+
+// searchQuery finds the live URLs of the owner $6 whose original URL or host contains $2, ignoring case.
+// The trigram indexes serve the ILIKE filters. The URLs are ordered by how similar their original URL or host is to $1,
+// with the URLs whose host starts with $1 first, so "example.com" ranks example.com links above links that only mention it.
+const searchQuery = `SELECT * FROM urls
+	WHERE deleted_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+		AND owner = $6 AND (original_url ILIKE $2 OR host ILIKE $2)
+	ORDER BY coalesce(host ILIKE $3, false) DESC,
+		GREATEST(similarity(original_url, $1), similarity(coalesce(host, ''), $1)) DESC,
+		hash
+	LIMIT $4 OFFSET $5`
+
+// Search is a method that finds the live URLs of an owner whose original URL or host contains the query, ignoring case.
+// It takes a context for managing the lifecycle of the operation, the owner, the query,
+// and the number of URLs to return and to skip, for pagination.
+// It reads from a healthy replica if there is one, and from the primary otherwise or if the replica fails.
+// It returns the matching URLs, the most relevant first, and an error if the operation fails.
+func (d *database) Search(ctx context.Context, owner, query string, limit, offset int) (_ []*models.URL, err error) {
+	ctx, span := tracing.Start(ctx, "postgres.Search", semconv.DBSystemPostgreSQL, semconv.DBOperation("SELECT"))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	pattern := escapeLike(query)
+	args := []any{query, "%" + pattern + "%", pattern + "%", limit, offset, owner}
+
+	var rows []repoModel.URL
+	err = d.readFrom(ctx, d.replicas.pick(), func(db *sqlx.DB) error {
+		rows = rows[:0] // Drop the rows of a replica that failed midway
+		return db.SelectContext(ctx, &rows, searchQuery, args...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]*models.URL, 0, len(rows))
+	for _, row := range rows {
+		urls = append(urls, converter.ToURLFromRepo(row))
+	}
+	return urls, nil
+}
+
+// escapeLike is a function that escapes the wildcards of a LIKE pattern, so the query matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
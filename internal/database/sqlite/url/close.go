@@ -0,0 +1,8 @@
+package url
+
+// Close is a method that closes the connection to the database file.
+// It waits for the queries in progress to finish, and checkpoints the write-ahead log into the file.
+// It returns an error if the connection could not be closed.
+func (d *database) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,25 @@
+package converter
+
+import (
+	repoModels "github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url/models"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"time"
+)
+
+// ToAPIKeyFromRepo is a function that converts an API key from the SQLite repository model to the service model.
+// It takes an API key from the repository model as a parameter.
+// It returns a pointer to an API key from the service model.
+// If the key is active, the pointer to the time when it was revoked is nil.
+func ToAPIKeyFromRepo(key repoModels.APIKey) *models.APIKey {
+	var revokedAt *time.Time
+	if key.RevokedAt.Valid {
+		revokedAt = &key.RevokedAt.Time
+	}
+	return &models.APIKey{
+		ID:        key.ID,        // Set the identifier of the key
+		Name:      key.Name,      // Set the name of the holder of the key
+		Hash:      key.Hash,      // Set the hash of the key
+		CreatedAt: key.CreatedAt, // Set the time when the key was created
+		RevokedAt: revokedAt,     // Set the pointer to the time when the key was revoked
+	}
+}
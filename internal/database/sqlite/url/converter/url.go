@@ -0,0 +1,26 @@
+package converter
+
+import (
+	repoModels "github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url/models"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"time"
+)
+
+// ToURLFromRepo is a function that converts a URL from the SQLite repository model to the service model.
+// It takes a URL from the repository model as a parameter.
+// It returns a pointer to a URL from the service model.
+// If the URL never expires, the pointer to the time when it expires is nil.
+func ToURLFromRepo(url repoModels.URL) *models.URL {
+	var expiresAt *time.Time
+	if url.ExpiresAt.Valid {
+		expiresAt = &url.ExpiresAt.Time
+	}
+	return &models.URL{
+		Original:  url.Original,        // Set the original URL
+		Hash:      url.Hash,            // Set the hash
+		AddedAt:   url.AddedAt,         // Set the time when the URL was added
+		UpdatedAt: &url.UpdatedAt.Time, // Set the pointer to the time when the URL was last updated
+		ExpiresAt: expiresAt,           // Set the pointer to the time when the URL expires
+		Owner:     url.Owner,           // Set the owner of the URL
+	}
+}
@@ -0,0 +1,62 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Create is a method that adds a new URL to the database.
+// It takes a context for managing the lifecycle of the operation,
+// a url which is the actual URL string, a hash which is the unique identifier for the URL,
+// the subject of the caller that owns the URL, and the time when the URL expires, or nil if it never expires.
+// The added and updated timestamps are filled in by the defaults of the table.
+// If the hash belongs to a URL that was deleted or has expired but is not purged yet, the row is taken over by the new URL.
+// If the hash is taken by a live URL, it returns models.ErrorURLExists.
+// If an error occurs during the execution of the query, it logs an error message and returns the error.
+// If the operation is successful, it returns nil.
+func (d *database) Create(ctx context.Context, url, hash, owner string, expiresAt *time.Time) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	// The SQL query to insert the URL into the database, or to reuse the row of a dead URL with the same hash
+	query := `INSERT INTO urls (original_url, hash, owner, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (hash) DO UPDATE SET
+			original_url = excluded.original_url,
+			owner = excluded.owner,
+			added_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP,
+			expires_at = excluded.expires_at,
+			deleted_at = NULL,
+			disabled_at = NULL
+		WHERE urls.deleted_at IS NOT NULL OR urls.expires_at <= ?`
+	res, err := d.db.ExecContext(ctx, query, url, hash, owner, utc(expiresAt), time.Now().UTC())
+	if isUniqueViolation(err) {
+		return models.ErrorURLExists
+	}
+	if err != nil {
+		logger.Error("Failed to insert URL into the database", zap.Error(err)) // Log the error if the creation fails
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// The conflicting row belongs to a live URL
+		return models.ErrorURLExists
+	}
+	return nil
+}
+
+// utc is a function that converts an optional time to UTC,
+// so the times written by the application compare correctly as text.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
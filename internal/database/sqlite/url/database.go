@@ -0,0 +1,124 @@
+package url
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	def "github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/database/migrator"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/migrations"
+	"go.uber.org/zap"
+	// reviving the sqlite driver, written in pure Go so the binary builds without cgo
+	_ "modernc.org/sqlite"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	_ def.URLDatabase    = (*database)(nil)
+	_ def.Purger         = (*database)(nil)
+	_ def.Searcher       = (*database)(nil)
+	_ def.Disabler       = (*database)(nil)
+	_ def.APIKeyDatabase = (*database)(nil)
+)
+
+type database struct {
+	db            *sqlx.DB      // The database connection
+	timeout       time.Duration // The timeout of a single query
+	migrationsDir string        // The directory to read the migrations from, empty for the embedded ones
+}
+
+// DSN is a function that builds the connection string of the SQLite database file at path.
+// It enables the write-ahead log so readers do not block the writer,
+// and makes a writer wait for a locked database instead of failing at once.
+// The times are written in the format of the SQLite date functions, so they compare correctly as text.
+func DSN(path string) string {
+	return fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_time_format=sqlite", path)
+}
+
+// Open is a function that opens the SQLite database file at path and returns it as a URLDatabase.
+// It takes a context for managing the lifecycle of the connection attempt,
+// the path of the database file, and the timeout of a single query.
+// It creates the directory of the file if it does not exist.
+// SQLite allows a single writer, so the pool is limited to one connection.
+// It returns an error if the file cannot be opened.
+func Open(ctx context.Context, path string, timeout time.Duration) (def.URLDatabase, error) {
+	db, err := open(ctx, path, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// open is a function that opens the SQLite database file at path, like Open, and returns the database struct.
+func open(ctx context.Context, path string, timeout time.Duration) (*database, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	db, err := sqlx.ConnectContext(ctx, "sqlite", DSN(path))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	return &database{
+		db:      db,      // Set the database connection
+		timeout: timeout, // Set the query timeout
+	}, nil
+}
+
+// Init is a function that opens the SQLite database.
+// It takes a context for managing the lifecycle of the connection attempt, and the configuration of the application.
+// It reads the path of the database file from the storage.sqlite.path setting,
+// the timeout of a single query from the storage.timeout setting in milliseconds,
+// and the directory of the migrations from the migrations.dir setting.
+// If the database cannot be opened, it logs a fatal error.
+func Init(ctx context.Context, cfg *config.Config) def.URLDatabase {
+	db, err := open(ctx,
+		cfg.Storage.SQLite.Path,
+		time.Duration(cfg.Storage.Timeout)*time.Millisecond)
+	if err != nil {
+		logger.Fatal("failed to open database", zap.Error(err))
+	}
+	db.migrationsDir = cfg.Migrations.Dir
+	return db
+}
+
+// withTimeout is a method on the database struct.
+// It derives a context that is cancelled when the timeout of a query expires,
+// or when the parent context is done, whichever happens first.
+// A timeout of zero leaves the parent context unchanged.
+func (d *database) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d.timeout)
+}
+
+// ApplyMigrations is a method on the database struct.
+// It applies every pending SQLite migration embedded in the binary,
+// or from the sqlite sub-directory of the migrations.dir setting if it is set.
+// It takes a context for managing the lifecycle of the operation.
+// It logs the pending versions before applying them.
+// It returns an error if the migrations cannot be loaded or applied.
+func (d *database) ApplyMigrations(ctx context.Context) error {
+	m, err := migrator.New(d.db, migrations.Source(d.migrationsDir, migrations.SQLite))
+	if err != nil {
+		return err
+	}
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range pending {
+		logger.Info("Pending migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	}
+
+	return m.Up(ctx, migrator.Latest)
+}
@@ -0,0 +1,94 @@
+package url_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/database/databasetest"
+	sqliteURL "github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// newDatabase is a function that opens a migrated SQLite database in a temporary directory,
+// and closes it when the test ends.
+func newDatabase(t *testing.T) database.URLDatabase {
+	ctx := context.Background()
+	db, err := sqliteURL.Open(ctx, filepath.Join(t.TempDir(), "urls.db"), 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, db.ApplyMigrations(ctx))
+	return db
+}
+
+// TestConformance is a test function that runs the storage conformance suite against a SQLite database file per case.
+func TestConformance(t *testing.T) {
+	databasetest.Run(t, newDatabase)
+}
+
+// TestSearch is a test function that checks that a search matches the live URLs of the owner ignoring case,
+// orders them by where the query appears and their length, pages through them, and takes the wildcards of LIKE literally.
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	db := newDatabase(t)
+	require.NoError(t, db.Create(ctx, "https://blog.example.com/posts/1", "post", "", nil))
+	require.NoError(t, db.Create(ctx, "https://example.com", "root", "", nil))
+	require.NoError(t, db.Create(ctx, "https://other.org/?ref=example.com", "ref", "", nil))
+	require.NoError(t, db.Create(ctx, "https://other.org/100%_off", "sale", "", nil))
+	require.NoError(t, db.Create(ctx, "https://deleted.example.com", "gone", "", nil))
+	require.NoError(t, db.Delete(ctx, "gone", ""))
+	require.NoError(t, db.Create(ctx, "https://example.com/mine", "mine", "owner1", nil))
+
+	searcher := db.(database.Searcher)
+	hashes := func(query string, limit, offset int) []string {
+		urls, err := searcher.Search(ctx, "", query, limit, offset)
+		require.NoError(t, err)
+		found := make([]string, 0, len(urls))
+		for _, url := range urls {
+			found = append(found, url.Hash)
+		}
+		return found
+	}
+
+	assert.Equal(t, []string{"root", "post", "ref"}, hashes("EXAMPLE.com", 10, 0))
+	assert.Equal(t, []string{"post", "ref"}, hashes("example.com", 10, 1))
+	assert.Equal(t, []string{"root"}, hashes("example.com", 1, 0))
+	assert.Equal(t, []string{"sale"}, hashes("%_", 10, 0))
+	assert.Empty(t, hashes("nothing", 10, 0))
+
+	urls, err := searcher.Search(ctx, "owner1", "example.com", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+	assert.Equal(t, "mine", urls[0].Hash)
+}
+
+// TestCreateAPIKey_Exists is a test function that checks that a key whose ID or hash is taken is refused,
+// and that the keys are read back with their times.
+func TestCreateAPIKey_Exists(t *testing.T) {
+	ctx := context.Background()
+	keys := newDatabase(t).(database.APIKeyDatabase)
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	require.NoError(t, keys.CreateAPIKey(ctx, &models.APIKey{ID: "key1", Name: "first", Hash: "hash1", CreatedAt: createdAt}))
+
+	err := keys.CreateAPIKey(ctx, &models.APIKey{ID: "key1", Name: "second", Hash: "hash2", CreatedAt: createdAt})
+	assert.EqualError(t, err, "API key already exists")
+	err = keys.CreateAPIKey(ctx, &models.APIKey{ID: "key2", Name: "second", Hash: "hash1", CreatedAt: createdAt})
+	assert.EqualError(t, err, "API key already exists")
+
+	key, err := keys.GetAPIKey(ctx, "hash1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", key.Name)
+	assert.True(t, createdAt.Equal(key.CreatedAt))
+}
@@ -0,0 +1,37 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Delete is a method that removes a URL from the database using its hash.
+// It takes a context for managing the lifecycle of the operation,
+// the hash of the URL to remove, and the owner the URL must belong to.
+// The row is only marked as deleted, the purge job removes it for good once the retention has passed.
+// If an error occurs during the execution of the query, it logs an error message and returns the error.
+// If there is no live URL with the hash owned by the owner, it returns models.ErrorURLNotFound.
+// If the operation is successful, it returns nil.
+func (d *database) Delete(ctx context.Context, hash, owner string) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	res, err := d.db.ExecContext(ctx, `UPDATE urls SET deleted_at = ?1, updated_at = CURRENT_TIMESTAMP
+		WHERE hash = ?2 AND owner = ?3 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > ?1)`, time.Now().UTC(), hash, owner)
+	if err != nil {
+		logger.Error("Failed to delete URL from the database", zap.String("hash", hash), zap.Error(err))
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrorURLNotFound
+	}
+	logger.Debug("URL is deleted from the database", zap.String("hash", hash))
+	return nil
+}
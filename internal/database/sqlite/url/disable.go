@@ -0,0 +1,37 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SetDisabled is a method that disables a URL, so it no longer resolves, or enables it again.
+// It takes a context for managing the lifecycle of the operation, the hash of the URL, and whether to disable it.
+// A URL disabled twice keeps the time when it was first disabled.
+// If an error occurs during the execution of the query, it logs an error message and returns the error.
+// If there is no URL with the hash that was neither deleted nor has expired, it returns models.ErrorURLNotFound.
+// If the operation is successful, it returns nil.
+func (d *database) SetDisabled(ctx context.Context, hash string, disabled bool) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	res, err := d.db.ExecContext(ctx, `UPDATE urls
+		SET disabled_at = CASE WHEN ?3 THEN COALESCE(disabled_at, ?1) END, updated_at = CURRENT_TIMESTAMP
+		WHERE hash = ?2 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > ?1)`, time.Now().UTC(), hash, disabled)
+	if err != nil {
+		logger.Error("Failed to disable URL in the database", zap.String("hash", hash), zap.Bool("disabled", disabled), zap.Error(err))
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrorURLNotFound
+	}
+	logger.Debug("URL is disabled in the database", zap.String("hash", hash), zap.Bool("disabled", disabled))
+	return nil
+}
@@ -0,0 +1,21 @@
+package url
+
+import (
+	"errors"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// errorAPIKeyExists is returned when an API key is created with an ID or a hash that is already taken.
+var errorAPIKeyExists = errors.New("API key already exists")
+
+// isUniqueViolation is a function that reports whether the error is SQLite refusing a row
+// because its primary key or one of its unique columns is already taken.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || code == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
@@ -0,0 +1,40 @@
+package url
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url/converter"
+	repoModel "github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url/models"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Get is a method that retrieves a URL from the database using its hash.
+// It takes a context for managing the lifecycle of the operation,
+// and the hash of the URL to retrieve.
+// If the URL is not found, or was deleted, has expired or was disabled, it returns nil for both the URL and the error.
+// If the query fails, it logs an error message and returns nil for the URL and the error.
+// It returns a pointer to the URL model if the operation is successful.
+func (d *database) Get(ctx context.Context, hash string) (*models.URL, error) {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	var url repoModel.URL
+	logger.Debug("Fetching URL from database", zap.String("hash", hash))
+	err := d.db.GetContext(ctx, &url, `SELECT * FROM urls
+		WHERE hash = ? AND deleted_at IS NULL AND disabled_at IS NULL AND (expires_at IS NULL OR expires_at > ?)`, hash, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// If the URL is not in the database, return nil
+			logger.Error("URL is not found in the database", zap.String("hash", hash))
+			return nil, nil
+		}
+		logger.Error("Failed to fetch URL from the database", zap.String("hash", hash), zap.Error(err))
+		return nil, err
+	}
+	logger.Debug("URL is fetched from the database", zap.String("url", url.Original))
+	return converter.ToURLFromRepo(url), nil
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// URL is a struct that represents a row of the urls table in SQLite.
+// Original is a string that holds the original URL.
+// Hash is a string that holds the hashed version of the original URL.
+// AddedAt is a time.Time value that holds the time when the URL was added to the application.
+// UpdatedAt is a sql.NullTime value that holds the time when the URL was last updated in the application.
+// ExpiresAt is a sql.NullTime value that holds the time after which the URL no longer resolves, nil if it never expires.
+// DeletedAt is a sql.NullTime value that holds the time when the URL was deleted, nil while it is live.
+// DisabledAt is a sql.NullTime value that holds the time when the URL was disabled by an operator, nil while it resolves.
+// Owner is a string that holds the owner of the URL, empty if it was created without one.
+// Unlike in PostgreSQL, the host of the original URL is not stored, the searches match the whole original URL.
+type URL struct {
+	Original   string       `db:"original_url"` // The original URL
+	Hash       string       `db:"hash"`         // The hashed version of the original URL
+	AddedAt    time.Time    `db:"added_at"`     // The time when the URL was added
+	UpdatedAt  sql.NullTime `db:"updated_at"`   // The time when the URL was last updated, nil if not updated
+	ExpiresAt  sql.NullTime `db:"expires_at"`   // The time when the URL expires, nil if it never expires
+	DeletedAt  sql.NullTime `db:"deleted_at"`   // The time when the URL was deleted, nil while it is live
+	DisabledAt sql.NullTime `db:"disabled_at"`  // The time when the URL was disabled, nil while it resolves
+	Owner      string       `db:"owner"`        // The owner of the URL
+}
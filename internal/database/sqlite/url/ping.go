@@ -0,0 +1,15 @@
+package url
+
+import (
+	"context"
+)
+
+// Ping is a method that checks whether the database file can be read.
+// It takes a context for managing the lifecycle of the operation.
+// It returns an error if the file cannot be read within the query timeout.
+func (d *database) Ping(ctx context.Context) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	return d.db.PingContext(ctx)
+}
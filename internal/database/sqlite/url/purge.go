@@ -0,0 +1,35 @@
+package url
+
+import (
+	"context"
+	"time"
+)
+
+// Purge is a method that removes the expired URLs and the URLs deleted longer than retention ago for good.
+// It takes a context for managing the lifecycle of the operation, the retention of deleted URLs,
+// and the number of rows to remove in one statement.
+// The database file belongs to a single process, so no lock between instances is needed.
+// It removes the rows in batches until a batch comes back short, so every statement holds the write lock briefly.
+// It returns the number of rows removed, and an error if a statement fails.
+func (d *database) Purge(ctx context.Context, retention time.Duration, batchSize int) (int64, error) {
+	var total int64
+	for {
+		now := time.Now().UTC()
+		queryCtx, cancel := d.withTimeout(ctx)
+		res, err := d.db.ExecContext(queryCtx, `DELETE FROM urls WHERE hash IN (
+			SELECT hash FROM urls WHERE expires_at <= ? OR deleted_at <= ? LIMIT ?)`,
+			now, now.Add(-retention), batchSize)
+		cancel()
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url/converter"
+	repoModel "github.com/t1ltxz-gxd/shortify/internal/database/sqlite/url/models"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"strings"
+	"time"
+)
+
+// Search is a method that finds the live URLs of an owner whose original URL contains the query, ignoring case.
+// It takes a context for managing the lifecycle of the operation, the owner, the query,
+// and the number of URLs to return and to skip, for pagination.
+// SQLite has no trigram index, so it scans the table; the host is part of the original URL, so it is searched too.
+// The URLs where the query appears earliest come first, and among them the shortest.
+// It returns the matching URLs and an error if the operation fails.
+func (d *database) Search(ctx context.Context, owner, query string, limit, offset int) ([]*models.URL, error) {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	pattern := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+	var rows []repoModel.URL
+	err := d.db.SelectContext(ctx, &rows, `SELECT * FROM urls
+		WHERE deleted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+			AND owner = ? AND original_url LIKE ? ESCAPE '\'
+		ORDER BY instr(lower(original_url), lower(?)), length(original_url), hash
+		LIMIT ? OFFSET ?`,
+		time.Now().UTC(), owner, "%"+pattern+"%", query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]*models.URL, 0, len(rows))
+	for _, row := range rows {
+		urls = append(urls, converter.ToURLFromRepo(row))
+	}
+	return urls, nil
+}
@@ -0,0 +1,267 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Names of the services reported by the grpc.health.v1 service besides the probes and the served services.
+const (
+	ServiceLiveness  = "liveness"  // Serving while the process runs, even during a shutdown
+	ServiceReadiness = "readiness" // Serving while every critical dependency is reachable and the process is not shutting down
+)
+
+// defaultInterval is the interval between two rounds of probes if none is configured.
+const defaultInterval = 5 * time.Second
+
+// Probe is a struct that describes a dependency of the application the checker pings.
+type Probe struct {
+	Name     string                          // The name of the dependency, also its service name in grpc.health.v1
+	Ping     func(ctx context.Context) error // The function that checks whether the dependency is reachable
+	Critical bool                            // Whether the application is not ready while the dependency is unreachable
+}
+
+// Checker is an interface that tracks the health of the application from periodic pings of its dependencies.
+// It reports it through a grpc.health.v1 server and through the /healthz and /readyz HTTP handlers.
+type Checker interface {
+	// Start is a method that pings the dependencies once, so the status is known before the application serves,
+	// and then again every interval in the background until the context is done.
+	Start(ctx context.Context)
+
+	// Wait is a method that blocks until the background pings have stopped after the context passed to Start is done.
+	Wait()
+
+	// Ready is a method that reports whether every critical dependency answered its last ping
+	// and the application is not shutting down.
+	Ready() bool
+
+	// Shutdown is a method that marks the application as not ready, so the load balancers stop sending requests to it,
+	// while it stays live to finish the requests in progress.
+	Shutdown()
+
+	// Liveness is a method that serves /healthz. It answers 200 while the process runs.
+	Liveness(w http.ResponseWriter, r *http.Request)
+
+	// Readiness is a method that serves /readyz. It answers 200 while the application is ready and 503 otherwise,
+	// with the result of the last ping of every dependency in the body.
+	Readiness(w http.ResponseWriter, r *http.Request)
+}
+
+// Ensure that the checker struct implements the Checker interface
+var _ Checker = (*checker)(nil)
+
+// checker is a struct that implements the Checker interface.
+type checker struct {
+	server   *health.Server // The grpc.health.v1 server that reports the statuses
+	services []string       // The served gRPC services, which are serving while the application is ready
+	probes   []Probe        // The dependencies to ping
+	interval time.Duration  // The interval between two rounds of probes
+	timeout  time.Duration  // The timeout of a single ping
+
+	m            sync.RWMutex     // Guards errs and shuttingDown
+	errs         map[string]error // The error of the last ping of every probe, nil if it answered
+	shuttingDown bool             // Whether Shutdown was called
+
+	wg sync.WaitGroup // Tracks the background pings
+}
+
+// NewChecker is a function that creates a new health checker.
+// It takes the grpc.health.v1 server to report to, the names of the served gRPC services,
+// the interval between two rounds of probes, the timeout of a single ping, and the dependencies to ping.
+// An interval of zero pings every defaultInterval, and a timeout of zero lets a ping take the whole interval.
+// Until the first round of probes the application is live but not ready.
+// It returns the checker.
+func NewChecker(server *health.Server, services []string, interval, timeout time.Duration, probes ...Probe) Checker {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if timeout <= 0 || timeout > interval {
+		timeout = interval
+	}
+	c := &checker{
+		server:   server,
+		services: services,
+		probes:   probes,
+		interval: interval,
+		timeout:  timeout,
+		errs:     make(map[string]error, len(probes)),
+	}
+	server.SetServingStatus(ServiceLiveness, healthpb.HealthCheckResponse_SERVING)
+	c.setReady(false)
+	for _, p := range probes {
+		server.SetServingStatus(p.Name, healthpb.HealthCheckResponse_UNKNOWN)
+	}
+	return c
+}
+
+// Start is a method on the checker struct.
+// It pings the dependencies once and then every interval in a background goroutine until the context is done.
+func (c *checker) Start(ctx context.Context) {
+	c.check(ctx)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.check(ctx)
+			}
+		}
+	}()
+}
+
+// Wait is a method on the checker struct.
+// It blocks until the background goroutine started by Start has returned.
+func (c *checker) Wait() {
+	c.wg.Wait()
+}
+
+// check is a method on the checker struct.
+// It pings every dependency concurrently, records the results and updates the statuses of the grpc.health.v1 server.
+// A dependency whose status changed is logged.
+func (c *checker) check(ctx context.Context) {
+	errs := make([]error, len(c.probes))
+	var wg sync.WaitGroup
+	for i, p := range c.probes {
+		wg.Add(1)
+		go func(i int, p Probe) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			errs[i] = p.Ping(ctx)
+		}(i, p)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return // The application is stopping, the pings failed because of it
+	}
+
+	c.m.Lock()
+	for i, p := range c.probes {
+		prev, seen := c.errs[p.Name]
+		c.errs[p.Name] = errs[i]
+		switch {
+		case errs[i] != nil && (!seen || prev == nil):
+			logger.Warn("Dependency is unreachable", zap.String("dependency", p.Name), zap.Bool("critical", p.Critical), zap.Error(errs[i]))
+		case errs[i] == nil && seen && prev != nil:
+			logger.Info("Dependency is reachable again", zap.String("dependency", p.Name))
+		}
+		c.server.SetServingStatus(p.Name, servingStatus(errs[i] == nil))
+	}
+	// The status is set under the lock, so a round of probes cannot report ready after Shutdown
+	c.setReady(c.readyLocked())
+	c.m.Unlock()
+}
+
+// Ready is a method on the checker struct.
+// It reports whether every critical dependency answered its last ping and the application is not shutting down.
+func (c *checker) Ready() bool {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.readyLocked()
+}
+
+// readyLocked is a method on the checker struct that implements Ready. The caller must hold the mutex.
+func (c *checker) readyLocked() bool {
+	if c.shuttingDown {
+		return false
+	}
+	for _, p := range c.probes {
+		err, seen := c.errs[p.Name]
+		if p.Critical && (!seen || err != nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// Shutdown is a method on the checker struct.
+// It marks the application as not ready for good, later rounds of probes do not change it.
+func (c *checker) Shutdown() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.shuttingDown = true
+	c.setReady(false)
+}
+
+// setReady is a method on the checker struct.
+// It sets the status of the readiness service, of the overall health reported for an empty service name,
+// and of every served gRPC service.
+func (c *checker) setReady(ready bool) {
+	status := servingStatus(ready)
+	c.server.SetServingStatus("", status)
+	c.server.SetServingStatus(ServiceReadiness, status)
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+}
+
+// Liveness is a method on the checker struct.
+// It answers 200 with a JSON status, the process is able to answer so it is live.
+func (c *checker) Liveness(w http.ResponseWriter, _ *http.Request) {
+	writeStatus(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Readiness is a method on the checker struct.
+// It answers 200 while the application is ready and 503 otherwise,
+// with a JSON body holding "ok" or the error of the last ping of every dependency.
+func (c *checker) Readiness(w http.ResponseWriter, _ *http.Request) {
+	c.m.RLock()
+	ready := c.readyLocked()
+	checks := make(map[string]string, len(c.probes))
+	for _, p := range c.probes {
+		err, seen := c.errs[p.Name]
+		switch {
+		case !seen:
+			checks[p.Name] = "unknown"
+		case err != nil:
+			checks[p.Name] = err.Error()
+		default:
+			checks[p.Name] = "ok"
+		}
+	}
+	shuttingDown := c.shuttingDown
+	c.m.RUnlock()
+
+	body := map[string]any{"status": "ok", "checks": checks}
+	code := http.StatusOK
+	if !ready {
+		body["status"] = "unavailable"
+		if shuttingDown {
+			body["status"] = "shutting down"
+		}
+		code = http.StatusServiceUnavailable
+	}
+	writeStatus(w, code, body)
+}
+
+// writeStatus is a function that writes a JSON body with the status code.
+func writeStatus(w http.ResponseWriter, code int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		logger.Debug("Failed to write the health status", zap.Error(err))
+	}
+}
+
+// servingStatus is a function that converts whether something is healthy into a grpc.health.v1 status.
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
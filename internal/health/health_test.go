@@ -0,0 +1,172 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/health"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/breaker"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// fakeProbe is a struct whose ping returns an error that can be changed while the test is running.
+type fakeProbe struct {
+	err atomic.Pointer[error]
+}
+
+// set is a method that changes the error returned by the ping, nil to make it answer.
+func (f *fakeProbe) set(err error) {
+	f.err.Store(&err)
+}
+
+// ping is a method that returns the current error.
+func (f *fakeProbe) ping(_ context.Context) error {
+	if err := f.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// status is a function that returns the status of a service reported by the grpc.health.v1 server.
+func status(t *testing.T, server *grpcHealth.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+// readyz is a function that calls the readiness handler and returns the status code and the decoded body.
+func readyz(t *testing.T, checker health.Checker) (int, map[string]any) {
+	rec := httptest.NewRecorder()
+	checker.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	return rec.Code, body
+}
+
+// TestChecker_Readiness is a test function that checks that the application is ready only while the critical dependencies answer,
+// that a non-critical dependency only changes its own status, and that the statuses follow the dependencies over time.
+func TestChecker_Readiness(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db, cache := &fakeProbe{}, &fakeProbe{}
+	cache.set(errors.New("connection refused"))
+	server := grpcHealth.NewServer()
+	checker := health.NewChecker(server, []string{"url_v1.UrlV1"}, 10*time.Millisecond, 0,
+		health.Probe{Name: "database", Ping: db.ping, Critical: true},
+		health.Probe{Name: "cache", Ping: cache.ping},
+	)
+	assert.False(t, checker.Ready(), "ready before the first probe")
+
+	checker.Start(ctx)
+	assert.True(t, checker.Ready())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, server, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, server, "url_v1.UrlV1"))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, server, "database"))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, server, "cache"))
+
+	code, body := readyz(t, checker)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]any{"database": "ok", "cache": "connection refused"}, body["checks"])
+
+	db.set(errors.New("database is down"))
+	assert.Eventually(t, func() bool { return !checker.Ready() }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, server, health.ServiceReadiness))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, server, health.ServiceLiveness))
+	code, _ = readyz(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	db.set(nil)
+	assert.Eventually(t, checker.Ready, time.Second, 5*time.Millisecond)
+
+	cancel()
+	checker.Wait()
+}
+
+// TestChecker_Shutdown is a test function that checks that a shutting down application is not ready however its dependencies are,
+// while it stays live.
+func TestChecker_Shutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := grpcHealth.NewServer()
+	checker := health.NewChecker(server, nil, 10*time.Millisecond, 0,
+		health.Probe{Name: "database", Ping: (&fakeProbe{}).ping, Critical: true},
+	)
+	checker.Start(ctx)
+	require.True(t, checker.Ready())
+
+	checker.Shutdown()
+	time.Sleep(30 * time.Millisecond) // Let a few rounds of probes run
+	assert.False(t, checker.Ready())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, server, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, server, health.ServiceLiveness))
+
+	code, body := readyz(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "shutting down", body["status"])
+
+	rec := httptest.NewRecorder()
+	checker.Liveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// failingCache is a struct that wraps a URLCache and fails every Get, so the breaker around it opens.
+type failingCache struct {
+	def.URLCache
+}
+
+// Get is a method that mocks the Get method of the URLCache interface with an error.
+func (failingCache) Get(_ context.Context, _ string) (*models.URL, error) {
+	return nil, errors.New("connection refused")
+}
+
+// TestChecker_CacheBreaker is a test function that checks that the state of the cache circuit breaker is reported in /readyz
+// and by grpc.health.v1, and that an open circuit does not affect readiness.
+func TestChecker_CacheBreaker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := breaker.NewBreaker(failingCache{}, 1, time.Hour)
+	defer b.Close()
+	server := grpcHealth.NewServer()
+	checker := health.NewChecker(server, nil, 10*time.Millisecond, 0,
+		health.Probe{Name: "database", Ping: (&fakeProbe{}).ping, Critical: true},
+		health.Probe{Name: "cacheBreaker", Ping: breaker.CheckClosed(b)},
+	)
+	checker.Start(ctx)
+	_, body := readyz(t, checker)
+	assert.Equal(t, "ok", body["checks"].(map[string]any)["cacheBreaker"])
+
+	_, _ = b.Get(ctx, "hash")
+	require.Equal(t, breaker.StateOpen, b.State())
+	assert.Eventually(t, func() bool {
+		resp, err := server.Check(ctx, &healthpb.HealthCheckRequest{Service: "cacheBreaker"})
+		return err == nil && resp.Status == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, 5*time.Millisecond)
+	code, body := readyz(t, checker)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "open", body["checks"].(map[string]any)["cacheBreaker"])
+
+	cancel()
+	checker.Wait()
+}
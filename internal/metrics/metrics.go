@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// namespace is the prefix of the names of every metric of the application.
+const namespace = "shortify"
+
+// Results of a cache lookup, the values of the result label of CacheRequests
+const (
+	CacheHit   = "hit"   // The URL was in the cache
+	CacheMiss  = "miss"  // The URL was not in the cache
+	CacheError = "error" // The cache could not be asked
+)
+
+// registry is the registry every metric of the application is registered with.
+// It is not the default registry of the client library, so the metrics of the dependencies do not leak into it.
+var registry = prometheus.NewRegistry()
+
+// Metrics of the application
+var (
+	// RPCRequests counts the handled RPCs by full method name and status code.
+	RPCRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Number of handled RPCs by method and status code.",
+	}, []string{"method", "code"})
+
+	// RPCDuration observes how long the RPCs take by full method name.
+	RPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "Time spent handling RPCs by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// CacheRequests counts the lookups of URLs in the cache by result, one of CacheHit, CacheMiss and CacheError.
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Number of URL lookups in the cache by result.",
+	}, []string{"result"})
+
+	// DatabaseDuration observes how long the database operations of the repository take by operation and whether they failed.
+	DatabaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "database",
+		Name:      "query_duration_seconds",
+		Help:      "Time spent in database operations by operation and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "error"})
+
+	// RateLimited counts the requests refused by the rate limits by class, like create or resolve.
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "refused_total",
+		Help:      "Number of requests refused by the rate limits by class.",
+	}, []string{"class"})
+
+	// LinksCreated counts the short links created.
+	LinksCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "links",
+		Name:      "created_total",
+		Help:      "Number of short links created.",
+	})
+
+	// LinksResolved counts the short links resolved to their original URL.
+	LinksResolved = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "links",
+		Name:      "resolved_total",
+		Help:      "Number of short links resolved to their original URL.",
+	})
+
+	// LinksNotFound counts the lookups of short links that do not exist, have expired or were deleted.
+	LinksNotFound = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "links",
+		Name:      "not_found_total",
+		Help:      "Number of lookups of short links that do not exist.",
+	})
+
+	// LinksPurged counts the expired and deleted links removed for good by the purge job.
+	LinksPurged = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "links",
+		Name:      "purged_total",
+		Help:      "Number of expired and deleted links removed by the purge job.",
+	})
+)
+
+// init is a function that registers the metrics of the application and the Go runtime and process metrics with the registry.
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RPCRequests,
+		RPCDuration,
+		CacheRequests,
+		DatabaseDuration,
+		RateLimited,
+		LinksCreated,
+		LinksResolved,
+		LinksNotFound,
+		LinksPurged,
+	)
+}
+
+// Handler is a function that returns the HTTP handler serving the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveDatabase is a function that records the duration of a database operation started at started in DatabaseDuration.
+// It takes the name of the operation, the time it started, and the error it returned.
+func ObserveDatabase(operation string, started time.Time, err error) {
+	DatabaseDuration.WithLabelValues(operation, strconv.FormatBool(err != nil)).Observe(time.Since(started).Seconds())
+}
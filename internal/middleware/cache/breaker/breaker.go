@@ -0,0 +1,221 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrorCircuitOpen is returned by the breaker instead of calling the cache while the circuit is open.
+var ErrorCircuitOpen = errors.New("cache circuit breaker is open")
+
+// State is the state of the circuit breaker.
+type State int32
+
+// Constants for the circuit breaker states
+const (
+	StateClosed   State = iota // Calls go to the cache and failures are counted
+	StateOpen                  // Calls bypass the cache until a probe succeeds
+	StateHalfOpen              // Calls go to the cache and the next result decides whether to close or reopen
+)
+
+// String is a method on the State type.
+// It returns the human-readable name of the state, as used in logs and health output.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker is an interface that wraps a URLCache with a circuit breaker.
+// It exposes the current state of the breaker and a Close method that stops the recovery probe.
+type Breaker interface {
+	def.URLCache
+
+	// State returns the current state of the circuit breaker.
+	State() State
+
+	// Close stops the background recovery probe and closes the wrapped cache if it holds connections.
+	Close() error
+}
+
+// Ensure that the breaker struct implements the Breaker and PrefixPurger interfaces
+var (
+	_ Breaker          = (*breaker)(nil)
+	_ def.PrefixPurger = (*breaker)(nil)
+)
+
+// breaker is a struct that implements the Breaker interface.
+// It counts consecutive cache failures and opens the circuit once the threshold is reached.
+// While the circuit is open, every call fails fast with ErrorCircuitOpen,
+// and a background goroutine pings the cache to detect when it has recovered.
+type breaker struct {
+	next             def.URLCache  // The wrapped cache
+	failureThreshold int           // The number of consecutive failures that opens the circuit
+	probeInterval    time.Duration // The interval between recovery probes while the circuit is open
+
+	m        sync.Mutex // The mutex guarding state and failures
+	state    State      // The current state
+	failures int        // The number of consecutive failures
+
+	stop     chan struct{} // Closed to stop the probe goroutine
+	stopOnce sync.Once     // Ensures the stop channel is closed only once
+}
+
+// NewBreaker is a function that creates a new circuit breaker around a cache.
+// It takes the cache to wrap, the number of consecutive failures that opens the circuit,
+// and the interval between recovery probes while the circuit is open.
+// It starts the background probe goroutine and returns the breaker.
+func NewBreaker(next def.URLCache, failureThreshold int, probeInterval time.Duration) Breaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	b := &breaker{
+		next:             next,
+		failureThreshold: failureThreshold,
+		probeInterval:    probeInterval,
+		state:            StateClosed,
+		stop:             make(chan struct{}),
+	}
+	go b.probe()
+	return b
+}
+
+// State is a method on the breaker struct.
+// It returns the current state of the circuit breaker.
+func (b *breaker) State() State {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.state
+}
+
+// CheckClosed is a function that returns a health check ping reporting the state of the breaker.
+// The ping does not reach the cache, it returns nil while the circuit is closed,
+// and an error named after the state otherwise, like "open", so the health output shows the state.
+func CheckClosed(b Breaker) func(ctx context.Context) error {
+	return func(_ context.Context) error {
+		if state := b.State(); state != StateClosed {
+			return errors.New(state.String())
+		}
+		return nil
+	}
+}
+
+// Close is a method on the breaker struct.
+// It stops the background probe goroutine and closes the wrapped cache if it implements io.Closer.
+// It is safe to call more than once; only the first call closes the wrapped cache.
+// It returns the error of closing the wrapped cache.
+func (b *breaker) Close() error {
+	var err error
+	b.stopOnce.Do(func() {
+		close(b.stop)
+		if closer, ok := b.next.(io.Closer); ok {
+			err = closer.Close()
+		}
+	})
+	return err
+}
+
+// allow is a method on the breaker struct.
+// It reports whether a call may be passed to the wrapped cache.
+func (b *breaker) allow() bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.state != StateOpen
+}
+
+// record is a method on the breaker struct.
+// It updates the breaker with the result of a call to the wrapped cache made with the given context.
+// Cache misses, cancelled contexts and errors caused by the deadline of the caller
+// are not failures of the cache and are treated as successes.
+// A success closes a half-open circuit, a failure reopens it,
+// and in the closed state the circuit opens after failureThreshold consecutive failures.
+func (b *breaker) record(ctx context.Context, err error) {
+	failed := err != nil && ctx.Err() == nil && !errors.Is(err, models.ErrorCacheMiss) && !errors.Is(err, context.Canceled)
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if !failed {
+		b.failures = 0
+		if b.state == StateHalfOpen {
+			b.setState(StateClosed, nil)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.setState(StateOpen, err)
+	}
+}
+
+// setState is a method on the breaker struct.
+// It changes the state of the breaker and logs the transition.
+// It must be called with the mutex held.
+func (b *breaker) setState(state State, err error) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	switch state {
+	case StateOpen:
+		logger.Warn("Cache circuit breaker opened, bypassing the cache",
+			zap.String("from", from.String()),
+			zap.Int("failures", b.failures),
+			zap.Error(err))
+	default:
+		logger.Info("Cache circuit breaker state changed",
+			zap.String("from", from.String()),
+			zap.String("to", state.String()))
+	}
+}
+
+// probe is a method on the breaker struct.
+// It runs until Close is called and pings the wrapped cache every probeInterval while the circuit is open.
+// A successful ping moves the circuit to half-open, so the next real call decides whether it closes.
+func (b *breaker) probe() {
+	ticker := time.NewTicker(b.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if b.State() != StateOpen {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), b.probeInterval)
+		err := b.next.Ping(ctx)
+		cancel()
+		if err != nil {
+			logger.Debug("Cache is still unavailable", zap.Error(err))
+			continue
+		}
+
+		b.m.Lock()
+		if b.state == StateOpen {
+			b.failures = 0
+			b.setState(StateHalfOpen, nil)
+		}
+		b.m.Unlock()
+	}
+}
@@ -0,0 +1,140 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/breaker"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+)
+
+// fakeCache is a struct that implements the URLCache interface for testing.
+// Every call returns err, which can be changed while the test is running.
+type fakeCache struct {
+	m     sync.Mutex
+	err   error
+	calls int
+}
+
+// setErr is a method that changes the error returned by the fake cache.
+func (f *fakeCache) setErr(err error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.err = err
+}
+
+// result is a method that counts a call and returns the current error.
+func (f *fakeCache) result() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.calls++
+	return f.err
+}
+
+// Create is a method that mocks the Create method of the URLCache interface.
+func (f *fakeCache) Create(_ context.Context, _, _ string, _ time.Duration) error {
+	return f.result()
+}
+
+// Get is a method that mocks the Get method of the URLCache interface.
+func (f *fakeCache) Get(_ context.Context, hash string) (*models.URL, error) {
+	if err := f.result(); err != nil {
+		return nil, err
+	}
+	return &models.URL{Hash: hash}, nil
+}
+
+// Delete is a method that mocks the Delete method of the URLCache interface.
+func (f *fakeCache) Delete(_ context.Context, _ string) error {
+	return f.result()
+}
+
+// Ping is a method that mocks the Ping method of the URLCache interface.
+// It does not count as a call.
+func (f *fakeCache) Ping(_ context.Context) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.err
+}
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	m.Run()
+}
+
+// TestBreaker_OpensAfterThreshold is a test function that checks that the circuit opens after the failure threshold
+// and that calls fail fast without reaching the cache while it is open.
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	next := &fakeCache{err: errors.New("connection refused")}
+	b := breaker.NewBreaker(next, 2, time.Hour)
+	defer b.Close()
+
+	_, _ = b.Get(context.Background(), "hash")
+	assert.Equal(t, breaker.StateClosed, b.State())
+	_, _ = b.Get(context.Background(), "hash")
+	assert.Equal(t, breaker.StateOpen, b.State())
+
+	_, err := b.Get(context.Background(), "hash")
+	assert.ErrorIs(t, err, breaker.ErrorCircuitOpen)
+	assert.Equal(t, 2, next.calls)
+}
+
+// TestBreaker_MissIsNotFailure is a test function that checks that cache misses do not open the circuit.
+func TestBreaker_MissIsNotFailure(t *testing.T) {
+	next := &fakeCache{err: models.ErrorCacheMiss}
+	b := breaker.NewBreaker(next, 1, time.Hour)
+	defer b.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := b.Get(context.Background(), "hash")
+		assert.ErrorIs(t, err, models.ErrorCacheMiss)
+	}
+	assert.Equal(t, breaker.StateClosed, b.State())
+}
+
+// TestBreaker_RecoversAfterProbe is a test function that checks that a successful probe moves the circuit to half-open
+// and that the next successful call closes it.
+func TestBreaker_RecoversAfterProbe(t *testing.T) {
+	next := &fakeCache{err: errors.New("connection refused")}
+	b := breaker.NewBreaker(next, 1, 10*time.Millisecond)
+	defer b.Close()
+
+	_ = b.Create(context.Background(), "hash", "https://example.com", time.Minute)
+	assert.Equal(t, breaker.StateOpen, b.State())
+
+	next.setErr(nil)
+	assert.Eventually(t, func() bool { return b.State() == breaker.StateHalfOpen }, time.Second, 5*time.Millisecond)
+
+	_, err := b.Get(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, breaker.StateClosed, b.State())
+}
+
+// closingCache is a struct that wraps fakeCache and counts how many times it is closed.
+type closingCache struct {
+	fakeCache
+	closed int
+}
+
+// Close is a method that counts the call.
+func (c *closingCache) Close() error {
+	c.closed++
+	return nil
+}
+
+// TestBreaker_CloseClosesCache is a test function that checks that closing the breaker closes the wrapped cache once,
+// however many times the breaker is closed.
+func TestBreaker_CloseClosesCache(t *testing.T) {
+	next := &closingCache{}
+	b := breaker.NewBreaker(next, 1, time.Hour)
+
+	assert.NoError(t, b.Close())
+	assert.NoError(t, b.Close())
+	assert.Equal(t, 1, next.closed)
+}
@@ -0,0 +1,68 @@
+package breaker
+
+import (
+	"context"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"time"
+)
+
+// Create is a method on the breaker struct.
+// It adds a new URL to the wrapped cache if the circuit allows it.
+// It returns ErrorCircuitOpen without calling the cache while the circuit is open.
+func (b *breaker) Create(ctx context.Context, hash, url string, expiration time.Duration) error {
+	if !b.allow() {
+		return ErrorCircuitOpen
+	}
+	err := b.next.Create(ctx, hash, url, expiration)
+	b.record(ctx, err)
+	return err
+}
+
+// Get is a method on the breaker struct.
+// It retrieves a URL from the wrapped cache if the circuit allows it.
+// It returns ErrorCircuitOpen without calling the cache while the circuit is open.
+func (b *breaker) Get(ctx context.Context, hash string) (*models.URL, error) {
+	if !b.allow() {
+		return nil, ErrorCircuitOpen
+	}
+	url, err := b.next.Get(ctx, hash)
+	b.record(ctx, err)
+	return url, err
+}
+
+// Delete is a method on the breaker struct.
+// It removes a URL from the wrapped cache if the circuit allows it.
+// It returns ErrorCircuitOpen without calling the cache while the circuit is open.
+func (b *breaker) Delete(ctx context.Context, hash string) error {
+	if !b.allow() {
+		return ErrorCircuitOpen
+	}
+	err := b.next.Delete(ctx, hash)
+	b.record(ctx, err)
+	return err
+}
+
+// PurgePrefix is a method on the breaker struct.
+// It removes every entry whose hash starts with the prefix from the wrapped cache if the circuit allows it.
+// It returns ErrorCircuitOpen without calling the cache while the circuit is open,
+// and models.ErrorPurgeUnsupported if the wrapped cache cannot purge by prefix.
+func (b *breaker) PurgePrefix(ctx context.Context, prefix string) (int64, error) {
+	p, ok := b.next.(def.PrefixPurger)
+	if !ok {
+		return 0, models.ErrorPurgeUnsupported
+	}
+	if !b.allow() {
+		return 0, ErrorCircuitOpen
+	}
+	n, err := p.PurgePrefix(ctx, prefix)
+	b.record(ctx, err)
+	return n, err
+}
+
+// Ping is a method on the breaker struct.
+// It pings the wrapped cache directly, regardless of the state of the circuit,
+// and does not affect the state of the breaker.
+func (b *breaker) Ping(ctx context.Context) error {
+	return b.next.Ping(ctx)
+}
@@ -20,5 +20,37 @@ type URLCache interface {
 	// and the hash of the URL to retrieve.
 	// It returns a pointer to a URL model if the operation is successful,
 	// and an error if the operation fails or if the URL is not found in the cache.
+	// A missing entry is reported with models.ErrorCacheMiss.
 	Get(ctx context.Context, hash string) (*models.URL, error)
+
+	// Delete is a method that removes a URL from the cache using its hash.
+	// It takes a context for managing the lifecycle of the operation,
+	// and the hash of the URL to remove.
+	// Removing a hash that is not cached is not an error.
+	// It returns an error if the operation fails.
+	Delete(ctx context.Context, hash string) error
+
+	// Ping is a method that checks whether the cache backend is reachable.
+	// It takes a context for managing the lifecycle of the operation.
+	// It returns an error if the backend cannot be reached.
+	Ping(ctx context.Context) error
+}
+
+// Flusher is an interface implemented by caches that can drop every entry they hold.
+// It is used to recover from missed invalidation messages.
+type Flusher interface {
+	// Flush is a method that removes every entry from the cache.
+	// It takes a context for managing the lifecycle of the operation.
+	// It returns an error if the operation fails.
+	Flush(ctx context.Context) error
+}
+
+// PrefixPurger is an interface implemented by caches that can drop every entry whose hash starts with a prefix.
+// It is used by the operators to evict a range of links at once.
+type PrefixPurger interface {
+	// PurgePrefix is a method that removes every entry whose hash starts with the prefix from the cache.
+	// It takes a context for managing the lifecycle of the operation, and the prefix.
+	// It returns the number of entries removed, and an error if the operation fails,
+	// in which case some of the entries may have been removed already.
+	PurgePrefix(ctx context.Context, prefix string) (int64, error)
 }
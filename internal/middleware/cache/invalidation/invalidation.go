@@ -0,0 +1,120 @@
+package invalidation
+
+import (
+	"context"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+)
+
+// Handler is an interface that defines how an instance reacts to invalidation messages.
+type Handler interface {
+	// Invalidate is a method that evicts a single hash from the caches of this instance.
+	Invalidate(ctx context.Context, hash string)
+
+	// Flush is a method that evicts every entry from the caches of this instance.
+	// It is called when the bus reconnects and messages may have been missed.
+	Flush(ctx context.Context)
+}
+
+// Bus is an interface that defines a channel for cache invalidation messages shared by every instance.
+type Bus interface {
+	// Publish is a method that announces that the link with the given hash has changed.
+	// It takes a context for managing the lifecycle of the operation and the hash of the link.
+	// It returns an error if the message could not be sent.
+	Publish(ctx context.Context, hash string) error
+
+	// Run is a method that delivers the messages of the bus to the handler.
+	// It reconnects after connection loss and calls Flush on the handler when messages may have been missed.
+	// It blocks until the context is cancelled.
+	Run(ctx context.Context, handler Handler) error
+
+	// Close is a method that releases the connections of the bus.
+	Close() error
+}
+
+// nopBus is a struct that implements a Bus that does nothing.
+// It is used when cache invalidation between instances is disabled.
+type nopBus struct{}
+
+// NewNopBus is a function that creates a Bus that drops every message.
+func NewNopBus() Bus {
+	return nopBus{}
+}
+
+// Publish is a method that drops the message.
+func (nopBus) Publish(_ context.Context, _ string) error {
+	return nil
+}
+
+// Run is a method that blocks until the context is cancelled.
+func (nopBus) Run(ctx context.Context, _ Handler) error {
+	<-ctx.Done()
+	return nil
+}
+
+// Close is a method that does nothing.
+func (nopBus) Close() error {
+	return nil
+}
+
+// evictor is a struct that implements the Handler interface by evicting hashes from a set of caches.
+type evictor struct {
+	caches []def.URLCache // The caches to evict from
+}
+
+// NewEvictor is a function that creates a Handler that evicts invalidated hashes from the given caches.
+// On Flush, it flushes every cache that implements the Flusher interface.
+func NewEvictor(caches ...def.URLCache) Handler {
+	return &evictor{caches: caches}
+}
+
+// Invalidate is a method that deletes the hash from every cache.
+// Errors are logged and do not stop the eviction from the other caches.
+func (e *evictor) Invalidate(ctx context.Context, hash string) {
+	logger.Debug("Invalidating cached URL", zap.String("hash", hash))
+	for _, c := range e.caches {
+		if err := c.Delete(ctx, hash); err != nil {
+			logger.Warn("Failed to invalidate cached URL", zap.String("hash", hash), zap.Error(err))
+		}
+	}
+}
+
+// Flush is a method that flushes every cache that implements the Flusher interface.
+func (e *evictor) Flush(ctx context.Context) {
+	logger.Info("Invalidation messages may have been missed, flushing local caches")
+	for _, c := range e.caches {
+		f, ok := c.(def.Flusher)
+		if !ok {
+			continue
+		}
+		if err := f.Flush(ctx); err != nil {
+			logger.Warn("Failed to flush cache", zap.Error(err))
+		}
+	}
+}
+
+// chain is a struct that implements the Handler interface by passing every message to a list of handlers in turn.
+type chain struct {
+	handlers []Handler // The handlers, in the order they get the messages
+}
+
+// Chain is a function that creates a Handler that passes every message to each of the given handlers in turn.
+// A handler that must see a change before the caches are evicted, such as a database reading from replicas, comes first.
+func Chain(handlers ...Handler) Handler {
+	return &chain{handlers: handlers}
+}
+
+// Invalidate is a method that passes the hash to every handler.
+func (c *chain) Invalidate(ctx context.Context, hash string) {
+	for _, h := range c.handlers {
+		h.Invalidate(ctx, hash)
+	}
+}
+
+// Flush is a method that passes the flush to every handler.
+func (c *chain) Flush(ctx context.Context) {
+	for _, h := range c.handlers {
+		h.Flush(ctx)
+	}
+}
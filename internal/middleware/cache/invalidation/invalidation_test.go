@@ -0,0 +1,110 @@
+package invalidation_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation"
+	memoryURL "github.com/t1ltxz-gxd/shortify/internal/middleware/cache/memory/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// plainCache is a struct that wraps a URLCache and hides its Flusher implementation.
+type plainCache struct {
+	def.URLCache
+}
+
+// failingCache is a struct that wraps a URLCache and fails every Delete.
+type failingCache struct {
+	def.URLCache
+}
+
+// Delete is a method that mocks the Delete method of the URLCache interface with an error.
+func (failingCache) Delete(_ context.Context, _ string) error {
+	return errors.New("connection refused")
+}
+
+// TestEvictor_Invalidate is a test function that checks that Invalidate deletes the hash from every cache,
+// even if deleting it from one of them fails.
+func TestEvictor_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	first, second := memoryURL.NewCache(10), memoryURL.NewCache(10)
+	for _, c := range []def.URLCache{first, second} {
+		require.NoError(t, c.Create(ctx, "hash", "https://example.com", 0))
+		require.NoError(t, c.Create(ctx, "other", "https://example.org", 0))
+	}
+	evictor := invalidation.NewEvictor(first, failingCache{first}, second)
+
+	evictor.Invalidate(ctx, "hash")
+
+	for _, c := range []def.URLCache{first, second} {
+		_, err := c.Get(ctx, "hash")
+		assert.ErrorIs(t, err, models.ErrorCacheMiss)
+		_, err = c.Get(ctx, "other")
+		assert.NoError(t, err)
+	}
+}
+
+// TestEvictor_Flush is a test function that checks that Flush empties every cache that implements the Flusher interface
+// and skips the others.
+func TestEvictor_Flush(t *testing.T) {
+	ctx := context.Background()
+	flushable, kept := memoryURL.NewCache(10), memoryURL.NewCache(10)
+	for _, c := range []def.URLCache{flushable, kept} {
+		require.NoError(t, c.Create(ctx, "hash", "https://example.com", time.Hour))
+	}
+	evictor := invalidation.NewEvictor(flushable, plainCache{kept})
+
+	evictor.Flush(ctx)
+
+	_, err := flushable.Get(ctx, "hash")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+	_, err = kept.Get(ctx, "hash")
+	assert.NoError(t, err)
+}
+
+// recordingHandler is a struct that implements the Handler interface by recording the messages it gets in a shared log.
+type recordingHandler struct {
+	name string    // The name of the handler in the log
+	log  *[]string // The log shared by the handlers
+}
+
+// Invalidate is a method that records the hash.
+func (h recordingHandler) Invalidate(_ context.Context, hash string) {
+	*h.log = append(*h.log, h.name+" invalidate "+hash)
+}
+
+// Flush is a method that records the flush.
+func (h recordingHandler) Flush(_ context.Context) {
+	*h.log = append(*h.log, h.name+" flush")
+}
+
+// TestChain is a test function that checks that Chain passes every message to each handler in the order they were given.
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	handler := invalidation.Chain(recordingHandler{"first", &log}, recordingHandler{"second", &log})
+
+	handler.Invalidate(ctx, "hash")
+	handler.Flush(ctx)
+
+	assert.Equal(t, []string{
+		"first invalidate hash",
+		"second invalidate hash",
+		"first flush",
+		"second flush",
+	}, log)
+}
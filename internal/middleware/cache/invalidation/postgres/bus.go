@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Ensure that the bus struct implements the Bus interface
+var _ invalidation.Bus = (*bus)(nil)
+
+// Constants for the listener connection
+const (
+	minReconnectInterval = time.Second      // The first delay before reconnecting after connection loss
+	maxReconnectInterval = time.Minute      // The maximum delay between reconnection attempts
+	pingInterval         = 90 * time.Second // The interval between pings that detect a dead connection
+)
+
+// bus is a struct that implements the Bus interface on Postgres LISTEN/NOTIFY.
+type bus struct {
+	db       *sqlx.DB     // The connection pool used to send notifications
+	listener *pq.Listener // The dedicated connection that receives notifications
+	channel  string       // The notification channel
+}
+
+// NewBus is a function that creates a new invalidation bus on Postgres LISTEN/NOTIFY.
+// It takes the connection string of the database and the name of the channel.
+// The listener connects in the background and reconnects by itself after connection loss.
+func NewBus(dsn, channel string) (invalidation.Bus, error) {
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	listener := pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			logger.Warn("Lost the cache invalidation channel, reconnecting", zap.String("channel", channel), zap.Error(err))
+		case pq.ListenerEventConnectionAttemptFailed:
+			logger.Debug("Failed to reconnect to the cache invalidation channel", zap.String("channel", channel), zap.Error(err))
+		case pq.ListenerEventReconnected:
+			logger.Info("Reconnected to the cache invalidation channel", zap.String("channel", channel))
+		}
+	})
+	return &bus{
+		db:       db,
+		listener: listener,
+		channel:  channel,
+	}, nil
+}
+
+// Publish is a method that sends the hash as the payload of a notification on the channel.
+func (b *bus) Publish(ctx context.Context, hash string) error {
+	_, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, b.channel, hash)
+	return err
+}
+
+// Run is a method that listens on the channel and delivers every notification to the handler.
+// After a reconnect the listener sends a nil notification, and Run calls Flush on the handler
+// because notifications sent while the connection was down are lost.
+// It blocks until the context is cancelled.
+func (b *bus) Run(ctx context.Context, handler invalidation.Handler) error {
+	if err := b.listener.Listen(b.channel); err != nil {
+		return err
+	}
+	logger.Info("Listening on the cache invalidation channel", zap.String("channel", b.channel))
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-b.listener.Notify:
+			if n == nil {
+				handler.Flush(ctx)
+				continue
+			}
+			handler.Invalidate(ctx, n.Extra)
+		case <-ticker.C:
+			go func() {
+				_ = b.listener.Ping() // Detects a dead connection and triggers a reconnect
+			}()
+		}
+	}
+}
+
+// Close is a method that closes the listener and the connection pool of the bus.
+func (b *bus) Close() error {
+	err := b.listener.Close()
+	if dbErr := b.db.Close(); err == nil {
+		err = dbErr
+	}
+	return err
+}
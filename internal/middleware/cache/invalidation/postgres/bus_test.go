@@ -0,0 +1,68 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation/postgres"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+// dsnEnv is the environment variable holding the connection string of a Postgres database the tests may use.
+const dsnEnv = "SHORTIFY_TEST_POSTGRES_DSN"
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// recorder is a struct that implements the Handler interface by recording the invalidated hashes.
+type recorder struct {
+	m           sync.Mutex
+	invalidated []string
+}
+
+// Invalidate is a method that records the invalidated hash.
+func (r *recorder) Invalidate(_ context.Context, hash string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.invalidated = append(r.invalidated, hash)
+}
+
+// Flush is a method that does nothing.
+func (r *recorder) Flush(_ context.Context) {}
+
+// TestBus_Publish is a test function that checks that a notification sent by one bus is delivered to the handler of another.
+func TestBus_Publish(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+	listener, err := postgres.NewBus(dsn, "shortify_invalidate_test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	publisher, err := postgres.NewBus(dsn, "shortify_invalidate_test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = publisher.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	handler := &recorder{}
+	go func() { _ = listener.Run(ctx, handler) }()
+
+	// Run starts listening in the background, so the notification is sent until it is delivered
+	assert.Eventually(t, func() bool {
+		if err := publisher.Publish(ctx, "hash"); err != nil {
+			return false
+		}
+		handler.m.Lock()
+		defer handler.m.Unlock()
+		return len(handler.invalidated) > 0 && handler.invalidated[0] == "hash"
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Ensure that the bus struct implements the Bus interface
+var _ invalidation.Bus = (*bus)(nil)
+
+// retryInterval is the time to wait before receiving again after the subscription failed.
+const retryInterval = time.Second
+
+// bus is a struct that implements the Bus interface on Redis pub/sub.
+type bus struct {
+	client  *redis.Client // The Redis client
+	channel string        // The pub/sub channel
+}
+
+// NewBus is a function that creates a new invalidation bus on Redis pub/sub.
+// It takes the Redis client, which is closed together with the bus, and the name of the channel.
+func NewBus(client *redis.Client, channel string) invalidation.Bus {
+	return &bus{
+		client:  client,
+		channel: channel,
+	}
+}
+
+// Publish is a method that publishes the hash on the channel.
+func (b *bus) Publish(ctx context.Context, hash string) error {
+	return b.client.Publish(ctx, b.channel, hash).Err()
+}
+
+// Run is a method that subscribes to the channel and delivers every message to the handler.
+// The Redis client reconnects and resubscribes by itself after connection loss;
+// once the subscription is confirmed again, Run calls Flush on the handler because messages published meanwhile are lost.
+// It blocks until the context is cancelled.
+func (b *bus) Run(ctx context.Context, handler invalidation.Handler) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	go func() {
+		<-ctx.Done()
+		_ = pubsub.Close() // Unblock Receive
+	}()
+
+	missed := false
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !missed {
+				logger.Warn("Lost the cache invalidation channel, reconnecting", zap.String("channel", b.channel), zap.Error(err))
+			}
+			missed = true
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" {
+				continue
+			}
+			logger.Info("Subscribed to the cache invalidation channel", zap.String("channel", b.channel))
+			if missed {
+				handler.Flush(ctx)
+				missed = false
+			}
+		case *redis.Message:
+			handler.Invalidate(ctx, m.Payload)
+		}
+	}
+}
+
+// Close is a method that closes the Redis client of the bus.
+func (b *bus) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,113 @@
+package redis_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation/redis"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+// channel is the pub/sub channel of the bus under test.
+const channel = "shortify_invalidate"
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// recorder is a struct that implements the Handler interface by recording the calls.
+type recorder struct {
+	m           sync.Mutex
+	invalidated []string
+	flushes     int
+}
+
+// Invalidate is a method that records the invalidated hash.
+func (r *recorder) Invalidate(_ context.Context, hash string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.invalidated = append(r.invalidated, hash)
+}
+
+// Flush is a method that counts the flushes.
+func (r *recorder) Flush(_ context.Context) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.flushes++
+}
+
+// calls is a method that returns the recorded hashes and the number of flushes.
+func (r *recorder) calls() ([]string, int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]string(nil), r.invalidated...), r.flushes
+}
+
+// run is a function that starts a bus on the server in the background and waits until it is subscribed.
+// It returns the bus and the handler the messages are delivered to.
+func run(t *testing.T, server *miniredis.Miniredis) *recorder {
+	t.Helper()
+	bus := redis.NewBus(goredis.NewClient(&goredis.Options{Addr: server.Addr()}), channel)
+	t.Cleanup(func() { _ = bus.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	handler := &recorder{}
+	go func() {
+		defer close(done)
+		_ = bus.Run(ctx, handler)
+	}()
+	require.Eventually(t, func() bool { return server.PubSubNumSub(channel)[channel] == 1 }, time.Second, 10*time.Millisecond)
+	return handler
+}
+
+// TestBus_Publish is a test function that checks that a published hash is delivered to the handler of a running bus.
+func TestBus_Publish(t *testing.T) {
+	server := miniredis.RunT(t)
+	handler := run(t, server)
+	publisher := redis.NewBus(goredis.NewClient(&goredis.Options{Addr: server.Addr()}), channel)
+	t.Cleanup(func() { _ = publisher.Close() })
+
+	require.NoError(t, publisher.Publish(context.Background(), "hash"))
+
+	assert.Eventually(t, func() bool {
+		invalidated, _ := handler.calls()
+		return len(invalidated) == 1 && invalidated[0] == "hash"
+	}, time.Second, 10*time.Millisecond)
+	_, flushes := handler.calls()
+	assert.Zero(t, flushes, "the first subscription does not flush")
+}
+
+// TestBus_FlushAfterResubscribe is a test function that checks that the bus flushes the handler once it subscribed again
+// after losing the connection, and delivers the messages published afterwards.
+func TestBus_FlushAfterResubscribe(t *testing.T) {
+	server := miniredis.RunT(t)
+	handler := run(t, server)
+
+	server.Close()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, server.Restart())
+
+	assert.Eventually(t, func() bool {
+		_, flushes := handler.calls()
+		return flushes == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	server.Publish(channel, "hash")
+	assert.Eventually(t, func() bool {
+		invalidated, _ := handler.calls()
+		return len(invalidated) == 1
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,146 @@
+package url
+
+import (
+	"container/list"
+	"context"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ensure that the cache struct implements the URLCache, Flusher and PrefixPurger interfaces
+var (
+	_ def.URLCache     = (*cache)(nil)
+	_ def.Flusher      = (*cache)(nil)
+	_ def.PrefixPurger = (*cache)(nil)
+)
+
+// entry is a struct that holds a cached URL and the time when it expires.
+type entry struct {
+	hash      string    // The hash of the URL
+	url       string    // The original URL
+	expiresAt time.Time // The time when the entry expires, zero if it never expires
+}
+
+// cache is a struct that implements an in-process URL cache.
+// It holds at most size entries and evicts the least recently used one when it is full.
+type cache struct {
+	m     sync.Mutex               // The mutex guarding items and order
+	size  int                      // The maximum number of entries
+	items map[string]*list.Element // The entries by hash
+	order *list.List               // The entries from the most to the least recently used
+}
+
+// NewCache is a function that creates a new in-process cache.
+// It takes the maximum number of entries the cache holds.
+// The returned cache also implements the Flusher and PrefixPurger interfaces.
+func NewCache(size int) def.URLCache {
+	if size < 1 {
+		size = 1
+	}
+	return &cache{
+		size:  size,
+		items: make(map[string]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// Create is a method that adds a new URL to the cache.
+// It takes a context for managing the lifecycle of the operation,
+// a hash which is the unique identifier for the URL,
+// the actual URL string, and an expiration time for the cache entry.
+// If the cache is full, the least recently used entry is evicted.
+func (c *cache) Create(_ context.Context, hash, url string, expiration time.Duration) error {
+	var expiresAt time.Time
+	if expiration > 0 {
+		expiresAt = time.Now().Add(expiration)
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if el, ok := c.items[hash]; ok {
+		el.Value = &entry{hash: hash, url: url, expiresAt: expiresAt}
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.items[hash] = c.order.PushFront(&entry{hash: hash, url: url, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Get is a method that retrieves a URL from the cache using its hash.
+// It takes a context for managing the lifecycle of the operation,
+// and the hash of the URL to retrieve.
+// If the URL is not in the cache or its entry has expired, it returns models.ErrorCacheMiss.
+func (c *cache) Get(_ context.Context, hash string) (*models.URL, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	el, ok := c.items[hash]
+	if !ok {
+		return nil, models.ErrorCacheMiss
+	}
+	e := el.Value.(*entry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.remove(el)
+		return nil, models.ErrorCacheMiss
+	}
+	c.order.MoveToFront(el)
+	return &models.URL{Original: e.url, Hash: e.hash}, nil
+}
+
+// Delete is a method that removes a URL from the cache using its hash.
+// Removing a hash that is not cached is not an error.
+func (c *cache) Delete(_ context.Context, hash string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if el, ok := c.items[hash]; ok {
+		c.remove(el)
+	}
+	return nil
+}
+
+// Flush is a method that removes every entry from the cache.
+func (c *cache) Flush(_ context.Context) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.items = make(map[string]*list.Element, c.size)
+	c.order.Init()
+	return nil
+}
+
+// PurgePrefix is a method that removes every entry whose hash starts with the prefix from the cache.
+// It returns the number of entries removed, expired ones included.
+func (c *cache) PurgePrefix(_ context.Context, prefix string) (int64, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	var n int64
+	for hash, el := range c.items {
+		if strings.HasPrefix(hash, prefix) {
+			c.remove(el)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Ping is a method that checks whether the cache is reachable.
+// The in-process cache is always reachable, so it always returns nil.
+func (c *cache) Ping(_ context.Context) error {
+	return nil
+}
+
+// remove is a method that removes an element from the cache.
+// It must be called with the mutex held.
+func (c *cache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).hash)
+}
@@ -0,0 +1,79 @@
+package url_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/memory/url"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+)
+
+// TestCache_EvictsLeastRecentlyUsed is a test function that checks that a full cache evicts the entry used the longest time ago,
+// and that a read counts as a use.
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := url.NewCache(2)
+	require.NoError(t, cache.Create(ctx, "a", "https://a.example.com", 0))
+	require.NoError(t, cache.Create(ctx, "b", "https://b.example.com", 0))
+	_, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Create(ctx, "c", "https://c.example.com", 0))
+
+	_, err = cache.Get(ctx, "b")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+	got, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, &models.URL{Original: "https://a.example.com", Hash: "a"}, got)
+	_, err = cache.Get(ctx, "c")
+	assert.NoError(t, err)
+}
+
+// TestCache_Expires is a test function that checks that an entry is a miss once its expiration has passed,
+// and that an entry without an expiration is kept.
+func TestCache_Expires(t *testing.T) {
+	ctx := context.Background()
+	cache := url.NewCache(10)
+	require.NoError(t, cache.Create(ctx, "short", "https://short.example.com", 10*time.Millisecond))
+	require.NoError(t, cache.Create(ctx, "forever", "https://forever.example.com", 0))
+
+	_, err := cache.Get(ctx, "short")
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = cache.Get(ctx, "short")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+	_, err = cache.Get(ctx, "forever")
+	assert.NoError(t, err)
+}
+
+// TestCache_DeleteFlushPurge is a test function that checks that Delete removes a single entry,
+// PurgePrefix the entries whose hash starts with the prefix, and Flush every entry.
+func TestCache_DeleteFlushPurge(t *testing.T) {
+	ctx := context.Background()
+	cache := url.NewCache(10)
+	for _, hash := range []string{"abc1", "abc2", "abd1", "xyz1"} {
+		require.NoError(t, cache.Create(ctx, hash, "https://example.com/"+hash, 0))
+	}
+
+	require.NoError(t, cache.Delete(ctx, "xyz1"))
+	require.NoError(t, cache.Delete(ctx, "missing"), "deleting a hash that is not cached is not an error")
+	_, err := cache.Get(ctx, "xyz1")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+
+	n, err := cache.(def.PrefixPurger).PurgePrefix(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	_, err = cache.Get(ctx, "abc1")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+	_, err = cache.Get(ctx, "abd1")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.(def.Flusher).Flush(ctx))
+	_, err = cache.Get(ctx, "abd1")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+}
@@ -1,41 +1,70 @@
 package url
 
 import (
-	"fmt"
-	"github.com/go-redis/redis"
-	"github.com/spf13/viper"
+	"context"
+	"github.com/redis/go-redis/v9"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
 	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
 	"go.uber.org/zap"
-	"os"
+	"net"
+	"strconv"
+	"time"
 )
 
 // URLCache is an interface that defines the methods for URL caching.
-var _ def.URLCache = (*cache)(nil)
+var (
+	_ def.URLCache     = (*cache)(nil)
+	_ def.PrefixPurger = (*cache)(nil)
+)
 
 // cache is a struct that implements the URLCache interface.
-// It contains a client for interacting with the Redis server.
+// It contains a client for interacting with the Redis server,
+// and the timeout applied to every cache operation.
 type cache struct {
-	client *redis.Client
+	client  *redis.Client // The Redis client
+	timeout time.Duration // The timeout of a single cache operation
 }
 
-// Init is a function that initializes a new cache.
-// It creates a new Redis client with the server address, password, and database number
-// specified in the application's configuration.
-// It then pings the Redis server to ensure the connection is successful.
-// If the connection fails, it logs a fatal error.
-// If the connection is successful, it returns a new cache with the Redis client.
-func Init() def.URLCache {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", viper.GetString("redisHost"), viper.GetInt("ports.redis")), // the address of the Redis server
-		Password: os.Getenv("REDIS_PASS"),                                                         // password (if required)
-		DB:       viper.GetInt("RedisDB"),                                                         // use default DB
+// NewClient is a function that creates a new Redis client.
+// It uses the server address from the redisHost and ports.redis settings, the password from the redisPassword setting,
+// and the database number from the redisDB setting of the application's configuration.
+// It does not check the connection.
+func NewClient(cfg *config.Config) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     net.JoinHostPort(cfg.RedisHost, strconv.Itoa(cfg.Ports.Redis)), // the address of the Redis server
+		Password: cfg.RedisPassword,                                              // password (if required)
+		DB:       cfg.RedisDB,                                                    // the database number
 	})
-	_, err := client.Ping().Result()
+}
+
+// Init is a function that initializes a new cache.
+// It takes a context and the configuration of the application.
+// It creates a new Redis client with NewClient,
+// and reads the timeout of a cache operation from the cache.timeout setting in milliseconds.
+// It then pings the Redis server to check the connection.
+// If the connection fails, it logs an error and still returns the cache,
+// so the application can start and serve from the database while Redis is down.
+// It returns a new cache with the Redis client.
+func Init(ctx context.Context, cfg *config.Config) def.URLCache {
+	c := &cache{
+		client:  NewClient(cfg),
+		timeout: time.Duration(cfg.Cache.Timeout) * time.Millisecond,
+	}
+	err := c.Ping(ctx)
 	if err != nil {
-		logger.Fatal("failed to connect to Redis", zap.Error(err))
+		logger.Error("failed to connect to Redis", zap.Error(err))
 	}
-	return &cache{
-		client: client,
+	return c
+}
+
+// withTimeout is a method on the cache struct.
+// It derives a context that is cancelled when the timeout of a cache operation expires,
+// or when the parent context is done, whichever happens first.
+// A timeout of zero leaves the parent context unchanged.
+func (c *cache) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, c.timeout)
 }
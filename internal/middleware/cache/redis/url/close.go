@@ -0,0 +1,7 @@
+package url
+
+// Close is a method that closes the connection pool of the Redis client.
+// It returns an error if the pool could not be closed.
+func (c *cache) Close() error {
+	return c.client.Close()
+}
@@ -2,6 +2,8 @@ package url
 
 import (
 	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"time"
 )
 
@@ -10,9 +12,15 @@ import (
 // a hash which is the unique identifier for the URL,
 // the actual URL string, and an expiration time for the cache entry.
 // It returns an error if the operation fails.
-func (c *cache) Create(_ context.Context, hash, url string, expiration time.Duration) error {
+func (c *cache) Create(ctx context.Context, hash, url string, expiration time.Duration) (err error) {
+	ctx, span := tracing.Start(ctx, "redis.Create", semconv.DBSystemRedis, tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	// Set the URL in the cache with the provided hash and expiration time
-	err := c.client.Set(hash, url, expiration).Err()
+	err = c.client.Set(ctx, hash, url, expiration).Err()
 	// If an error occurs, return the error
 	if err != nil {
 		return err
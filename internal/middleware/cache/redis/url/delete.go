@@ -0,0 +1,22 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// Delete is a method that removes a URL from the cache.
+// It takes a context for managing the lifecycle of the operation,
+// and the hash of the URL to remove.
+// Removing a hash that is not cached is not an error.
+// It returns an error if the operation fails.
+func (c *cache) Delete(ctx context.Context, hash string) (err error) {
+	ctx, span := tracing.Start(ctx, "redis.Delete", semconv.DBSystemRedis, tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Del(ctx, hash).Err()
+}
@@ -2,8 +2,12 @@ package url
 
 import (
 	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.uber.org/zap"
 )
 
@@ -12,9 +16,21 @@ import (
 // and the hash of the URL to retrieve.
 // It returns a pointer to a URL model if the operation is successful,
 // and an error if the operation fails or if the URL is not found in the cache.
-func (c *cache) Get(_ context.Context, hash string) (*models.URL, error) {
+// If the URL is not in the cache, it returns models.ErrorCacheMiss.
+func (c *cache) Get(ctx context.Context, hash string) (_ *models.URL, err error) {
+	ctx, span := tracing.Start(ctx, "redis.Get", semconv.DBSystemRedis, tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err, models.ErrorCacheMiss) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	// Attempt to get the URL from the cache using the provided hash
-	val, err := c.client.Get(hash).Result()
+	val, err := c.client.Get(ctx, hash).Result()
+	// If the URL is not in the cache, report a cache miss
+	if errors.Is(err, redis.Nil) {
+		logger.Debug("URL is not found in the cache", zap.String("hash", hash))
+		return nil, models.ErrorCacheMiss
+	}
 	// If an error occurs, log the error and return nil and the error
 	if err != nil {
 		logger.Error("Failed to fetch URL from the cache", zap.Error(err))
@@ -22,5 +38,5 @@ func (c *cache) Get(_ context.Context, hash string) (*models.URL, error) {
 	}
 	// If the URL is successfully retrieved, log the URL and return a pointer to the URL model and nil for the error
 	logger.Debug("URL is fetched from the cache", zap.String("url", val))
-	return &models.URL{Original: val}, nil
+	return &models.URL{Original: val, Hash: hash}, nil
 }
@@ -0,0 +1,15 @@
+package url
+
+import (
+	"context"
+)
+
+// Ping is a method that checks whether the Redis server is reachable.
+// It takes a context for managing the lifecycle of the operation.
+// It returns an error if the Redis server does not answer the ping.
+func (c *cache) Ping(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Ping(ctx).Err()
+}
@@ -0,0 +1,69 @@
+package url
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"strings"
+)
+
+// purgeBatch is the number of keys PurgePrefix asks Redis to scan at a time.
+const purgeBatch = 500
+
+// globEscaper escapes the characters of a prefix that have a meaning in a SCAN pattern.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// PurgePrefix is a method that removes every URL whose hash starts with the prefix from the cache.
+// It takes a context for managing the lifecycle of the operation, and the prefix.
+// It walks the keys with SCAN, so Redis keeps serving while it runs, and removes them batch by batch with UNLINK,
+// each call limited by the timeout of the cache.
+// The keys holding a colon belong to the other users of the Redis database, like the rate limiter, and are left alone.
+// It returns the number of URLs removed, and an error if a call fails.
+func (c *cache) PurgePrefix(ctx context.Context, prefix string) (n int64, err error) {
+	ctx, span := tracing.Start(ctx, "redis.PurgePrefix", semconv.DBSystemRedis)
+	defer func() { tracing.End(span, err) }()
+
+	pattern := globEscaper.Replace(prefix) + "*"
+	var cursor uint64
+	for {
+		var keys []string
+		keys, cursor, err = c.scan(ctx, cursor, pattern)
+		if err != nil {
+			return n, err
+		}
+
+		hashes := keys[:0]
+		for _, key := range keys {
+			if !strings.Contains(key, ":") {
+				hashes = append(hashes, key)
+			}
+		}
+		if len(hashes) > 0 {
+			removed, err := c.unlink(ctx, hashes)
+			n += removed
+			if err != nil {
+				return n, err
+			}
+		}
+
+		if cursor == 0 {
+			return n, nil
+		}
+	}
+}
+
+// scan is a method that returns the next batch of keys matching the pattern, and the cursor of the batch after it.
+func (c *cache) scan(ctx context.Context, cursor uint64, pattern string) ([]string, uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Scan(ctx, cursor, pattern, purgeBatch).Result()
+}
+
+// unlink is a method that removes the keys and returns the number of keys that existed.
+func (c *cache) unlink(ctx context.Context, keys []string) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Unlink(ctx, keys...).Result()
+}
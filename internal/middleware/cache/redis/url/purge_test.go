@@ -0,0 +1,51 @@
+package url_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/redis/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// TestPurgePrefix is a test function that checks that a purge removes the URLs whose hash starts with the prefix,
+// takes the prefix literally, and leaves the keys of the other users of the Redis database alone.
+func TestPurgePrefix(t *testing.T) {
+	server := miniredis.RunT(t)
+	port, err := strconv.Atoi(server.Port())
+	require.NoError(t, err)
+	cfg := &config.Config{RedisHost: server.Host(), Ports: config.Ports{Redis: port}}
+
+	ctx := context.Background()
+	cache := url.Init(ctx, cfg)
+	for _, hash := range []string{"abc1", "abc2", "abd1", "a*c1"} {
+		require.NoError(t, cache.Create(ctx, hash, "https://example.com/"+hash, 0))
+	}
+	require.NoError(t, server.Set("abc:ratelimit", "1"))
+
+	n, err := cache.(def.PrefixPurger).PurgePrefix(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.False(t, server.Exists("abc1"))
+	assert.False(t, server.Exists("abc2"))
+	assert.True(t, server.Exists("abd1"))
+	assert.True(t, server.Exists("abc:ratelimit"), "the keys of the rate limiter are not URLs")
+
+	n, err = cache.(def.PrefixPurger).PurgePrefix(ctx, "a*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.True(t, server.Exists("abd1"), "the prefix is not a pattern")
+}
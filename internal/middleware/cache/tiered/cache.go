@@ -0,0 +1,105 @@
+package tiered
+
+import (
+	"context"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Ensure that the cache struct implements the URLCache, Flusher and PrefixPurger interfaces
+var (
+	_ def.URLCache     = (*cache)(nil)
+	_ def.Flusher      = (*cache)(nil)
+	_ def.PrefixPurger = (*cache)(nil)
+)
+
+// cache is a struct that combines a local cache of this instance with a cache shared by every instance.
+// Reads go to the local cache first, writes and deletes go to both.
+type cache struct {
+	local    def.URLCache  // The cache local to this instance
+	shared   def.URLCache  // The cache shared by every instance
+	localTTL time.Duration // The maximum lifetime of a local entry
+}
+
+// NewCache is a function that creates a new two-tier cache.
+// It takes the local cache, the shared cache and the maximum lifetime of a local entry.
+// The local lifetime bounds how long an instance can serve a stale entry if an invalidation message is lost.
+func NewCache(local, shared def.URLCache, localTTL time.Duration) def.URLCache {
+	return &cache{
+		local:    local,
+		shared:   shared,
+		localTTL: localTTL,
+	}
+}
+
+// Create is a method that adds a new URL to both caches.
+// The local entry expires after the smaller of the expiration and the local lifetime.
+// It returns the error of the shared cache.
+func (c *cache) Create(ctx context.Context, hash, url string, expiration time.Duration) error {
+	_ = c.local.Create(ctx, hash, url, c.localExpiration(expiration))
+	return c.shared.Create(ctx, hash, url, expiration)
+}
+
+// Get is a method that retrieves a URL from the local cache, or from the shared cache on a local miss.
+// A URL found in the shared cache is copied into the local cache.
+func (c *cache) Get(ctx context.Context, hash string) (*models.URL, error) {
+	url, err := c.local.Get(ctx, hash)
+	if err == nil {
+		logger.Debug("URL is fetched from the local cache", zap.String("hash", hash))
+		return url, nil
+	}
+
+	url, err = c.shared.Get(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.local.Create(ctx, hash, url.Original, c.localTTL)
+	return url, nil
+}
+
+// Delete is a method that removes a URL from both caches.
+// It returns the error of the shared cache.
+func (c *cache) Delete(ctx context.Context, hash string) error {
+	_ = c.local.Delete(ctx, hash)
+	return c.shared.Delete(ctx, hash)
+}
+
+// Flush is a method that removes every entry from the local cache.
+// The shared cache is left untouched, it is invalidated directly by the instance that changed a link.
+func (c *cache) Flush(ctx context.Context) error {
+	if f, ok := c.local.(def.Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// PurgePrefix is a method that removes every entry whose hash starts with the prefix from both caches.
+// The local caches of the other instances keep their entries until they expire after the local lifetime.
+// It returns the number of entries removed from the shared cache, and models.ErrorPurgeUnsupported
+// if the shared cache cannot purge by prefix.
+func (c *cache) PurgePrefix(ctx context.Context, prefix string) (int64, error) {
+	if p, ok := c.local.(def.PrefixPurger); ok {
+		_, _ = p.PurgePrefix(ctx, prefix)
+	}
+	p, ok := c.shared.(def.PrefixPurger)
+	if !ok {
+		return 0, models.ErrorPurgeUnsupported
+	}
+	return p.PurgePrefix(ctx, prefix)
+}
+
+// Ping is a method that checks whether the shared cache is reachable.
+func (c *cache) Ping(ctx context.Context) error {
+	return c.shared.Ping(ctx)
+}
+
+// localExpiration is a method that returns the expiration of a local entry for a given expiration.
+func (c *cache) localExpiration(expiration time.Duration) time.Duration {
+	if expiration <= 0 || expiration > c.localTTL {
+		return c.localTTL
+	}
+	return expiration
+}
@@ -0,0 +1,79 @@
+package tiered_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	memoryURL "github.com/t1ltxz-gxd/shortify/internal/middleware/cache/memory/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/tiered"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// newCache is a function that creates a two-tier cache over two in-process caches, and returns it with both tiers.
+func newCache() (cache, local, shared def.URLCache) {
+	local, shared = memoryURL.NewCache(10), memoryURL.NewCache(10)
+	return tiered.NewCache(local, shared, time.Minute), local, shared
+}
+
+// TestCache_ReadThrough is a test function that checks that a local miss is served from the shared cache
+// and copied into the local cache.
+func TestCache_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	cache, local, shared := newCache()
+	require.NoError(t, shared.Create(ctx, "hash", "https://example.com", 0))
+
+	got, err := cache.Get(ctx, "hash")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", got.Original)
+	got, err = local.Get(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", got.Original)
+
+	_, err = cache.Get(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+}
+
+// TestCache_WriteThroughAndDelete is a test function that checks that Create writes to both tiers and Delete removes from both.
+func TestCache_WriteThroughAndDelete(t *testing.T) {
+	ctx := context.Background()
+	cache, local, shared := newCache()
+
+	require.NoError(t, cache.Create(ctx, "hash", "https://example.com", time.Hour))
+	_, err := local.Get(ctx, "hash")
+	require.NoError(t, err)
+	_, err = shared.Get(ctx, "hash")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Delete(ctx, "hash"))
+	_, err = local.Get(ctx, "hash")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+	_, err = shared.Get(ctx, "hash")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+}
+
+// TestCache_FlushLocalOnly is a test function that checks that Flush empties the local tier and leaves the shared tier alone.
+func TestCache_FlushLocalOnly(t *testing.T) {
+	ctx := context.Background()
+	cache, local, shared := newCache()
+	require.NoError(t, cache.Create(ctx, "hash", "https://example.com", 0))
+
+	require.NoError(t, cache.(def.Flusher).Flush(ctx))
+
+	_, err := local.Get(ctx, "hash")
+	assert.ErrorIs(t, err, models.ErrorCacheMiss)
+	_, err = shared.Get(ctx, "hash")
+	assert.NoError(t, err)
+}
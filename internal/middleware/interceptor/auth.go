@@ -0,0 +1,176 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// Metadata keys of the credentials of the callers
+const (
+	APIKeyKey        = "x-api-key"     // The API key of a caller of the URL service
+	AuthorizationKey = "authorization" // The bearer token of a caller of the URL service
+	AdminTokenKey    = "x-admin-token" // The admin token of a caller of the admin services
+)
+
+// bearerPrefix is the scheme of a bearer token in the authorization metadata.
+const bearerPrefix = "bearer "
+
+// KeyAuthenticator is an interface that finds the active API key a caller presented.
+// It is implemented by service.APIKeyService.
+type KeyAuthenticator interface {
+	// Authenticate is a method that returns the description of an active API key,
+	// and models.ErrorAPIKeyNotFound if the key is unknown or revoked.
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// TokenVerifier is an interface that checks the bearer token a caller presented.
+// It is implemented by bearer.Verifier.
+type TokenVerifier interface {
+	// Verify is a method that returns the caller a valid token was issued to, and an error if the token is not valid.
+	Verify(ctx context.Context, token string) (*auth.Principal, error)
+}
+
+// Bearer is a function that returns a unary server interceptor that authenticates the callers of the listed RPCs
+// with the JSON Web Token in the authorization metadata, sent as "Bearer <token>".
+// It takes the verifier of the tokens and the full method names of the RPCs that accept a token;
+// the other RPCs, and the calls without a token, pass through, so a later interceptor can ask for an API key instead.
+// The caller is put in the context of the RPC as an auth.Principal with the subject and the scopes of the token.
+// An invalid token fails the RPC with Unauthenticated.
+func Bearer(verifier TokenVerifier, methods ...string) grpc.UnaryServerInterceptor {
+	protected := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		protected[method] = struct{}{}
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := protected[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+
+		value := firstValue(ctx, AuthorizationKey)
+		if value == "" {
+			return handler(ctx, req)
+		}
+		if len(value) < len(bearerPrefix) || !strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			return nil, status.Error(codes.Unauthenticated, "the "+AuthorizationKey+" metadata must hold a bearer token")
+		}
+		principal, err := verifier.Verify(ctx, strings.TrimSpace(value[len(bearerPrefix):]))
+		if err != nil {
+			logger.Debug("Rejected bearer token", zap.String("method", info.FullMethod), zap.Error(err))
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		}
+
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// APIKey is a function that returns a unary server interceptor that authenticates the callers of the listed RPCs
+// with the API key in the x-api-key metadata.
+// It takes the authenticator of the keys and the full method names of the RPCs that need a key, like "/url_v1.UrlV1/Create";
+// the other RPCs pass through without one, and so do the callers an earlier interceptor authenticated, like with a bearer token.
+// The key is put in the context of the RPC as an auth.Principal whose subject is the ID of the key, with every scope.
+// A missing, unknown or revoked key fails the RPC with Unauthenticated, and a failed lookup with Unavailable.
+func APIKey(authenticator KeyAuthenticator, methods ...string) grpc.UnaryServerInterceptor {
+	protected := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		protected[method] = struct{}{}
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := protected[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+		if _, ok := auth.FromContext(ctx); ok {
+			return handler(ctx, req)
+		}
+
+		key := firstValue(ctx, APIKeyKey)
+		if key == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing API key in the "+APIKeyKey+" metadata")
+		}
+		apiKey, err := authenticator.Authenticate(ctx, key)
+		if errors.Is(err, models.ErrorAPIKeyNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		if err != nil {
+			logger.Error("Failed to authenticate API key", zap.String("method", info.FullMethod), zap.Error(err))
+			return nil, status.Error(codes.Unavailable, "cannot verify the API key")
+		}
+
+		return handler(auth.WithPrincipal(ctx, &auth.Principal{
+			Subject: auth.KeySubject(apiKey.ID),
+			Name:    apiKey.Name,
+			Scopes:  []string{auth.ScopeRead, auth.ScopeWrite},
+		}), req)
+	}
+}
+
+// Authenticated is a function that returns a unary server interceptor that refuses the calls of the listed RPCs
+// that no earlier interceptor authenticated, with Unauthenticated.
+// It takes the full method names of the RPCs that need a caller; the other RPCs pass through.
+// It requires a bearer token where API keys are disabled.
+func Authenticated(methods ...string) grpc.UnaryServerInterceptor {
+	protected := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		protected[method] = struct{}{}
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := protected[info.FullMethod]; ok {
+			if _, ok := auth.FromContext(ctx); !ok {
+				return nil, status.Error(codes.Unauthenticated, "missing bearer token in the "+AuthorizationKey+" metadata")
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AdminToken is a function that returns a unary server interceptor that guards the RPCs of the listed services
+// with the admin token in the x-admin-token metadata.
+// It takes the admin token and the full names of the services, like "apikey_v1.ApiKeyV1"; the other RPCs pass through.
+// The tokens are compared in constant time.
+// A missing or wrong token fails the RPC with Unauthenticated. Without an admin token the services cannot be called
+// at all and fail with PermissionDenied.
+func AdminToken(token string, services ...string) grpc.UnaryServerInterceptor {
+	prefixes := make([]string, 0, len(services))
+	for _, service := range services {
+		prefixes = append(prefixes, "/"+service+"/")
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		guarded := false
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				guarded = true
+				break
+			}
+		}
+		if !guarded {
+			return handler(ctx, req)
+		}
+
+		if token == "" {
+			return nil, status.Error(codes.PermissionDenied, "admin RPCs are disabled, no admin token is configured")
+		}
+		if subtle.ConstantTimeCompare([]byte(firstValue(ctx, AdminTokenKey)), []byte(token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// firstValue is a function that returns the first value of a key in the incoming metadata of the context,
+// or an empty string if there is none.
+func firstValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
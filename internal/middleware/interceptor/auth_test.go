@@ -0,0 +1,160 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/interceptor"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAuthenticator is a struct that authenticates the keys of a map, or fails every lookup with err.
+type fakeAuthenticator struct {
+	keys map[string]*models.APIKey
+	err  error
+}
+
+// Authenticate is a method that returns the key from the map.
+func (a *fakeAuthenticator) Authenticate(_ context.Context, key string) (*models.APIKey, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	if apiKey, ok := a.keys[key]; ok {
+		return apiKey, nil
+	}
+	return nil, models.ErrorAPIKeyNotFound
+}
+
+// fakeVerifier is a struct that accepts the tokens of a map.
+type fakeVerifier struct {
+	tokens map[string]*auth.Principal
+}
+
+// Verify is a method that returns the principal of the token from the map.
+func (v *fakeVerifier) Verify(_ context.Context, token string) (*auth.Principal, error) {
+	if principal, ok := v.tokens[token]; ok {
+		return principal, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// call is a function that runs a unary interceptor on an RPC of the method with the incoming metadata.
+// It returns the principal the handler found in its context, if it was called, and the error of the RPC.
+func call(interceptor grpc.UnaryServerInterceptor, method string, md metadata.MD) (*auth.Principal, error) {
+	var principal *auth.Principal
+	handler := func(ctx context.Context, _ any) (any, error) {
+		principal, _ = auth.FromContext(ctx)
+		return "ok", nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return principal, err
+}
+
+// TestAPIKey is a test function that checks that the APIKey interceptor lets the public RPCs through without a key,
+// rejects the protected RPCs without a valid key, and puts the owner of a valid key in the context.
+func TestAPIKey(t *testing.T) {
+	authenticator := &fakeAuthenticator{keys: map[string]*models.APIKey{"shk_valid": {ID: "key1", Name: "alice"}}}
+	apiKey := interceptor.APIKey(authenticator, "/url_v1.UrlV1/Create")
+
+	principal, err := call(apiKey, "/url_v1.UrlV1/Get", metadata.MD{})
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+
+	_, err = call(apiKey, "/url_v1.UrlV1/Create", metadata.MD{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(apiKey, "/url_v1.UrlV1/Create", metadata.Pairs(interceptor.APIKeyKey, "shk_revoked"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	principal, err = call(apiKey, "/url_v1.UrlV1/Create", metadata.Pairs(interceptor.APIKeyKey, "shk_valid"))
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.Equal(t, "key:key1", principal.Subject)
+	assert.Equal(t, "alice", principal.Name)
+
+	authenticator.err = errors.New("database is down")
+	_, err = call(apiKey, "/url_v1.UrlV1/Create", metadata.Pairs(interceptor.APIKeyKey, "shk_valid"))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+// TestBearer is a test function that checks that the Bearer interceptor puts the caller of a valid token in the context,
+// rejects an invalid token, and leaves the calls without a token to the APIKey interceptor after it.
+func TestBearer(t *testing.T) {
+	verifier := &fakeVerifier{tokens: map[string]*auth.Principal{
+		"valid": {Subject: "user1", Scopes: []string{auth.ScopeRead}},
+	}}
+	authenticator := &fakeAuthenticator{keys: map[string]*models.APIKey{"shk_valid": {ID: "key1"}}}
+	chain := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		apiKey := interceptor.APIKey(authenticator, "/url_v1.UrlV1/Search")
+		return interceptor.Bearer(verifier, "/url_v1.UrlV1/Search")(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return apiKey(ctx, req, info, handler)
+		})
+	}
+
+	principal, err := call(chain, "/url_v1.UrlV1/Search", metadata.Pairs(interceptor.AuthorizationKey, "Bearer valid"))
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.Equal(t, "user1", principal.Subject)
+	assert.Equal(t, []string{auth.ScopeRead}, principal.Scopes)
+
+	_, err = call(chain, "/url_v1.UrlV1/Search", metadata.Pairs(interceptor.AuthorizationKey, "Bearer forged"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(chain, "/url_v1.UrlV1/Search", metadata.Pairs(interceptor.AuthorizationKey, "Basic dXNlcjpwYXNz"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	principal, err = call(chain, "/url_v1.UrlV1/Search", metadata.Pairs(interceptor.APIKeyKey, "shk_valid"))
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.Equal(t, "key:key1", principal.Subject)
+	assert.ElementsMatch(t, []string{auth.ScopeRead, auth.ScopeWrite}, principal.Scopes)
+
+	_, err = call(chain, "/url_v1.UrlV1/Search", metadata.MD{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// TestAuthenticated is a test function that checks that the Authenticated interceptor refuses the listed RPCs without a caller.
+func TestAuthenticated(t *testing.T) {
+	authenticated := interceptor.Authenticated("/url_v1.UrlV1/Create")
+
+	_, err := call(authenticated, "/url_v1.UrlV1/Create", metadata.MD{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(authenticated, "/url_v1.UrlV1/Get", metadata.MD{})
+	assert.NoError(t, err)
+
+	handler := func(ctx context.Context, _ any) (any, error) { return "ok", nil }
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "user1"})
+	_, err = authenticated(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/url_v1.UrlV1/Create"}, handler)
+	assert.NoError(t, err)
+}
+
+// TestAdminToken is a test function that checks that the AdminToken interceptor guards only the listed services,
+// and refuses them entirely without a configured token.
+func TestAdminToken(t *testing.T) {
+	admin := interceptor.AdminToken("secret-admin-token", "apikey_v1.ApiKeyV1")
+
+	_, err := call(admin, "/url_v1.UrlV1/Create", metadata.MD{})
+	assert.NoError(t, err)
+
+	_, err = call(admin, "/apikey_v1.ApiKeyV1/Create", metadata.MD{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(admin, "/apikey_v1.ApiKeyV1/Create", metadata.Pairs(interceptor.AdminTokenKey, "wrong"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(admin, "/apikey_v1.ApiKeyV1/Create", metadata.Pairs(interceptor.AdminTokenKey, "secret-admin-token"))
+	assert.NoError(t, err)
+
+	_, err = call(interceptor.AdminToken("", "apikey_v1.ApiKeyV1"), "/apikey_v1.ApiKeyV1/List", metadata.Pairs(interceptor.AdminTokenKey, ""))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,72 @@
+package interceptor
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// Logging is a function that returns a unary server interceptor writing an access log line for every RPC
+// through the logger package, with the method, the address of the peer, the status code, the duration
+// and the request ID assigned by RequestID.
+// The RPCs that fail because of the server are logged as errors, the others as info.
+func Logging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		started := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, info.FullMethod, started, err)
+		return resp, err
+	}
+}
+
+// LoggingStream is a function that returns a stream server interceptor writing an access log line for every stream
+// once it ends, like Logging does for unary RPCs.
+func LoggingStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		started := time.Now()
+		err := handler(srv, ss)
+		logRPC(ss.Context(), info.FullMethod, started, err)
+		return err
+	}
+}
+
+// logRPC is a function that writes the access log line of an RPC that started at started and returned err.
+func logRPC(ctx context.Context, method string, started time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("peer", peerAddress(ctx)),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(started)),
+		zap.String("request_id", RequestIDFromContext(ctx)),
+	}
+	if serverFault(code) {
+		logger.Error("RPC failed", append(fields, zap.Error(err))...)
+		return
+	}
+	logger.Info("RPC handled", fields...)
+}
+
+// peerAddress is a function that returns the address of the client of the RPC the context belongs to, if it is known.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
+
+// serverFault is a function that reports whether a status code means that the server failed rather than the request.
+func serverFault(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
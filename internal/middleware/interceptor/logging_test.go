@@ -0,0 +1,47 @@
+package interceptor_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/interceptor"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// TestLogging is a test function that checks that the logging interceptors pass the response and the error
+// of the handler through, whether the RPC succeeds, fails because of the request, or fails because of the server.
+func TestLogging(t *testing.T) {
+	intercept := interceptor.Logging()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}})
+
+	resp, err := intercept(ctx, "request", info, func(context.Context, any) (any, error) { return "response", nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "response", resp)
+
+	for _, code := range []codes.Code{codes.InvalidArgument, codes.Internal} {
+		_, err = intercept(ctx, "request", info, func(context.Context, any) (any, error) {
+			return nil, status.Error(code, "failed")
+		})
+		assert.Equal(t, code, status.Code(err))
+	}
+
+	ss := &fakeStream{ctx: ctx}
+	err = interceptor.LoggingStream()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}, func(any, grpc.ServerStream) error {
+		return status.Error(codes.Unavailable, "down")
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,24 @@
+package interceptor
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// Metrics is a function that returns a unary server interceptor recording every RPC in the metrics package.
+// It counts the RPC in metrics.RPCRequests by its full method name and status code,
+// and observes its duration in metrics.RPCDuration.
+// An error that does not carry a gRPC status is counted with the Unknown code, as gRPC reports it to the client.
+func Metrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		started := time.Now()
+		resp, err := handler(ctx, req)
+
+		metrics.RPCDuration.WithLabelValues(info.FullMethod).Observe(time.Since(started).Seconds())
+		metrics.RPCRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return resp, err
+	}
+}
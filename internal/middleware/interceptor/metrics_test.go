@@ -0,0 +1,35 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestMetrics is a test function that checks that the metrics interceptor counts every RPC by method and status code
+// and passes the response and the error of the handler through.
+func TestMetrics(t *testing.T) {
+	intercept := interceptor.Metrics()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	ok := func(context.Context, any) (any, error) { return "response", nil }
+	notFound := func(context.Context, any) (any, error) { return nil, status.Error(codes.NotFound, "missing") }
+
+	resp, err := intercept(context.Background(), "request", info, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, "response", resp)
+
+	_, err = intercept(context.Background(), "request", info, notFound)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, _ = intercept(context.Background(), "request", info, notFound)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RPCRequests.WithLabelValues(info.FullMethod, codes.OK.String())))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.RPCRequests.WithLabelValues(info.FullMethod, codes.NotFound.String())))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.RPCDuration, "shortify_grpc_request_duration_seconds"))
+}
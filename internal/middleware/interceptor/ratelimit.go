@@ -0,0 +1,53 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimit is a function that returns a unary server interceptor that limits the calls of every client to the listed RPCs.
+// It takes the limiter, how the clients are told apart, and the rules by full method name, like "/url_v1.UrlV1/Create";
+// the RPCs without a rule are not limited.
+// It runs after the authentication, so the calls can be counted by the caller.
+// A refused call fails with ResourceExhausted and a retry-after header with the seconds to wait.
+// If the limiter fails, the call is let through, so an outage of the limiter does not take the server down.
+func RateLimit(limiter ratelimit.Limiter, policy ratelimit.Policy, rules map[string]ratelimit.Rule) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		rule, ok := rules[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		var remoteAddr string
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+		id := ratelimit.Identity{
+			Subject: auth.Owner(ctx),
+			Tenant:  firstValue(ctx, ratelimit.TenantKey),
+			IP:      policy.ClientIP(firstValue(ctx, ratelimit.ForwardedForKey), remoteAddr),
+		}
+		result, err := limiter.Allow(ctx, policy.Key(rule, id), rule.Current())
+		if err != nil {
+			logger.Error("Failed to check the rate limit", zap.String("method", info.FullMethod), zap.Error(err))
+			return handler(ctx, req)
+		}
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(rule.Class).Inc()
+			retryAfter := ratelimit.RetryAfterSeconds(result.RetryAfter)
+			_ = grpc.SetHeader(ctx, metadata.Pairs(ratelimit.RetryAfterKey, retryAfter))
+			return nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry in %ss", retryAfter))
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,76 @@
+package interceptor_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/interceptor"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit/local"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTransportStream is a struct that implements grpc.ServerTransportStream and records the headers it is sent.
+type fakeTransportStream struct {
+	header metadata.MD
+}
+
+// Method is a method that returns the name of the RPC.
+func (s *fakeTransportStream) Method() string { return "" }
+
+// SetHeader is a method that records the headers.
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader is a method that records the headers.
+func (s *fakeTransportStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+// SetTrailer is a method that ignores the trailers.
+func (s *fakeTransportStream) SetTrailer(metadata.MD) error { return nil }
+
+// TestRateLimit is a test function that checks that the RateLimit interceptor refuses a client over its limit
+// with ResourceExhausted and a retry-after header, counts every caller apart, and does not limit the RPCs without a rule.
+func TestRateLimit(t *testing.T) {
+	rateLimit := interceptor.RateLimit(local.NewLimiter(), ratelimit.Policy{KeyBy: ratelimit.KeyByAPIKey}, map[string]ratelimit.Rule{
+		"/url_v1.UrlV1/Create": {Class: "create", Limit: ratelimit.Limit{Rate: 1, Burst: 2}},
+	})
+	invoke := func(method, subject string) (*fakeTransportStream, error) {
+		stream := &fakeTransportStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 7), Port: 1234}})
+		if subject != "" {
+			ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: subject})
+		}
+		handler := func(context.Context, any) (any, error) { return "ok", nil }
+		_, err := rateLimit(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return stream, err
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := invoke("/url_v1.UrlV1/Create", "key1")
+		require.NoError(t, err)
+	}
+	stream, err := invoke("/url_v1.UrlV1/Create", "key1")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"1"}, stream.header.Get(ratelimit.RetryAfterKey))
+
+	_, err = invoke("/url_v1.UrlV1/Create", "key2")
+	assert.NoError(t, err, "another caller has a bucket of its own")
+	_, err = invoke("/url_v1.UrlV1/Create", "")
+	assert.NoError(t, err, "an anonymous caller is counted by address")
+
+	for i := 0; i < 5; i++ {
+		_, err = invoke("/url_v1.UrlV1/Get", "key1")
+		require.NoError(t, err)
+	}
+}
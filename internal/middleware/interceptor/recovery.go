@@ -0,0 +1,49 @@
+package interceptor
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+)
+
+// Recovery is a function that returns a unary server interceptor that keeps a panicking handler from killing the process.
+// The panic is logged through the logger package with the method, the request ID and the stack trace,
+// and the RPC fails with codes.Internal, without telling the client what went wrong.
+func Recovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStream is a function that returns a stream server interceptor that keeps a panicking handler
+// from killing the process, like Recovery does for unary RPCs.
+func RecoveryStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered is a function that logs a panic recovered from the handler of an RPC with the stack trace.
+// It returns the error the RPC fails with.
+func recovered(ctx context.Context, method string, r any) error {
+	logger.Error("Recovered from a panic in an RPC handler",
+		zap.String("method", method),
+		zap.String("request_id", RequestIDFromContext(ctx)),
+		zap.Any("panic", r),
+		zap.ByteString("stack", debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,43 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRecovery is a test function that checks that the Recovery interceptor turns a panic into codes.Internal
+// and passes the response and the error of a handler that does not panic through.
+func TestRecovery(t *testing.T) {
+	intercept := interceptor.Recovery()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	resp, err := intercept(context.Background(), "request", info, func(context.Context, any) (any, error) {
+		panic("nil map")
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "nil map", "the panic leaks to the client")
+
+	failure := errors.New("failure")
+	resp, err = intercept(context.Background(), "request", info, func(context.Context, any) (any, error) {
+		return "response", failure
+	})
+	assert.Equal(t, "response", resp)
+	assert.Equal(t, failure, err)
+}
+
+// TestRecoveryStream is a test function that checks that the RecoveryStream interceptor turns a panic into codes.Internal.
+func TestRecoveryStream(t *testing.T) {
+	ss := &fakeStream{ctx: context.Background()}
+	err := interceptor.RecoveryStream()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}, func(any, grpc.ServerStream) error {
+		panic(errors.New("boom"))
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
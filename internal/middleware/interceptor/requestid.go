@@ -0,0 +1,93 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDKey is the metadata key carrying the ID of a request, in the request and in the response headers.
+const RequestIDKey = "x-request-id"
+
+// maxRequestIDLength is the maximum length of a request ID accepted from a client.
+const maxRequestIDLength = 128
+
+// requestIDContextKey is the type of the key the request ID is stored under in the context of a request.
+type requestIDContextKey struct{}
+
+// RequestID is a function that returns a unary server interceptor that assigns an ID to every RPC.
+// It keeps the ID sent by the client in the x-request-id metadata, so a request can be followed across services,
+// or generates a new one if there is none or it is malformed.
+// The ID is stored in the context, where RequestIDFromContext finds it, and sent back in the x-request-id response header.
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := requestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, id)) // Fails only if the headers were already sent
+		return handler(context.WithValue(ctx, requestIDContextKey{}, id), req)
+	}
+}
+
+// RequestIDStream is a function that returns a stream server interceptor that assigns an ID to every stream,
+// like RequestID does for unary RPCs.
+func RequestIDStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := requestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(RequestIDKey, id)) // Fails only if the headers were already sent
+		return handler(srv, &serverStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), requestIDContextKey{}, id)})
+	}
+}
+
+// RequestIDFromContext is a function that returns the ID of the request the context belongs to,
+// or an empty string if it does not belong to an RPC that went through the RequestID interceptors.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// requestID is a function that returns the valid request ID in the incoming metadata of the context,
+// or a new random one.
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, id := range md.Get(RequestIDKey) {
+		if validRequestID(id) {
+			return id
+		}
+	}
+	return newRequestID()
+}
+
+// validRequestID is a function that reports whether a request ID sent by a client is safe to log and send back:
+// not empty, not longer than maxRequestIDLength, and made of letters, digits, and the characters - _ . and :.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID is a function that generates a random request ID of 32 hexadecimal characters.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // Never fails on the supported platforms
+	return hex.EncodeToString(b)
+}
+
+// serverStream is a struct that wraps a grpc.ServerStream to replace its context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context // The context returned instead of the one of the wrapped stream
+}
+
+// Context is a method on the serverStream struct. It returns the replaced context.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
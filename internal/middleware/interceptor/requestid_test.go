@@ -0,0 +1,79 @@
+package interceptor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeStream is a struct that implements grpc.ServerStream over a context and records the headers it is sent.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+// Context is a method that returns the context of the stream.
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+// SetHeader is a method that records the headers.
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// requestIDOf is a function that runs the RequestID interceptor on an RPC with the incoming metadata
+// and returns the request ID the handler found in its context.
+func requestIDOf(t *testing.T, md metadata.MD) string {
+	var id string
+	handler := func(ctx context.Context, _ any) (any, error) {
+		id = interceptor.RequestIDFromContext(ctx)
+		return nil, nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := interceptor.RequestID()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler)
+	require.NoError(t, err)
+	return id
+}
+
+// TestRequestID is a test function that checks that the RequestID interceptor keeps a valid request ID from the client
+// and generates a new one when there is none or it is malformed.
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "req-42.a:b_c", requestIDOf(t, metadata.Pairs(interceptor.RequestIDKey, "req-42.a:b_c")))
+
+	generated := requestIDOf(t, metadata.MD{})
+	assert.Len(t, generated, 32)
+	assert.NotEqual(t, generated, requestIDOf(t, metadata.MD{}), "two generated IDs are equal")
+
+	for _, malformed := range []string{"", "with space", "line\nbreak", strings.Repeat("a", 129)} {
+		id := requestIDOf(t, metadata.Pairs(interceptor.RequestIDKey, malformed))
+		assert.NotEqual(t, malformed, id)
+		assert.Len(t, id, 32)
+	}
+
+	assert.Empty(t, interceptor.RequestIDFromContext(context.Background()))
+}
+
+// TestRequestIDStream is a test function that checks that the RequestIDStream interceptor stores the request ID
+// in the context of the stream and sends it back in the response headers.
+func TestRequestIDStream(t *testing.T) {
+	ss := &fakeStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(interceptor.RequestIDKey, "abc"))}
+	var id string
+	handler := func(_ any, stream grpc.ServerStream) error {
+		id = interceptor.RequestIDFromContext(stream.Context())
+		return nil
+	}
+
+	err := interceptor.RequestIDStream()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", id)
+	assert.Equal(t, []string{"abc"}, ss.header.Get(interceptor.RequestIDKey))
+}
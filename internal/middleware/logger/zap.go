@@ -1,12 +1,12 @@
 package logger
 
 import (
-	"errors"
-	"github.com/spf13/viper"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 	"os"
+	"time"
 )
 
 // Constants for environment names
@@ -17,46 +17,159 @@ const (
 	envProduction  = "production"  // Production environment full name
 )
 
+// Constants for the encodings of the log entries
+const (
+	EncodingJSON    = "json"    // One JSON object per entry
+	EncodingConsole = "console" // Tab-separated fields, easier to read in a terminal
+)
+
+// Constants for the targets the log entries are written to
+const (
+	OutputStdout = "stdout" // The standard output
+	OutputStderr = "stderr" // The standard error
+	OutputFile   = "file"   // The rotated log file described by File
+)
+
+// Options is a struct that holds the configuration of the logger.
+// The empty values take the defaults of the environment.
+type Options struct {
+	Name     string   // The name of the logger, added to every entry
+	Level    string   // The lowest level logged: debug, info, warn or error; debug in development and info in production if empty
+	Encoding string   // The encoding of the entries: json or console; console in development and json in production if empty
+	Outputs  []string // Where the entries are written: stdout, stderr or file; stderr in development, and stdout and file in production if empty
+	Sampling Sampling // The sampling of the repeated entries
+	File     File     // The rotated log file, used if file is one of the outputs
+}
+
+// Sampling is a struct that holds how the repeated entries are sampled to bound the cost of logging under load.
+// Every second, the first Initial entries with the same level and message are logged, then every Thereafter-th of them.
+type Sampling struct {
+	Enabled    bool // Whether the entries are sampled
+	Initial    int  // The number of entries with the same level and message logged every second before sampling
+	Thereafter int  // The sampling rate after that, one entry in Thereafter is logged
+}
+
+// File is a struct that holds the log file and how it is rotated.
+type File struct {
+	Filename   string // The path of the log file
+	MaxSize    int    // The size in megabytes the file is rotated at
+	MaxBackups int    // The number of rotated files to keep
+	Compress   bool   // Whether the rotated files are compressed
+	MaxAge     int    // The number of days to keep the rotated files
+}
+
 // zapLog is a global variable that holds the logger instance
 var zapLog *zap.Logger
 
+// level is a global variable that holds the lowest level logged, which can be changed while the application runs.
+var level = zap.NewAtomicLevel()
+
 // Init is a function that initializes the logger.
-// It takes the environment name as a parameter.
-// Depending on the environment, it sets up the logger for development or production.
-// For development, it uses the built-in development configuration of zap.
-// For production, it creates a new logger with a file and console syncer, and a JSON encoder.
-// If the environment is not recognized, it logs a fatal error.
-// Finally, it names the logger with the name from the configuration.
-func Init(env string) {
-	var w zapcore.WriteSyncer
-	var encoder zapcore.Encoder
+// It takes the environment name and the options of the logger as parameters.
+// The environment sets the defaults of the options: development logs every level in colored console lines to the standard error
+// with the stack traces of the warnings, and production logs from the info level in JSON to the standard output and the log file.
+// It returns an error, and keeps the previous logger, if the environment is not recognized or an option is invalid.
+// Finally, it names the logger with the name from the options.
+func Init(env string, options Options) error {
+	var development bool
 	switch env {
 	case envDev, envDevelopment:
-		config := zap.NewDevelopmentConfig()
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		config.EncoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
-		zapLog, _ = config.Build(zap.AddCallerSkip(1))
+		development = true
+		options = withDefaults(options, "debug", EncodingConsole, OutputStderr)
 	case envProd, envProduction:
-		fileSyncer := zapcore.AddSync(&lumberjack.Logger{
-			Filename:   viper.GetString("log.file"),
-			MaxSize:    viper.GetInt("logger"), // megabytes
-			MaxBackups: viper.GetInt("logger.maxBackups"),
-			Compress:   viper.GetBool("logger.compress"),
-			MaxAge:     viper.GetInt("logger.maxAge"), // days
-		})
-		consoleSyncer := zapcore.AddSync(os.Stdout)
-		w = zapcore.NewMultiWriteSyncer(fileSyncer, consoleSyncer)
-		config := zap.NewProductionConfig()
-		encoder = zapcore.NewJSONEncoder(config.EncoderConfig)
-		core := zapcore.NewCore(encoder, w, config.Level)
-		zapLog = zap.New(core, zap.AddCallerSkip(1))
+		options = withDefaults(options, "info", EncodingJSON, OutputStdout, OutputFile)
 	default:
-		err := errors.New("unknown environment")
-		if err != nil {
-			zapLog.Fatal("Failed to initialize logger", zap.Error(err))
+		return errors.Errorf("unknown environment %q, use dev, development, prod or production", env)
+	}
+
+	lvl, err := zapcore.ParseLevel(options.Level)
+	if err != nil {
+		return err
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	if development {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+	}
+	var encoder zapcore.Encoder
+	switch options.Encoding {
+	case EncodingJSON:
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case EncodingConsole:
+		if development {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return errors.Errorf("unknown log encoding %q, use json or console", options.Encoding)
 	}
-	zapLog = zapLog.Named(viper.GetString("logger.name"))
+
+	syncers := make([]zapcore.WriteSyncer, 0, len(options.Outputs))
+	for _, output := range options.Outputs {
+		switch output {
+		case OutputStdout:
+			syncers = append(syncers, zapcore.Lock(os.Stdout))
+		case OutputStderr:
+			syncers = append(syncers, zapcore.Lock(os.Stderr))
+		case OutputFile:
+			syncers = append(syncers, zapcore.AddSync(&lumberjack.Logger{
+				Filename:   options.File.Filename,
+				MaxSize:    options.File.MaxSize, // megabytes
+				MaxBackups: options.File.MaxBackups,
+				Compress:   options.File.Compress,
+				MaxAge:     options.File.MaxAge, // days
+			}))
+		default:
+			return errors.Errorf("unknown log output %q, use stdout, stderr or file", output)
+		}
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(syncers...), level)
+	if options.Sampling.Enabled {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, options.Sampling.Initial, options.Sampling.Thereafter)
+	}
+	zapOptions := []zap.Option{zap.AddCaller(), zap.AddCallerSkip(1)}
+	if development {
+		zapOptions = append(zapOptions, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
+	} else {
+		zapOptions = append(zapOptions, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+
+	level.SetLevel(lvl)
+	zapLog = zap.New(core, zapOptions...).Named(options.Name)
+	return nil
+}
+
+// withDefaults is a function that returns the options with the empty level, encoding and outputs set to the defaults.
+func withDefaults(options Options, level, encoding string, outputs ...string) Options {
+	if options.Level == "" {
+		options.Level = level
+	}
+	if options.Encoding == "" {
+		options.Encoding = encoding
+	}
+	if len(options.Outputs) == 0 {
+		options.Outputs = outputs
+	}
+	return options
+}
+
+// Level is a function that returns the name of the lowest level logged, like "info".
+func Level() string {
+	return level.Level().String()
+}
+
+// SetLevel is a function that changes the lowest level logged while the application runs.
+// It takes the name of the level: debug, info, warn or error.
+// It returns an error, and keeps the current level, if the name is not a level.
+func SetLevel(name string) error {
+	lvl, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(lvl)
+	return nil
 }
 
 // Sync is a function that syncs the logger.
@@ -77,6 +190,12 @@ func Debug(message string, fields ...zap.Field) {
 	zapLog.Debug(message, fields...)
 }
 
+// Warn is a function that logs a warning level message.
+// It takes a message and a variadic parameter of fields.
+func Warn(message string, fields ...zap.Field) {
+	zapLog.Warn(message, fields...)
+}
+
 // Error is a function that logs an error level message.
 // It takes a message and a variadic parameter of fields.
 func Error(message string, fields ...zap.Field) {
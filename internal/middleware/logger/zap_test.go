@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+)
+
+// TestInit is a test function that checks that the environment sets the default level,
+// and that an unknown environment or option is reported instead of crashing.
+func TestInit(t *testing.T) {
+	require.NoError(t, logger.Init("dev", logger.Options{}))
+	assert.Equal(t, "debug", logger.Level())
+
+	require.NoError(t, logger.Init("production", logger.Options{Outputs: []string{logger.OutputStderr}}))
+	assert.Equal(t, "info", logger.Level())
+
+	assert.ErrorContains(t, logger.Init("staging", logger.Options{}), `unknown environment "staging"`)
+	assert.ErrorContains(t, logger.Init("dev", logger.Options{Encoding: "xml"}), `unknown log encoding "xml"`)
+	assert.ErrorContains(t, logger.Init("dev", logger.Options{Outputs: []string{"syslog"}}), `unknown log output "syslog"`)
+	assert.Error(t, logger.Init("dev", logger.Options{Level: "loud"}))
+	assert.Equal(t, "info", logger.Level(), "a failed initialization keeps the previous logger")
+}
+
+// TestInit_File is a test function that checks that the entries are written to the log file in the configured encoding,
+// under the name of the logger, from the configured level, and that the level can be changed afterwards.
+func TestInit_File(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "shortify.log")
+	require.NoError(t, logger.Init("prod", logger.Options{
+		Name:     "Test",
+		Level:    "warn",
+		Encoding: logger.EncodingJSON,
+		Outputs:  []string{logger.OutputFile},
+		File:     logger.File{Filename: file, MaxSize: 1},
+	}))
+	t.Cleanup(func() { _ = logger.Init("dev", logger.Options{}) })
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+	require.NoError(t, logger.SetLevel("info"))
+	logger.Info("shown after the change")
+	assert.Error(t, logger.SetLevel("loud"))
+	assert.Equal(t, "info", logger.Level())
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "shown", entry["msg"])
+	assert.Equal(t, "Test", entry["logger"])
+	assert.Contains(t, lines[1], "shown after the change")
+}
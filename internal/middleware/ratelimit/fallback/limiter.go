@@ -0,0 +1,76 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"go.uber.org/zap"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ensure that the limiter struct implements the Limiter interface
+var _ def.Limiter = (*limiter)(nil)
+
+// limiter is a struct that implements the Limiter interface with a shared limiter and a local one to fall back on.
+// When the shared limiter fails, the decisions are taken by the local limiter for the retry interval,
+// so requests are neither refused nor let through unlimited while the shared store is down,
+// and do not wait for its timeout one after another.
+type limiter struct {
+	shared def.Limiter   // The limiter shared by the instances, like the Redis one
+	local  def.Limiter   // The limiter of this instance, used while the shared one is down
+	retry  time.Duration // How long the shared limiter is skipped after it failed
+
+	m         sync.Mutex  // The mutex guarding downUntil
+	downUntil time.Time   // The time until which the shared limiter is skipped
+	degraded  atomic.Bool // Whether the shared limiter failed and has not answered since
+}
+
+// NewLimiter is a function that creates a new limiter falling back on a local limiter.
+// It takes the shared limiter, the local limiter, and how long the shared limiter is skipped after it failed.
+// It returns the limiter.
+func NewLimiter(shared, local def.Limiter, retry time.Duration) def.Limiter {
+	return &limiter{
+		shared: shared,
+		local:  local,
+		retry:  retry,
+	}
+}
+
+// Allow is a method on the limiter struct.
+// It asks the shared limiter, unless it failed less than the retry interval ago, and the local limiter otherwise.
+// A failure of the shared limiter is logged when it starts and when it ends, and the decision is taken by the local limiter.
+// It returns the decision and the error of the local limiter.
+func (l *limiter) Allow(ctx context.Context, key string, limit def.Limit) (def.Result, error) {
+	l.m.Lock()
+	down := time.Now().Before(l.downUntil)
+	l.m.Unlock()
+
+	if !down {
+		result, err := l.shared.Allow(ctx, key, limit)
+		if err == nil {
+			if l.degraded.CompareAndSwap(true, false) {
+				logger.Info("Shared rate limiter is available again")
+			}
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			// The request is gone, which says nothing about the shared limiter
+			return result, err
+		}
+		l.m.Lock()
+		l.downUntil = time.Now().Add(l.retry)
+		l.m.Unlock()
+		if l.degraded.CompareAndSwap(false, true) {
+			logger.Warn("Shared rate limiter is unavailable, limiting per instance", zap.Error(err))
+		}
+	}
+	return l.local.Allow(ctx, key, limit)
+}
+
+// Close is a method on the limiter struct. It closes both limiters.
+func (l *limiter) Close() error {
+	return errors.Join(l.shared.Close(), l.local.Close())
+}
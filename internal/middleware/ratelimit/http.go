@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+	"net/http"
+	"slices"
+)
+
+// Middleware is a function that returns an HTTP middleware that limits the requests of every client under the rule.
+// It takes the limiter, how the clients are told apart, the rule, and the paths that are never limited, like the health probes.
+// A refused request gets 429 Too Many Requests with a Retry-After header.
+// If the limiter fails, the request is let through, so an outage of the limiter does not take the server down.
+func Middleware(limiter Limiter, policy Policy, rule Rule, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(exempt, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			id := Identity{
+				Subject: auth.Owner(r.Context()),
+				Tenant:  r.Header.Get(TenantKey),
+				IP:      policy.ClientIP(r.Header.Get(ForwardedForKey), r.RemoteAddr),
+			}
+			result, err := limiter.Allow(r.Context(), policy.Key(rule, id), rule.Current())
+			if err != nil {
+				logger.Error("Failed to check the rate limit", zap.String("class", rule.Class), zap.Error(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !result.Allowed {
+				metrics.RateLimited.WithLabelValues(rule.Class).Inc()
+				w.Header().Set(RetryAfterKey, RetryAfterSeconds(result.RetryAfter))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package local
+
+import (
+	"context"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the interval between two removals of the idle buckets.
+const sweepInterval = time.Minute
+
+// Ensure that the limiter struct implements the Limiter interface
+var _ def.Limiter = (*limiter)(nil)
+
+// bucket is a struct that holds the state of a token bucket.
+type bucket struct {
+	tokens  float64       // The tokens left after the last request
+	updated time.Time     // The time of the last request
+	refill  time.Duration // How long the bucket takes to fill up from empty
+}
+
+// limiter is a struct that implements the Limiter interface with token buckets in the memory of the process.
+// Every instance of the application counts on its own, so the limits apply per instance.
+type limiter struct {
+	m         sync.Mutex         // The mutex guarding the buckets
+	buckets   map[string]*bucket // The buckets by key
+	lastSweep time.Time          // The time the idle buckets were removed last
+}
+
+// NewLimiter is a function that creates a new limiter in the memory of the process.
+// It returns the limiter.
+func NewLimiter() def.Limiter {
+	return &limiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow is a method on the limiter struct.
+// It refills the bucket of the key for the time since its last request, takes a token if there is one,
+// and otherwise returns how long it takes until there is one.
+// It never returns an error.
+func (l *limiter) Allow(_ context.Context, key string, limit def.Limit) (def.Result, error) {
+	if !limit.Enabled() {
+		return def.Result{Allowed: true}, nil
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	burst := float64(limit.Burst)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+	b.refill = time.Duration(burst / limit.Rate * float64(time.Second))
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return def.Result{Allowed: true}, nil
+	}
+	return def.Result{RetryAfter: time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))}, nil
+}
+
+// sweep is a method on the limiter struct.
+// Once every sweep interval, it removes the buckets that have been idle long enough to be full again,
+// since a missing bucket is created full, so the memory does not grow with every client ever seen.
+// It must be called with the mutex held.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= b.refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Close is a method on the limiter struct. It holds no connection and returns nil.
+func (l *limiter) Close() error {
+	return nil
+}
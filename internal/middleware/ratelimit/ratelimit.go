@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Limit is a struct that describes a token bucket: it holds up to Burst tokens and gains Rate tokens every second.
+// Every request takes a token, so a client can send Burst requests at once and Rate requests per second after that.
+type Limit struct {
+	Rate  float64 // The number of tokens added every second
+	Burst int     // The maximum number of tokens
+}
+
+// Enabled is a method on the Limit struct. It reports whether the limit lets any request through, so it is worth enforcing.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Result is a struct that holds the decision of a limiter on a request.
+type Result struct {
+	Allowed    bool          // Whether the request may proceed
+	RetryAfter time.Duration // How long to wait until a token is available, when the request is refused
+}
+
+// Limiter is an interface that decides whether a request may proceed under the token bucket of its key.
+type Limiter interface {
+	// Allow is a method that takes a token from the bucket of the key, created full with the limit if there is none.
+	// The limit is passed on every call, so a changed limit applies to the buckets that already exist.
+	// It returns the decision, and an error if the state of the bucket cannot be reached.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+	// Close is a method that releases the connections of the limiter.
+	Close() error
+}
+
+// TenantKey is the gRPC metadata key and, case-insensitively, the HTTP header of the tenant of a request.
+// It is meant to be set by a trusted gateway in front of the application.
+const TenantKey = "x-tenant-id"
+
+// ForwardedForKey is the gRPC metadata key and, case-insensitively, the HTTP header of the addresses a request was forwarded for.
+const ForwardedForKey = "x-forwarded-for"
+
+// RetryAfterKey is the gRPC metadata key and, case-insensitively, the HTTP header telling a refused client how many seconds to wait.
+const RetryAfterKey = "retry-after"
+
+// KeyBy is a type for what the requests are counted by.
+type KeyBy string
+
+// Constants for what the requests are counted by
+const (
+	KeyByAPIKey KeyBy = "apiKey" // The authenticated caller, the API key or the subject of the bearer token
+	KeyByIP     KeyBy = "ip"     // The address of the client
+	KeyByTenant KeyBy = "tenant" // The tenant in the x-tenant-id metadata or header
+)
+
+// Valid is a method on the KeyBy type. It reports whether the value is one of the known ones.
+func (k KeyBy) Valid() bool {
+	return k == KeyByAPIKey || k == KeyByIP || k == KeyByTenant
+}
+
+// Policy is a struct that holds how the clients of a server are told apart.
+type Policy struct {
+	KeyBy             KeyBy // What the requests are counted by
+	TrustForwardedFor bool  // Whether the address of the client is read from the x-forwarded-for metadata or header of a proxy
+}
+
+// Rule is a struct that holds the class of the requests that share a bucket per client, like create or resolve, and its limit.
+// The limit is either fixed, or read from Limits on every request, so it can be changed while the server runs.
+type Rule struct {
+	Class  string  // The name of the class, part of the key of the bucket and of the metrics
+	Limit  Limit   // The limit of every client in the class, used if Limits is nil
+	Limits *Limits // The limits the limit of the class is read from on every request, nil for the fixed Limit
+}
+
+// Current is a method on the Rule struct. It returns the limit of the class now: the one in Limits if it is set, and Limit otherwise.
+func (r Rule) Current() Limit {
+	if r.Limits != nil {
+		return r.Limits.Of(r.Class)
+	}
+	return r.Limit
+}
+
+// Limits is a struct that holds the limit of every class of requests, and can be replaced while the requests are served,
+// like when the configuration is reloaded. A request is checked against the limits that are current when it arrives.
+type Limits struct {
+	limits atomic.Pointer[map[string]Limit] // The limits by class
+}
+
+// NewLimits is a function that returns the limits by class.
+func NewLimits(limits map[string]Limit) *Limits {
+	l := &Limits{}
+	l.Set(limits)
+	return l
+}
+
+// Of is a method on the Limits struct. It returns the limit of the class, or a zero limit, which is not enforced, if it has none.
+func (l *Limits) Of(class string) Limit {
+	return (*l.limits.Load())[class]
+}
+
+// Set is a method on the Limits struct. It replaces every limit at once.
+func (l *Limits) Set(limits map[string]Limit) {
+	l.limits.Store(&limits)
+}
+
+// Identity is a struct that holds what a client can be told apart by.
+type Identity struct {
+	Subject string // The authenticated caller, empty for an anonymous one
+	Tenant  string // The tenant of the request, empty if there is none
+	IP      string // The address of the client
+}
+
+// Key is a method on the Policy struct.
+// It returns the key of the bucket of the client in the class of the rule.
+// The requests without a caller or without a tenant, when they are counted by one of these, are counted by address instead.
+func (p Policy) Key(rule Rule, id Identity) string {
+	switch {
+	case p.KeyBy == KeyByAPIKey && id.Subject != "":
+		return rule.Class + ":sub:" + id.Subject
+	case p.KeyBy == KeyByTenant && id.Tenant != "":
+		return rule.Class + ":tenant:" + id.Tenant
+	default:
+		return rule.Class + ":ip:" + id.IP
+	}
+}
+
+// ClientIP is a method on the Policy struct.
+// It returns the address of the client: the first address of the x-forwarded-for value if the proxies are trusted and there is one,
+// and the host of the remote address of the connection otherwise.
+func (p Policy) ClientIP(forwardedFor, remoteAddr string) string {
+	if p.TrustForwardedFor && forwardedFor != "" {
+		first, _, _ := strings.Cut(forwardedFor, ",")
+		if first = strings.TrimSpace(first); first != "" {
+			return first
+		}
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// RetryAfterSeconds is a function that returns the value of the retry-after metadata or header for a wait:
+// the number of seconds rounded up, at least one.
+func RetryAfterSeconds(wait time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(wait.Seconds()))))
+}
@@ -0,0 +1,179 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit/fallback"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit/local"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit/redis"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// newRedisLimiter is a function that returns a Redis limiter on an in-memory Redis server, and the server.
+func newRedisLimiter(t *testing.T) (ratelimit.Limiter, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	limiter := redis.NewLimiter(goredis.NewClient(&goredis.Options{Addr: server.Addr()}), "test:", time.Second)
+	t.Cleanup(func() { _ = limiter.Close() })
+	return limiter, server
+}
+
+// TestLimiters is a test function that checks that the local and the Redis limiters let a burst through,
+// refuse the next request with the time until a token is available, refill over time, and count every key apart.
+func TestLimiters(t *testing.T) {
+	limiters := map[string]func(t *testing.T) ratelimit.Limiter{
+		"local": func(*testing.T) ratelimit.Limiter { return local.NewLimiter() },
+		"redis": func(t *testing.T) ratelimit.Limiter {
+			limiter, _ := newRedisLimiter(t)
+			return limiter
+		},
+	}
+	for name, newLimiter := range limiters {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			limiter := newLimiter(t)
+			limit := ratelimit.Limit{Rate: 10, Burst: 3}
+
+			for i := 0; i < 3; i++ {
+				result, err := limiter.Allow(ctx, "a", limit)
+				require.NoError(t, err)
+				assert.True(t, result.Allowed, "request %d of the burst", i)
+			}
+			result, err := limiter.Allow(ctx, "a", limit)
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Greater(t, result.RetryAfter, time.Duration(0))
+			assert.LessOrEqual(t, result.RetryAfter, 100*time.Millisecond)
+
+			result, err = limiter.Allow(ctx, "b", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed, "another key has a bucket of its own")
+
+			time.Sleep(150 * time.Millisecond)
+			result, err = limiter.Allow(ctx, "a", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed, "the bucket refills over time")
+
+			for i := 0; i < 10; i++ {
+				result, err = limiter.Allow(ctx, "a", ratelimit.Limit{})
+				require.NoError(t, err)
+				assert.True(t, result.Allowed, "a disabled limit lets everything through")
+			}
+		})
+	}
+}
+
+// TestFallback is a test function that checks that the fallback limiter limits per instance while Redis is down
+// and goes back to Redis once the retry interval is over.
+func TestFallback(t *testing.T) {
+	ctx := context.Background()
+	shared, server := newRedisLimiter(t)
+	limiter := fallback.NewLimiter(shared, local.NewLimiter(), 50*time.Millisecond)
+	limit := ratelimit.Limit{Rate: 1, Burst: 1}
+
+	result, err := limiter.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.True(t, server.Exists("test:a"), "the bucket is stored in Redis")
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	result, err = limiter.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "the local bucket is full")
+	result, err = limiter.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "the local bucket limits while Redis is down")
+
+	server.SetError("")
+	time.Sleep(60 * time.Millisecond)
+	result, err = limiter.Allow(ctx, "b", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.True(t, server.Exists("test:b"), "Redis is used again after the retry interval")
+}
+
+// TestMiddleware is a test function that checks that the HTTP middleware refuses a client over its limit with a Retry-After header,
+// counts the clients by address, and never limits the exempt paths.
+func TestMiddleware(t *testing.T) {
+	policy := ratelimit.Policy{KeyBy: ratelimit.KeyByIP, TrustForwardedFor: true}
+	rule := ratelimit.Rule{Class: "resolve", Limit: ratelimit.Limit{Rate: 1, Burst: 1}}
+	handler := ratelimit.Middleware(local.NewLimiter(), policy, rule, "/healthz")(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(path, forwardedFor string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		if forwardedFor != "" {
+			r.Header.Set(ratelimit.ForwardedForKey, forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve("/abc", "").Code)
+	refused := serve("/abc", "")
+	assert.Equal(t, http.StatusTooManyRequests, refused.Code)
+	assert.Equal(t, "1", refused.Header().Get(ratelimit.RetryAfterKey))
+
+	assert.Equal(t, http.StatusNoContent, serve("/abc", "192.0.2.7, 10.0.0.1").Code, "a forwarded client has a bucket of its own")
+	assert.Equal(t, http.StatusNoContent, serve("/healthz", "").Code)
+}
+
+// TestRuleLimits is a test function that checks that a rule reading its limit from Limits applies a replaced limit to the next request,
+// including to the buckets that already exist.
+func TestRuleLimits(t *testing.T) {
+	limits := ratelimit.NewLimits(map[string]ratelimit.Limit{"resolve": {Rate: 1, Burst: 1}})
+	rule := ratelimit.Rule{Class: "resolve", Limits: limits}
+	handler := ratelimit.Middleware(local.NewLimiter(), ratelimit.Policy{KeyBy: ratelimit.KeyByIP}, rule)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func() int {
+		r := httptest.NewRequest(http.MethodGet, "/abc", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve())
+	assert.Equal(t, http.StatusTooManyRequests, serve())
+
+	limits.Set(map[string]ratelimit.Limit{})
+	assert.Equal(t, ratelimit.Limit{}, rule.Current(), "a class without a limit is not limited")
+	assert.Equal(t, http.StatusNoContent, serve())
+
+	limits.Set(map[string]ratelimit.Limit{"resolve": {Rate: 1, Burst: 1}})
+	assert.Equal(t, http.StatusTooManyRequests, serve())
+	assert.Equal(t, ratelimit.Limit{Rate: 2}, ratelimit.Rule{Limit: ratelimit.Limit{Rate: 2}}.Current(), "a rule without Limits has a fixed limit")
+}
+
+// TestPolicyKey is a test function that checks that the requests are counted by the configured identity,
+// and by address when the request does not have it.
+func TestPolicyKey(t *testing.T) {
+	rule := ratelimit.Rule{Class: "create"}
+	id := ratelimit.Identity{Subject: "key1", Tenant: "acme", IP: "192.0.2.7"}
+
+	assert.Equal(t, "create:sub:key1", ratelimit.Policy{KeyBy: ratelimit.KeyByAPIKey}.Key(rule, id))
+	assert.Equal(t, "create:tenant:acme", ratelimit.Policy{KeyBy: ratelimit.KeyByTenant}.Key(rule, id))
+	assert.Equal(t, "create:ip:192.0.2.7", ratelimit.Policy{KeyBy: ratelimit.KeyByIP}.Key(rule, id))
+	assert.Equal(t, "create:ip:192.0.2.7", ratelimit.Policy{KeyBy: ratelimit.KeyByAPIKey}.Key(rule, ratelimit.Identity{IP: "192.0.2.7"}))
+
+	assert.Equal(t, "10.0.0.1", ratelimit.Policy{}.ClientIP("192.0.2.7", "10.0.0.1:1234"), "the proxies are not trusted")
+	assert.Equal(t, "192.0.2.7", ratelimit.Policy{TrustForwardedFor: true}.ClientIP(" 192.0.2.7 , 10.0.0.1", "10.0.0.1:1234"))
+}
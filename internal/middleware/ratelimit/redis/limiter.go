@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	def "github.com/t1ltxz-gxd/shortify/internal/middleware/ratelimit"
+	"strconv"
+	"time"
+)
+
+// script is the Lua script that takes a token from a bucket stored in a Redis hash, atomically.
+// It reads the time of the Redis server, so the instances of the application share one clock,
+// and lets the bucket expire once it would be full again, since a missing bucket is created full.
+// It returns whether the request is allowed and, when it is not, the seconds until a token is available, as a string
+// because Redis truncates the numbers returned by a script to integers.
+var script = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) + tonumber(clock[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = (1 - tokens) / rate
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(retry)}
+`)
+
+// Ensure that the limiter struct implements the Limiter interface
+var _ def.Limiter = (*limiter)(nil)
+
+// limiter is a struct that implements the Limiter interface with token buckets stored in Redis,
+// so the limits apply to all the instances of the application together.
+type limiter struct {
+	client  *redis.Client // The Redis client
+	prefix  string        // The prefix of the keys of the buckets
+	timeout time.Duration // The timeout of a single decision
+}
+
+// NewLimiter is a function that creates a new limiter storing its buckets in Redis.
+// It takes the Redis client, which the limiter closes, the prefix of the keys of the buckets,
+// and the timeout of a single decision, zero for none.
+// It returns the limiter.
+func NewLimiter(client *redis.Client, prefix string, timeout time.Duration) def.Limiter {
+	return &limiter{
+		client:  client,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+}
+
+// Allow is a method on the limiter struct.
+// It runs the token bucket script on the bucket of the key.
+// It returns the decision, and an error if Redis does not answer in time.
+func (l *limiter) Allow(ctx context.Context, key string, limit def.Limit) (def.Result, error) {
+	if !limit.Enabled() {
+		return def.Result{Allowed: true}, nil
+	}
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	reply, err := script.Run(ctx, l.client, []string{l.prefix + key},
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64), limit.Burst).Slice()
+	if err != nil {
+		return def.Result{}, err
+	}
+	if len(reply) != 2 {
+		return def.Result{}, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	allowed, _ := reply[0].(int64)
+	retry, _ := reply[1].(string)
+	seconds, err := strconv.ParseFloat(retry, 64)
+	if err != nil {
+		return def.Result{}, err
+	}
+	return def.Result{
+		Allowed:    allowed == 1,
+		RetryAfter: time.Duration(seconds * float64(time.Second)),
+	}, nil
+}
+
+// Close is a method on the limiter struct. It closes the Redis client.
+func (l *limiter) Close() error {
+	return l.client.Close()
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// APIKey is a struct that represents an API key of the application.
+// The key itself is only shown once, when it is created; the application keeps its SHA-256 hash.
+// ID is a string that identifies the key, it is recorded as the owner of the URLs created with the key.
+// Name is a string that describes who the key was issued to.
+// Hash is a string that holds the hex-encoded SHA-256 hash of the key.
+// CreatedAt is a time.Time value that holds the time when the key was created.
+// RevokedAt is a pointer to a time.Time value that holds the time when the key was revoked.
+// If the key is active, RevokedAt is nil.
+type APIKey struct {
+	ID        string     // The identifier of the key
+	Name      string     // The name of the holder of the key
+	Hash      string     // The SHA-256 hash of the key
+	CreatedAt time.Time  // The time when the key was created
+	RevokedAt *time.Time // The time when the key was revoked, nil while it is active
+}
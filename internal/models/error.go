@@ -4,6 +4,34 @@ import "errors"
 
 // ErrorInvalidURL is a global variable that holds an error.
 // This error is returned when an invalid URL is encountered in the application.
+// ErrorCacheMiss is returned by cache implementations when the requested entry is not cached.
+// ErrorURLNotFound is returned by storage implementations when there is no URL to change for a hash.
+// ErrorURLExists is returned by storage implementations when a URL is created with a hash that is already taken.
+// ErrorSearchUnsupported is returned when the configured storage cannot search the URLs.
+// ErrorInvalidQuery is returned when a search query is too short or a page token is malformed.
+// ErrorAPIKeyNotFound is returned by storage implementations when there is no active API key with an ID,
+// and by the API key service when a key does not authenticate.
+// ErrorInvalidAPIKeyName is returned when an API key is created without a name.
+// ErrorPermissionDenied is returned when the caller was not granted the scope an operation needs.
+// ErrorPurgeUnsupported is returned when the configured cache cannot purge its entries by prefix.
+// ErrorDisableUnsupported is returned when the configured storage cannot disable the URLs.
+// ErrorInvalidPurge is returned when a cache purge names neither a hash nor a prefix, or both.
+// ErrorInvalidLogLevel is returned when the log level is changed to a name that is not a level.
 var (
-	ErrorInvalidURL = errors.New("invalid URL") // Error message for invalid URL
+	ErrorInvalidURL  = errors.New("invalid URL")        // Error message for invalid URL
+	ErrorCacheMiss   = errors.New("cache miss")         // Error message for a missing cache entry
+	ErrorURLNotFound = errors.New("URL not found")      // Error message for a missing URL
+	ErrorURLExists   = errors.New("URL already exists") // Error message for a hash that is already taken
+
+	ErrorSearchUnsupported = errors.New("search is not supported by the storage") // Error message for a storage without search
+	ErrorInvalidQuery      = errors.New("invalid search query")                   // Error message for an invalid search query
+
+	ErrorAPIKeyNotFound    = errors.New("API key not found")        // Error message for a missing or revoked API key
+	ErrorInvalidAPIKeyName = errors.New("API key name is required") // Error message for an API key without a name
+	ErrorPermissionDenied  = errors.New("permission denied")        // Error message for a caller without the needed scope
+
+	ErrorPurgeUnsupported   = errors.New("purging by prefix is not supported by the cache") // Error message for a cache without prefix purges
+	ErrorDisableUnsupported = errors.New("disabling URLs is not supported by the storage")  // Error message for a storage without disabled URLs
+	ErrorInvalidPurge       = errors.New("a cache purge needs either a hash or a prefix")   // Error message for a purge without a single target
+	ErrorInvalidLogLevel    = errors.New("log level must be debug, info, warn or error")    // Error message for an unknown log level
 )
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Stats is a struct that represents the runtime statistics of an instance of the application.
+// StartedAt is a time.Time value that holds the time when the instance started.
+// GoVersion is a string that holds the version of Go the instance was built with.
+// Goroutines, HeapBytes and GCCycles hold the figures of the Go runtime: the number of goroutines,
+// the bytes of allocated heap objects, and the number of completed garbage collection cycles.
+// Ready is a bool value that holds whether the instance is ready to serve.
+// CacheState is a string that holds the state of the circuit breaker around the shared cache.
+type Stats struct {
+	StartedAt  time.Time // The time when the instance started
+	GoVersion  string    // The version of Go the instance was built with
+	Goroutines int       // The number of goroutines
+	HeapBytes  uint64    // The bytes of allocated heap objects
+	GCCycles   uint32    // The number of completed garbage collection cycles
+	Ready      bool      // Whether the instance is ready to serve
+	CacheState string    // The state of the circuit breaker around the shared cache
+}
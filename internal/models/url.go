@@ -9,9 +9,15 @@ import "time"
 // AddedAt is a time.Time value that holds the time when the URL was added to the application.
 // UpdatedAt is a pointer to a time.Time value that holds the time when the URL was last updated in the application.
 // If the URL has not been updated, UpdatedAt is nil.
+// ExpiresAt is a pointer to a time.Time value that holds the time after which the URL no longer resolves.
+// If the URL never expires, ExpiresAt is nil.
+// Owner is a string that holds the subject of the caller that created the URL, empty if it was created anonymously.
+// The subject is key:<id> for an API key and jwt:<iss>|<sub> for a bearer token.
 type URL struct {
 	Original  string     // The original URL
 	Hash      string     // The hashed version of the original URL
 	AddedAt   time.Time  // The time when the URL was added
 	UpdatedAt *time.Time // The time when the URL was last updated, nil if not updated
+	ExpiresAt *time.Time // The time when the URL expires, nil if it never expires
+	Owner     string     // The subject of the caller that created the URL, empty if created anonymously
 }
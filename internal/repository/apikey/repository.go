@@ -0,0 +1,114 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	def "github.com/t1ltxz-gxd/shortify/internal/repository"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Ensure that the repository struct implements the APIKeyRepository interface
+var _ def.APIKeyRepository = (*repository)(nil)
+
+// repository is a struct that represents a repository for API keys.
+// It has one field: db.
+// db is the database that stores the API keys next to the URLs.
+// The keys are not cached, so a revoked key stops authenticating on every instance at once.
+type repository struct {
+	db database.APIKeyDatabase // The database of the API keys
+}
+
+// NewRepository is a function that creates a new repository for API keys.
+// It takes the database of the API keys as a parameter.
+// It returns an instance of the APIKeyRepository interface.
+func NewRepository(db database.APIKeyDatabase) def.APIKeyRepository {
+	return &repository{
+		db: db, // Set the database of the API keys
+	}
+}
+
+// Create is a method of the repository struct that stores a new API key.
+// It takes a context and the key with its ID, name, hash and creation time.
+// The duration of the insert is recorded in the metrics package.
+// It returns an error if the creation fails.
+func (r *repository) Create(ctx context.Context, key *models.APIKey) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.CreateAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	started := time.Now()
+	err = r.db.CreateAPIKey(ctx, key)
+	metrics.ObserveDatabase("create_api_key", started, err)
+	if err != nil {
+		logger.Error("Failed to insert API key into the database", zap.String("id", key.ID), zap.Error(err))
+	}
+	return err
+}
+
+// GetByHash is a method of the repository struct that retrieves an active API key using the hash of the key.
+// It takes a context and the hex-encoded SHA-256 hash of the key.
+// The duration of the query is recorded in the metrics package.
+// It returns the key, nil for both the key and the error if there is no active key with the hash,
+// and an error if the retrieval fails.
+func (r *repository) GetByHash(ctx context.Context, hash string) (_ *models.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "repository.GetAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	started := time.Now()
+	key, err := r.db.GetAPIKey(ctx, hash)
+	metrics.ObserveDatabase("get_api_key", started, err)
+	if err != nil {
+		logger.Error("Failed to fetch API key from the database", zap.Error(err))
+		return nil, err
+	}
+	return key, nil
+}
+
+// List is a method of the repository struct that retrieves every API key, the revoked ones included, the newest first.
+// It takes a context.
+// The duration of the query is recorded in the metrics package.
+// It returns the keys and an error if the retrieval fails.
+func (r *repository) List(ctx context.Context) (_ []*models.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "repository.ListAPIKeys")
+	defer func() { tracing.End(span, err) }()
+
+	started := time.Now()
+	keys, err := r.db.ListAPIKeys(ctx)
+	metrics.ObserveDatabase("list_api_keys", started, err)
+	if err != nil {
+		logger.Error("Failed to list API keys from the database", zap.Error(err))
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Revoke is a method of the repository struct that revokes an API key, so it no longer authenticates.
+// It takes a context and the ID of the key.
+// The duration of the update is recorded in the metrics package.
+// It returns models.ErrorAPIKeyNotFound if there is no active key with the ID, and an error if the revocation fails.
+func (r *repository) Revoke(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.RevokeAPIKey")
+	defer func() { tracing.End(span, err, models.ErrorAPIKeyNotFound) }()
+
+	started := time.Now()
+	err = r.db.RevokeAPIKey(ctx, id)
+	metrics.ObserveDatabase("revoke_api_key", started, queryError(err))
+	if err != nil && !errors.Is(err, models.ErrorAPIKeyNotFound) {
+		logger.Error("Failed to revoke API key in the database", zap.String("id", id), zap.Error(err))
+	}
+	return err
+}
+
+// queryError is a function that returns the error of a database operation as recorded in the metrics.
+// A missing key is an answer of the database rather than a failure, so it returns nil for it.
+func queryError(err error) error {
+	if errors.Is(err, models.ErrorAPIKeyNotFound) {
+		return nil
+	}
+	return err
+}
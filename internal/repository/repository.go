@@ -3,18 +3,21 @@ package repository
 import (
 	"context"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"time"
 )
 
 // URLRepository is an interface that represents a repository for URLs.
-// It has two methods: Create and Get.
+// It has seven methods: Create, Get, Delete, Search, SetDisabled, PurgeCache and PurgeCachePrefix.
 type URLRepository interface {
 	// Create is a method that creates a new URL in the repository.
 	// It takes a context, a hash string, and a URL string as parameters.
 	// The context is used for request-scoped data, cancellation signals, and deadlines.
 	// The hash string is the hashed version of the URL.
 	// The URL string is the original URL.
+	// The owner is the subject of the caller that creates the URL, empty for an anonymous URL.
+	// The expiry is the time when the URL stops resolving, or nil if it never expires.
 	// It returns an error if the creation fails.
-	Create(ctx context.Context, hash string, url string) error
+	Create(ctx context.Context, hash, url, owner string, expiresAt *time.Time) error
 
 	// Get is a method that retrieves a URL from the repository.
 	// It takes a context and a hash string as parameters.
@@ -24,4 +27,60 @@ type URLRepository interface {
 	// If the retrieval is successful, the error is nil.
 	// If the retrieval fails, the URL model is nil and the error contains the failure reason.
 	Get(ctx context.Context, hash string) (*models.URL, error)
+
+	// Delete is a method that removes a URL from the repository.
+	// It takes a context and a hash string as parameters.
+	// The context is used for request-scoped data, cancellation signals, and deadlines.
+	// The hash string is the hashed version of the URL.
+	// The owner is the subject of the caller the URL must belong to.
+	// It returns models.ErrorURLNotFound if there is no URL with the hash owned by the owner,
+	// and an error if the deletion fails.
+	Delete(ctx context.Context, hash, owner string) error
+
+	// Search is a method that finds the live URLs of an owner whose original URL or host contains a query.
+	// It takes a context, the owner, the query, and the number of URLs to return and to skip.
+	// It returns the matching URLs, the most relevant first, and an error.
+	// If the storage cannot search, the error is models.ErrorSearchUnsupported.
+	Search(ctx context.Context, owner, query string, limit, offset int) ([]*models.URL, error)
+
+	// SetDisabled is a method that disables a URL, so it no longer resolves, or enables it again.
+	// It takes a context, the hash of the URL, and whether to disable it.
+	// It returns models.ErrorURLNotFound if there is no URL with the hash, and an error if the change fails.
+	// If the storage cannot disable URLs, the error is models.ErrorDisableUnsupported.
+	SetDisabled(ctx context.Context, hash string, disabled bool) error
+
+	// PurgeCache is a method that evicts a URL from the caches of every instance, so the next lookup reads the storage.
+	// It takes a context and the hash of the URL.
+	// It returns an error if the URL cannot be evicted.
+	PurgeCache(ctx context.Context, hash string) error
+
+	// PurgeCachePrefix is a method that evicts the URLs whose hash starts with a prefix from the cache.
+	// It takes a context and the prefix.
+	// It returns the number of URLs evicted from the shared cache, and an error.
+	// If the cache cannot purge by prefix, the error is models.ErrorPurgeUnsupported.
+	PurgeCachePrefix(ctx context.Context, prefix string) (int64, error)
+}
+
+// APIKeyRepository is an interface that represents a repository for API keys.
+// It has four methods: Create, GetByHash, List and Revoke.
+type APIKeyRepository interface {
+	// Create is a method that stores a new API key.
+	// It takes a context and the key with its ID, name, hash and creation time.
+	// It returns an error if the creation fails.
+	Create(ctx context.Context, key *models.APIKey) error
+
+	// GetByHash is a method that retrieves an active API key using the hash of the key.
+	// It takes a context and the hex-encoded SHA-256 hash of the key.
+	// It returns the key, nil for both the key and the error if there is no active key with the hash, and an error.
+	GetByHash(ctx context.Context, hash string) (*models.APIKey, error)
+
+	// List is a method that retrieves every API key, the revoked ones included, the newest first.
+	// It takes a context.
+	// It returns the keys and an error.
+	List(ctx context.Context) ([]*models.APIKey, error)
+
+	// Revoke is a method that revokes an API key, so it no longer authenticates.
+	// It takes a context and the ID of the key.
+	// It returns models.ErrorAPIKeyNotFound if there is no active key with the ID, and an error if the revocation fails.
+	Revoke(ctx context.Context, id string) error
 }
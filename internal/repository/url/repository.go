@@ -3,14 +3,15 @@ package url
 import (
 	"context"
 	"database/sql"
-	"github.com/go-redis/redis"
 	"github.com/pkg/errors"
-	"github.com/spf13/viper"
 	"github.com/t1ltxz-gxd/shortify/internal/database"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
 	def "github.com/t1ltxz-gxd/shortify/internal/repository"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
 	"go.uber.org/zap"
 	"sync"
 	"time"
@@ -20,25 +21,29 @@ import (
 var _ def.URLRepository = (*repository)(nil)
 
 // repository is a struct that represents a repository for URLs.
-// It has three fields: db, cache, and m.
+// It has five fields: db, cache, bus, cacheTTL, and m.
 // db is a pointer to a sqlx.DB instance that represents the database connection.
 // cache is a pointer to a redis.Client instance that represents the Redis cache.
+// bus is the invalidation bus that tells the other instances to evict changed links from their caches.
+// cacheTTL returns how long a resolved URL is kept in the cache; it is called on every write to the cache, so a reloaded value applies at once.
 // m is a sync.RWMutex instance that is used for read/write locking to ensure thread safety.
 type repository struct {
-	db    database.URLDatabase // The database connection
-	cache cache.URLCache       // The cache
-	m     sync.RWMutex         // The read/write mutex
+	db       database.URLDatabase // The database connection
+	cache    cache.URLCache       // The cache
+	bus      invalidation.Bus     // The cache invalidation bus
+	cacheTTL func() time.Duration // The lifetime of a cache entry
+	m        sync.RWMutex         // The read/write mutex
 }
 
 // NewRepository is a function that creates a new repository.
-// It takes a pointer to a sqlx.DB instance and a pointer to a redis.Client instance as parameters.
-// The sqlx.DB instance represents the database connection.
-// The redis.Client instance represents the Redis cache.
+// It takes the database, the cache, the cache invalidation bus, and a function returning the lifetime of a cache entry as parameters.
 // It returns a pointer to a repository instance.
-func NewRepository(db database.URLDatabase, cache cache.URLCache) def.URLRepository {
+func NewRepository(db database.URLDatabase, cache cache.URLCache, bus invalidation.Bus, cacheTTL func() time.Duration) def.URLRepository {
 	return &repository{
-		db:    db,    // Set the database connection
-		cache: cache, // Set the Redis cache
+		db:       db,       // Set the database connection
+		cache:    cache,    // Set the Redis cache
+		bus:      bus,      // Set the cache invalidation bus
+		cacheTTL: cacheTTL, // Set the lifetime of a cache entry
 	}
 }
 
@@ -47,14 +52,22 @@ func NewRepository(db database.URLDatabase, cache cache.URLCache) def.URLReposit
 // The context is used for request-scoped data, cancellation signals, and deadlines.
 // The hash string is the hashed version of the URL.
 // The URL string is the original URL.
+// The owner is the subject of the caller that creates the URL, empty for an anonymous URL.
+// The expiry is the time when the URL stops resolving, or nil if it never expires.
 // It locks the mutex before creating the URL and unlocks it after the creation.
+// The duration of the insert is recorded in the metrics package.
 // It returns an error if the creation fails.
-func (r *repository) Create(_ context.Context, hash string, url string) error {
+func (r *repository) Create(ctx context.Context, hash, url, owner string, expiresAt *time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.Create", tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err, models.ErrorURLExists) }()
+
 	r.m.Lock()         // Lock the mutex
 	defer r.m.Unlock() // Unlock the mutex after the creation
 
 	// The SQL query to insert the URL into the database
-	err := r.db.Create(context.Background(), url, hash)
+	started := time.Now()
+	err = r.db.Create(ctx, url, hash, owner, expiresAt)
+	metrics.ObserveDatabase("create", started, queryError(err))
 	if err != nil {
 		logger.Error("Failed to insert URL into the database", zap.Error(err)) // Log the error if the creation fails
 	}
@@ -66,52 +79,222 @@ func (r *repository) Create(_ context.Context, hash string, url string) error {
 // The context is used for request-scoped data, cancellation signals, and deadlines.
 // The hash string is the hashed version of the URL.
 // It locks the mutex for reading before retrieving the URL and unlocks it after the retrieval.
-// It first tries to get the URL from the Redis cache.
-// If the URL is not in the cache, it gets it from the Postgres database.
-// If the URL is in the database, it saves it in the Redis cache and returns it.
+// It first tries to get the URL from the cache.
+// If the URL is not in the cache, or the cache is unavailable, it gets it from the Postgres database.
+// A cache failure is logged and never fails the request, so lookups degrade to the database while the cache is down.
+// The result of the cache lookup and the duration of the database query are recorded in the metrics package.
+// If the URL is in the database, it tries to save it in the cache and returns it.
 // If the URL is not in the database, it returns nil.
-// If the retrieval from the cache or the database fails, it logs an error and returns the error.
-func (r *repository) Get(_ context.Context, hash string) (*models.URL, error) {
+// If the retrieval from the database fails, it logs an error and returns the error.
+func (r *repository) Get(ctx context.Context, hash string) (_ *models.URL, err error) {
+	ctx, span := tracing.Start(ctx, "repository.Get", tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err) }()
+
 	r.m.RLock()         // Lock the mutex for reading
 	defer r.m.RUnlock() // Unlock the mutex after the retrieval
 
-	// Try to get the URL from the Redis cache
+	// Try to get the URL from the cache
 	logger.Debug("Fetching URL from cache", zap.String("hash", hash))
-	val, err := r.cache.Get(context.Background(), hash)
-	if errors.Is(err, redis.Nil) {
-		// If the URL is not in the cache, get it from the Postgres database
-		logger.Debug("Fetching URL from database", zap.String("hash", hash))
-		url, err := r.db.Get(context.Background(), hash)
-		//err := r.db.Get(&url, "SELECT * FROM urls WHERE hash = $1", hash)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				// If the URL is not in the database, return nil
-				logger.Error("URL is not found in the database", zap.String("hash", hash))
-				return nil, nil
-			}
-			logger.Error("Failed to fetch URL from the database", zap.String("hash", hash), zap.Error(err))
-			return nil, err
+	val, err := r.cache.Get(ctx, hash)
+	switch {
+	case err == nil:
+		metrics.CacheRequests.WithLabelValues(metrics.CacheHit).Inc()
+	case errors.Is(err, models.ErrorCacheMiss):
+		metrics.CacheRequests.WithLabelValues(metrics.CacheMiss).Inc()
+	default:
+		metrics.CacheRequests.WithLabelValues(metrics.CacheError).Inc()
+	}
+	if err == nil {
+		// If the URL is in the cache, return it
+		logger.Debug("URL is fetched from the cache",
+			zap.String("hash", val.Hash),
+			zap.String("url", val.Original))
+		return val, nil
+	}
+	if !errors.Is(err, models.ErrorCacheMiss) {
+		// If the cache is unavailable, fall back to the database
+		logger.Warn("Cache is unavailable, fetching URL from database", zap.String("hash", hash), zap.Error(err))
+	}
+
+	// Get the URL from the Postgres database
+	logger.Debug("Fetching URL from database", zap.String("hash", hash))
+	started := time.Now()
+	url, err := r.db.Get(ctx, hash)
+	metrics.ObserveDatabase("get", started, queryError(err))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// If the URL is not in the database, return nil
+			logger.Error("URL is not found in the database", zap.String("hash", hash))
+			return nil, nil
 		}
+		logger.Error("Failed to fetch URL from the database", zap.String("hash", hash), zap.Error(err))
+		return nil, err
+	}
+	if url == nil {
+		// If the URL is not in the database, return nil
+		return nil, nil
+	}
 
-		// Save the URL in the Redis cache
-		ttl := viper.GetUint("app.services.hash.ttlCache")
-		err = r.cache.Create(context.Background(), hash, url.Original, time.Duration(ttl)*time.Second)
+	// Save the URL in the cache, a failure here does not fail the request.
+	// The entry never outlives the URL, so an expired URL stops resolving from the cache too.
+	ttl := r.cacheTTL()
+	if url.ExpiresAt != nil {
+		if left := time.Until(*url.ExpiresAt); left < ttl {
+			ttl = left
+		}
+	}
+	if ttl > 0 {
+		err = r.cache.Create(ctx, hash, url.Original, ttl)
 		if err != nil {
-			logger.Error("Failed to save URL in the cache", zap.Error(err))
-			return nil, err
+			logger.Warn("Failed to save URL in the cache", zap.String("hash", hash), zap.Error(err))
 		}
+	}
+
+	// Return the URL
+	logger.Debug("URL is fetched from the database", zap.String("url", url.Original))
+	return url, nil
+}
+
+// Delete is a method of the repository struct that removes a URL from the repository.
+// It takes a context and a hash string as parameters.
+// The context is used for request-scoped data, cancellation signals, and deadlines.
+// The hash string is the hashed version of the URL.
+// The owner is the subject of the caller the URL must belong to; the URL of another owner is not found.
+// It locks the mutex before removing the URL and unlocks it after the removal.
+// It removes the URL from the database, evicts it from the cache of this instance,
+// and publishes the hash on the invalidation bus so every other instance evicts it too.
+// Failures to evict or publish are logged and do not fail the deletion.
+// The duration of the removal from the database is recorded in the metrics package.
+// It returns an error if the removal from the database fails.
+func (r *repository) Delete(ctx context.Context, hash, owner string) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.Delete", tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err, models.ErrorURLNotFound) }()
+
+	r.m.Lock()         // Lock the mutex
+	defer r.m.Unlock() // Unlock the mutex after the removal
+
+	started := time.Now()
+	err = r.db.Delete(ctx, hash, owner)
+	metrics.ObserveDatabase("delete", started, queryError(err))
+	if err != nil {
+		return err
+	}
+
+	r.evict(ctx, hash)
+	return nil
+}
+
+// Search is a method of the repository struct that finds the live URLs of an owner whose original URL or host contains a query.
+// It takes a context, the owner, the query, and the number of URLs to return and to skip.
+// The context is used for request-scoped data, cancellation signals, and deadlines.
+// The search always reads the database, the cache only holds URLs by hash.
+// The duration of the search is recorded in the metrics package.
+// It returns models.ErrorSearchUnsupported if the database cannot search,
+// and an error if the search fails.
+func (r *repository) Search(ctx context.Context, owner, query string, limit, offset int) (_ []*models.URL, err error) {
+	ctx, span := tracing.Start(ctx, "repository.Search")
+	defer func() { tracing.End(span, err) }()
+
+	searcher, ok := r.db.(database.Searcher)
+	if !ok {
+		return nil, models.ErrorSearchUnsupported
+	}
 
-		// Return the URL
-		logger.Debug("URL is fetched from the database", zap.String("url", url.Original))
-		return url, nil
-	} else if err != nil {
-		logger.Error("Failed to fetch URL from the cache", zap.String("hash", hash), zap.Error(err))
+	logger.Debug("Searching URLs in database", zap.String("query", query), zap.Int("limit", limit), zap.Int("offset", offset))
+	started := time.Now()
+	urls, err := searcher.Search(ctx, owner, query, limit, offset)
+	metrics.ObserveDatabase("search", started, err)
+	if err != nil {
+		logger.Error("Failed to search URLs in the database", zap.String("query", query), zap.Error(err))
 		return nil, err
 	}
+	return urls, nil
+}
+
+// SetDisabled is a method of the repository struct that disables a URL, so it no longer resolves, or enables it again.
+// It takes a context, the hash of the URL, and whether to disable it.
+// It locks the mutex before changing the URL and unlocks it after the change.
+// It changes the URL in the database, then evicts it from the caches like Delete, so a disabled URL stops resolving at once.
+// The duration of the change is recorded in the metrics package.
+// It returns models.ErrorDisableUnsupported if the database cannot disable URLs, and an error if the change fails.
+func (r *repository) SetDisabled(ctx context.Context, hash string, disabled bool) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.SetDisabled", tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err, models.ErrorURLNotFound) }()
+
+	disabler, ok := r.db.(database.Disabler)
+	if !ok {
+		return models.ErrorDisableUnsupported
+	}
+
+	r.m.Lock()         // Lock the mutex
+	defer r.m.Unlock() // Unlock the mutex after the change
+
+	started := time.Now()
+	err = disabler.SetDisabled(ctx, hash, disabled)
+	metrics.ObserveDatabase("disable", started, queryError(err))
+	if err != nil {
+		return err
+	}
+
+	r.evict(ctx, hash)
+	return nil
+}
+
+// PurgeCache is a method of the repository struct that evicts a URL from the caches of every instance.
+// It takes a context and the hash of the URL.
+// It evicts the URL from the cache of this instance and publishes the hash on the invalidation bus,
+// so every other instance evicts it too.
+// It returns the error of the cache, and the error of the bus if the cache succeeded.
+func (r *repository) PurgeCache(ctx context.Context, hash string) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.PurgeCache", tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err) }()
+
+	err = r.cache.Delete(ctx, hash)
+	if err != nil {
+		return err
+	}
+	return r.bus.Publish(ctx, hash)
+}
+
+// PurgeCachePrefix is a method of the repository struct that evicts the URLs whose hash starts with a prefix from the cache.
+// It takes a context and the prefix.
+// The invalidation bus only carries single hashes, so the local caches of the other instances
+// keep the URLs until their entries expire.
+// It returns the number of URLs evicted from the shared cache,
+// models.ErrorPurgeUnsupported if the cache cannot purge by prefix, and an error if the purge fails.
+func (r *repository) PurgeCachePrefix(ctx context.Context, prefix string) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "repository.PurgeCachePrefix")
+	defer func() { tracing.End(span, err) }()
+
+	purger, ok := r.cache.(cache.PrefixPurger)
+	if !ok {
+		return 0, models.ErrorPurgeUnsupported
+	}
+	return purger.PurgePrefix(ctx, prefix)
+}
 
-	// If the URL is in the cache, return it
-	logger.Debug("URL is fetched from the cache",
-		zap.String("hash", val.Hash),
-		zap.String("url", val.Original))
-	return val, nil
+// evict is a method of the repository struct that evicts a changed URL from the cache of this instance
+// and publishes its hash on the invalidation bus, so every other instance evicts it too.
+// Failures are logged and do not fail the change, which is already stored.
+func (r *repository) evict(ctx context.Context, hash string) {
+	// Evict the URL from the cache of this instance
+	err := r.cache.Delete(ctx, hash)
+	if err != nil {
+		logger.Warn("Failed to delete URL from the cache", zap.String("hash", hash), zap.Error(err))
+	}
+
+	// Tell the other instances to evict the URL
+	err = r.bus.Publish(ctx, hash)
+	if err != nil {
+		logger.Warn("Failed to publish cache invalidation", zap.String("hash", hash), zap.Error(err))
+	}
+}
+
+// queryError is a function that returns the error of a database operation as recorded in the metrics.
+// A missing URL or a taken hash is an answer of the database rather than a failure, so it returns nil for them.
+func queryError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, models.ErrorURLNotFound) || errors.Is(err, models.ErrorURLExists) {
+		return nil
+	}
+	return err
 }
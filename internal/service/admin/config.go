@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"context"
+	"strings"
+)
+
+// redacted is the value that replaces a secret in the configuration.
+const redacted = "REDACTED"
+
+// secretNames are the words that mark a setting as a secret, matched against its lower-cased name.
+var secretNames = []string{"password", "secret", "token", "dsn"}
+
+// Config is a method on the service struct that returns the configuration this instance runs with.
+// It takes a context.
+// It reads every setting from the current configuration, with the defaults, the environment overrides and the last reload applied,
+// and replaces the value of every setting whose name mentions a password, a secret, a token or a connection string,
+// as the connection strings may carry a password; an empty secret is kept, so an operator can tell it is not set.
+// It returns the settings by section.
+func (s *service) Config(_ context.Context) map[string]any {
+	return redact(s.reloader.Current().Settings())
+}
+
+// redact is a function that returns a copy of the settings with the values of the secrets replaced.
+// It walks the sections recursively.
+func redact(settings map[string]any) map[string]any {
+	out := make(map[string]any, len(settings))
+	for name, value := range settings {
+		switch {
+		case isSecret(name) && !isEmpty(value):
+			out[name] = redacted
+		default:
+			if section, ok := value.(map[string]any); ok {
+				value = redact(section)
+			}
+			out[name] = value
+		}
+	}
+	return out
+}
+
+// isSecret is a function that reports whether the name of a setting marks it as a secret.
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretNames {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty is a function that reports whether the value of a setting is unset: nil, an empty string or an empty list.
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
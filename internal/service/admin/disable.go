@@ -0,0 +1,21 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"go.uber.org/zap"
+)
+
+// SetLinkDisabled is a method on the service struct that disables a URL, so it no longer resolves, or enables it again.
+// It takes a context, the hash of the URL, and whether to disable it.
+// It changes the URL through the repository, which also evicts it from the caches of every instance,
+// and logs the change, so the actions of the operators can be traced back.
+// It returns the error of the repository, models.ErrorURLNotFound if there is no URL with the hash.
+func (s *service) SetLinkDisabled(ctx context.Context, hash string, disabled bool) error {
+	err := s.urlRepository.SetDisabled(ctx, hash, disabled)
+	if err != nil {
+		return err
+	}
+	logger.Info("Changed whether URL is disabled", zap.String("hash", hash), zap.Bool("disabled", disabled))
+	return nil
+}
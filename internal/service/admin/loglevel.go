@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+	"slices"
+)
+
+// logLevels are the names of the levels the operators can change the log level to.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// LogLevel is a method on the service struct that returns the lowest level this instance logs.
+// It takes a context.
+// It returns the name of the level, like "info".
+func (s *service) LogLevel(_ context.Context) string {
+	return logger.Level()
+}
+
+// SetLogLevel is a method on the service struct that changes the lowest level this instance logs, until it is restarted.
+// It takes a context and the name of the level: debug, info, warn or error.
+// It logs the change before it is applied, so it is recorded at the level the instance logged at when the operator changed it.
+// It returns the name of the level it logged before, and models.ErrorInvalidLogLevel if the name is not one of the levels.
+func (s *service) SetLogLevel(_ context.Context, level string) (string, error) {
+	if !slices.Contains(logLevels, level) {
+		return "", models.ErrorInvalidLogLevel
+	}
+	previous := logger.Level()
+	logger.Info("Changing log level", zap.String("from", previous), zap.String("to", level))
+	err := logger.SetLevel(level)
+	if err != nil {
+		return "", err
+	}
+	return previous, nil
+}
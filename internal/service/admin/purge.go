@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"go.uber.org/zap"
+)
+
+// PurgeCache is a method on the service struct that evicts URLs from the cache.
+// It takes a context, and either the hash of a URL or a prefix of the hashes.
+// A hash is evicted from the caches of every instance through the repository.
+// A prefix is evicted from the shared cache and the local cache of this instance; the local caches of the other instances
+// keep their entries until they expire.
+// It logs the purge, so the actions of the operators can be traced back.
+// It returns the number of URLs evicted by a prefix, zero for a hash,
+// models.ErrorInvalidPurge if both or neither of the hash and the prefix are set, and the error of the repository.
+func (s *service) PurgeCache(ctx context.Context, hash, prefix string) (int64, error) {
+	switch {
+	case (hash == "") == (prefix == ""):
+		return 0, models.ErrorInvalidPurge
+	case hash != "":
+		err := s.urlRepository.PurgeCache(ctx, hash)
+		if err != nil {
+			return 0, err
+		}
+		logger.Info("Purged URL from the cache", zap.String("hash", hash))
+		return 0, nil
+	default:
+		n, err := s.urlRepository.PurgeCachePrefix(ctx, prefix)
+		if err != nil {
+			return n, err
+		}
+		logger.Info("Purged URLs from the cache", zap.String("prefix", prefix), zap.Int64("purged", n))
+		return n, nil
+	}
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/health"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/breaker"
+	"github.com/t1ltxz-gxd/shortify/internal/repository"
+	def "github.com/t1ltxz-gxd/shortify/internal/service"
+	"time"
+)
+
+// Ensure that the service struct implements the AdminService interface
+var _ def.AdminService = (*service)(nil)
+
+// service is a struct that represents a service for the operational actions on a running instance.
+// It has five fields: urlRepository, health, cacheBreaker, startedAt and reloader.
+// urlRepository is the repository whose URLs and cache the operators change.
+// health and cacheBreaker report the state of the dependencies in the statistics,
+// startedAt is the time when the instance started, and reloader holds the configuration it runs with.
+type service struct {
+	urlRepository repository.URLRepository // The repository for URLs
+	health        health.Checker           // The health of the dependencies
+	cacheBreaker  breaker.Breaker          // The circuit breaker around the shared cache
+	startedAt     time.Time                // The time when the instance started
+	reloader      *config.Reloader         // The current configuration of the instance
+}
+
+// NewService is a function that creates a new service for the operational actions.
+// It takes the repository for URLs, the health checker, the circuit breaker around the shared cache,
+// the time when the instance started, and the reloader holding the current configuration of the instance.
+// It returns an instance of the AdminService interface.
+func NewService(
+	urlRepository repository.URLRepository, // The repository for URLs
+	health health.Checker, // The health of the dependencies
+	cacheBreaker breaker.Breaker, // The circuit breaker around the shared cache
+	startedAt time.Time, // The time when the instance started
+	reloader *config.Reloader, // The current configuration of the instance
+) def.AdminService {
+	return &service{
+		urlRepository: urlRepository, // Set the repository for URLs
+		health:        health,        // Set the health checker
+		cacheBreaker:  cacheBreaker,  // Set the circuit breaker around the shared cache
+		startedAt:     startedAt,     // Set the time when the instance started
+		reloader:      reloader,      // Set the current configuration of the instance
+	}
+}
@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"runtime"
+)
+
+// Stats is a method on the service struct that returns the runtime statistics of this instance.
+// It takes a context.
+// It reads the memory statistics of the Go runtime, which stops the world for a moment,
+// the readiness from the health checker, and the state of the circuit breaker around the shared cache.
+// It returns the statistics.
+func (s *service) Stats(_ context.Context) *models.Stats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return &models.Stats{
+		StartedAt:  s.startedAt,                     // Set the time when the instance started
+		GoVersion:  runtime.Version(),               // Set the version of Go
+		Goroutines: runtime.NumGoroutine(),          // Set the number of goroutines
+		HeapBytes:  mem.HeapAlloc,                   // Set the bytes of allocated heap objects
+		GCCycles:   mem.NumGC,                       // Set the number of garbage collection cycles
+		Ready:      s.health.Ready(),                // Set whether the instance is ready
+		CacheState: s.cacheBreaker.State().String(), // Set the state of the circuit breaker
+	}
+}
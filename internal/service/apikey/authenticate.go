@@ -0,0 +1,30 @@
+package apikey
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	"strings"
+)
+
+// Authenticate is a method of the service struct that finds the active API key a caller presented.
+// It takes a context and the key.
+// A key without the prefix of the keys of the application is rejected without asking the repository.
+// It returns the description of the key, models.ErrorAPIKeyNotFound if the key is unknown or revoked,
+// and an error if the lookup fails.
+func (s *service) Authenticate(ctx context.Context, key string) (_ *models.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "service.Authenticate")
+	defer func() { tracing.End(span, err, models.ErrorAPIKeyNotFound) }()
+
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, models.ErrorAPIKeyNotFound
+	}
+	apiKey, err := s.apiKeyRepository.GetByHash(ctx, hashKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, models.ErrorAPIKeyNotFound
+	}
+	return apiKey, nil
+}
@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// Create is a method of the service struct that issues a new API key.
+// It takes a context and the name of the holder of the key.
+// The name is trimmed and must not be empty.
+// It generates a random key and ID and stores the key hashed, so the key is only known to the caller.
+// It logs the ID and the name of the new key, never the key itself.
+// It returns the key, its description, and models.ErrorInvalidAPIKeyName if the name is empty or an error if the creation fails.
+func (s *service) Create(ctx context.Context, name string) (_ string, _ *models.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "service.CreateAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, models.ErrorInvalidAPIKeyName
+	}
+
+	key, id, err := newKey()
+	if err != nil {
+		return "", nil, err
+	}
+	apiKey := &models.APIKey{
+		ID:        id,           // Set the identifier of the key
+		Name:      name,         // Set the name of the holder of the key
+		Hash:      hashKey(key), // Set the hash of the key
+		CreatedAt: time.Now(),   // Set the time when the key was created
+	}
+	err = s.apiKeyRepository.Create(ctx, apiKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	logger.Info("API key is created", zap.String("id", apiKey.ID), zap.String("name", apiKey.Name))
+	return key, apiKey, nil
+}
@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+)
+
+// List is a method of the service struct that returns every API key, the revoked ones included, the newest first.
+// It takes a context.
+// It returns the descriptions of the keys and an error if the repository fails.
+func (s *service) List(ctx context.Context) (_ []*models.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "service.ListAPIKeys")
+	defer func() { tracing.End(span, err) }()
+
+	return s.apiKeyRepository.List(ctx)
+}
@@ -0,0 +1,26 @@
+package apikey
+
+import (
+	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	"go.uber.org/zap"
+)
+
+// Revoke is a method of the service struct that revokes an API key, so it no longer authenticates.
+// It takes a context and the ID of the key.
+// The URLs created with the key stay owned by it.
+// It logs the revoked key and returns models.ErrorAPIKeyNotFound if there is no active key with the ID,
+// and an error if the revocation fails.
+func (s *service) Revoke(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "service.RevokeAPIKey")
+	defer func() { tracing.End(span, err, models.ErrorAPIKeyNotFound) }()
+
+	err = s.apiKeyRepository.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+	logger.Info("API key is revoked", zap.String("id", id))
+	return nil
+}
@@ -0,0 +1,57 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/t1ltxz-gxd/shortify/internal/repository"
+	def "github.com/t1ltxz-gxd/shortify/internal/service"
+)
+
+// Ensure that the service struct implements the APIKeyService interface
+var _ def.APIKeyService = (*service)(nil)
+
+// keyPrefix is the prefix of every API key, so a leaked key is easy to recognise in logs and by secret scanners.
+const keyPrefix = "shk_"
+
+// service is a struct that represents a service for API keys.
+// It has one field: apiKeyRepository.
+// apiKeyRepository is an instance of the APIKeyRepository interface that represents the repository for API keys.
+type service struct {
+	apiKeyRepository repository.APIKeyRepository // The repository for API keys
+}
+
+// NewService is a function that creates a new service for API keys.
+// It takes an instance of the APIKeyRepository interface as a parameter.
+// It returns an instance of the APIKeyService interface.
+func NewService(
+	apiKeyRepository repository.APIKeyRepository, // The repository for API keys
+) def.APIKeyService {
+	return &service{
+		apiKeyRepository: apiKeyRepository, // Set the repository for API keys
+	}
+}
+
+// newKey is a function that generates a new API key and its ID from the random source of the operating system.
+// The key carries 256 random bits, so a plain SHA-256 hash is enough to store it safely.
+// It returns the key, the ID, and an error if the random source fails.
+func newKey() (string, string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", "", err
+	}
+	id := make([]byte, 8)
+	_, err = rand.Read(id)
+	if err != nil {
+		return "", "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(secret), hex.EncodeToString(id), nil
+}
+
+// hashKey is a function that returns the hex-encoded SHA-256 hash of an API key, which is what the repository stores.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -4,19 +4,23 @@ import (
 	"context"
 
 	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"time"
 )
 
 // URLService is an interface that represents a service for URLs.
-// It has two methods: Create and Get.
+// It has four methods: Create, Get, Delete and Search.
 type URLService interface {
 	// Create is a method that creates a new URL in the service.
 	// It takes a context and a URL string as parameters.
 	// The context is used for request-scoped data, cancellation signals, and deadlines.
+	// The URL is owned by the principal of the context, or by nobody for an anonymous caller.
+	// A principal without the urls:write scope gets models.ErrorPermissionDenied.
 	// The URL string is the original URL.
+	// The TTL is how long the short URL resolves; zero uses the default lifetime from the configuration.
 	// It returns a hash string that represents the hashed version of the URL and an error.
 	// If the creation is successful, the error is nil.
 	// If the creation fails, the hash string is empty and the error contains the failure reason.
-	Create(ctx context.Context, url string) (string, error)
+	Create(ctx context.Context, url string, ttl time.Duration) (string, error)
 
 	// Get is a method that retrieves a URL from the service.
 	// It takes a context and a hash string as parameters.
@@ -26,4 +30,86 @@ type URLService interface {
 	// If the retrieval is successful, the error is nil.
 	// If the retrieval fails, the URL model is nil and the error contains the failure reason.
 	Get(ctx context.Context, hash string) (*models.URL, error)
+
+	// Delete is a method that removes a URL from the service.
+	// It takes a context and a hash string as parameters.
+	// The context is used for request-scoped data, cancellation signals, and deadlines.
+	// The hash string is the hashed version of the URL.
+	// It returns an error if the deletion fails.
+	// If there is no URL with the hash owned by the principal of the context, the error is models.ErrorInvalidURL.
+	// A principal without the urls:write scope gets models.ErrorPermissionDenied.
+	Delete(ctx context.Context, hash string) error
+
+	// Search is a method that finds the live URLs whose original URL or host contains a query.
+	// Only the URLs owned by the principal of the context are searched.
+	// A principal without the urls:read scope gets models.ErrorPermissionDenied.
+	// It takes a context, the query, the maximum number of URLs to return, and the page token of a previous search.
+	// It returns a page of matching URLs, the token of the next page, empty on the last page, and an error.
+	// If the query is too short or the page token is malformed, the error is models.ErrorInvalidQuery.
+	Search(ctx context.Context, query string, pageSize int, pageToken string) ([]*models.URL, string, error)
+}
+
+// APIKeyService is an interface that represents a service for API keys.
+// It has four methods: Create, Authenticate, List and Revoke.
+type APIKeyService interface {
+	// Create is a method that issues a new API key.
+	// It takes a context and the name of the holder of the key.
+	// It returns the key, which is not stored and cannot be retrieved again, its description, and an error.
+	// If the name is empty, the error is models.ErrorInvalidAPIKeyName.
+	Create(ctx context.Context, name string) (string, *models.APIKey, error)
+
+	// Authenticate is a method that finds the active API key a caller presented.
+	// It takes a context and the key.
+	// It returns the description of the key and an error.
+	// If the key is unknown or revoked, the error is models.ErrorAPIKeyNotFound.
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+
+	// List is a method that returns every API key, the revoked ones included, the newest first.
+	// It takes a context.
+	// It returns the descriptions of the keys and an error.
+	List(ctx context.Context) ([]*models.APIKey, error)
+
+	// Revoke is a method that revokes an API key, so it no longer authenticates.
+	// It takes a context and the ID of the key.
+	// It returns an error if the revocation fails.
+	// If there is no active key with the ID, the error is models.ErrorAPIKeyNotFound.
+	Revoke(ctx context.Context, id string) error
+}
+
+// AdminService is an interface that represents a service for the operational actions on a running instance.
+// It has four methods: PurgeCache, SetLinkDisabled, Stats and Config.
+type AdminService interface {
+	// PurgeCache is a method that evicts URLs from the cache.
+	// It takes a context, and either the hash of a URL, evicted from the caches of every instance,
+	// or a prefix of the hashes, evicted from the shared cache and the local cache of this instance.
+	// It returns the number of URLs evicted by a prefix, zero for a hash, and an error.
+	// If both or neither of the hash and the prefix are set, the error is models.ErrorInvalidPurge.
+	PurgeCache(ctx context.Context, hash, prefix string) (int64, error)
+
+	// SetLinkDisabled is a method that disables a URL, so it no longer resolves without being deleted, or enables it again.
+	// It takes a context, the hash of the URL, and whether to disable it.
+	// It returns an error if the change fails.
+	// If there is no URL with the hash, the error is models.ErrorURLNotFound.
+	SetLinkDisabled(ctx context.Context, hash string, disabled bool) error
+
+	// Stats is a method that returns the runtime statistics of this instance.
+	// It takes a context.
+	// It returns the statistics.
+	Stats(ctx context.Context) *models.Stats
+
+	// Config is a method that returns the configuration this instance runs with.
+	// It takes a context.
+	// It returns the settings by section, with the environment overrides applied and the values of the secrets redacted.
+	Config(ctx context.Context) map[string]any
+
+	// LogLevel is a method that returns the lowest level this instance logs.
+	// It takes a context.
+	// It returns the name of the level, like "info".
+	LogLevel(ctx context.Context) string
+
+	// SetLogLevel is a method that changes the lowest level this instance logs, until it is restarted.
+	// It takes a context and the name of the level: debug, info, warn or error.
+	// It returns the name of the level it logged before, and an error.
+	// If the name is not a level, the error is models.ErrorInvalidLogLevel.
+	SetLogLevel(ctx context.Context, level string) (string, error)
 }
@@ -4,17 +4,24 @@ import (
 	"context"
 	"fmt"
 	"github.com/speps/go-hashids"
-	"github.com/spf13/viper"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
 	"go.uber.org/zap"
+	"time"
 )
 
 // Create is a method of the service struct that creates a new URL in the service.
 // It takes a context and a URL string as parameters.
 // The context is used for request-scoped data, cancellation signals, and deadlines.
 // The URL string is the original URL.
+// The TTL is how long the short URL resolves; zero uses the linkTTL of the hash configuration in seconds, and if that is zero too the URL never expires.
 // It first logs a debug message that it is creating a new short for the URL.
-// It then creates a new hash data with the URL as the salt, the minimum length from the configuration, and the alphabet from the configuration.
+// It then creates a new hash data with the owner and the URL as the salt, and the minimum length and the alphabet from the hash configuration.
+// The owner is part of the salt so that owners shortening the same URL get links of their own,
+// instead of a conflict with a link they can neither see nor delete. The URLs of anonymous callers are salted with the URL alone.
 // It logs the minimum length and the alphabet.
 // It creates a new hash with the hash data.
 // It uses the length of the URL as the ID.
@@ -22,14 +29,26 @@ import (
 // It generates a unique hash for the ID and logs a debug message that it is generating a hash for the ID.
 // It checks if the hash is already in use and logs a debug message that it is checking if the hash is already in use.
 // If the hash is already in use, it logs a debug message that the hash is already in use and returns an error.
-// If the hash is not in use, it creates a short URL with the host and the gRPC port from the configuration and the hash.
-// It returns the short URL and nil.
-func (s *service) Create(ctx context.Context, url string) (string, error) {
+// The URL is owned by the principal of the context, or by nobody for an anonymous caller,
+// and the principal must have the urls:write scope, otherwise it returns models.ErrorPermissionDenied.
+// If the hash is not in use, it creates a short URL with the base URL of the service and the hash.
+// It counts the created URL in the metrics package and returns the short URL and nil.
+// The creation is traced in a span that records the generated hash.
+func (s *service) Create(ctx context.Context, url string, ttl time.Duration) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "service.Create")
+	defer func() { tracing.End(span, err) }()
+
+	owner := auth.Owner(ctx)
+	if !auth.HasScope(ctx, auth.ScopeWrite) {
+		logger.Error("Caller may not create URLs", zap.String("owner", owner))
+		return "", models.ErrorPermissionDenied
+	}
+
 	logger.Debug("Creating a new short for URL...", zap.String("url", url)) // Log the creation
 	hd := hashids.NewData()                                                 // Create new hash data
-	hd.Salt = url                                                           // Set the salt
-	hd.MinLength = viper.GetInt("app.services.hash.minLength")              // Set the minimum length
-	hd.Alphabet = viper.GetString("app.services.hash.alphabet")             // Set the alphabet
+	hd.Salt = salt(owner, url)                                              // Set the salt
+	hd.MinLength = s.hash.MinLength                                         // Set the minimum length
+	hd.Alphabet = s.hash.Alphabet                                           // Set the alphabet
 	logger.Debug("minLength", zap.Int("minLength", hd.MinLength))           // Log the minimum length
 	logger.Debug("alphabet", zap.String("alphabet", hd.Alphabet))           // Log the alphabet
 	h, _ := hashids.NewWithData(hd)                                         // Create new hash
@@ -41,15 +60,40 @@ func (s *service) Create(ctx context.Context, url string) (string, error) {
 	// Generate a unique hash for the ID
 	logger.Debug("Generating a hash for the ID...", zap.Int("id", id)) // Log the generation
 	hash, _ := h.Encode([]int{id})                                     // Generate the hash
+	span.SetAttributes(tracing.HashKey.String(hash))                   // Record the hash in the trace
 
 	// Check if the hash is already in use
 	logger.Debug("Checking if the hash is already in use...", zap.String("hash", hash)) // Log the check
-	err := s.urlRepository.Create(ctx, hash, url)                                       // Create the URL
+	err = s.urlRepository.Create(ctx, hash, url, owner, s.expiresAt(ttl))               // Create the URL
 	if err != nil {
 		logger.Debug("The hash is already in use!", zap.String("hash", hash)) // Log the error
 		return "", err                                                        // Return the error
 	}
-	shortURL := fmt.Sprintf("http://%s:%d/%s", viper.GetString("host"), viper.GetInt("ports.grpc"), hash) // Create the short URL
+	metrics.LinksCreated.Inc()
+	shortURL := fmt.Sprintf("%s/%s", s.baseURL, hash) // Create the short URL
 
 	return shortURL, nil // Return the short URL
 }
+
+// salt is a function that returns the salt of the hash of a URL created by the owner.
+// It is the URL for an anonymous caller, and the owner and the URL separated by a newline, which neither contains, otherwise.
+func salt(owner, url string) string {
+	if owner == "" {
+		return url
+	}
+	return owner + "\n" + url
+}
+
+// expiresAt is a method of the service struct that returns the time when a URL created now with the TTL expires.
+// A TTL of zero falls back to the linkTTL of the hash configuration in seconds.
+// It returns nil if the URL never expires.
+func (s *service) expiresAt(ttl time.Duration) *time.Time {
+	if ttl <= 0 {
+		ttl = time.Duration(s.hash.LinkTTL) * time.Second
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	t := time.Now().Add(ttl)
+	return &t
+}
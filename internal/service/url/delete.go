@@ -0,0 +1,41 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	"go.uber.org/zap"
+)
+
+// Delete is a method of the service struct that removes a URL from the service.
+// It takes a context and a hash string as parameters.
+// The context is used for request-scoped data, cancellation signals, and deadlines.
+// The hash string is the hashed version of the URL.
+// Only a URL owned by the principal of the context can be removed, a URL of another owner is reported as missing,
+// and the principal must have the urls:write scope, otherwise it returns models.ErrorPermissionDenied.
+// If there is no URL with the hash, it logs an error and returns an invalid URL error.
+// If the removal from the repository fails, it logs an error and returns the error.
+func (s *service) Delete(ctx context.Context, hash string) (err error) {
+	ctx, span := tracing.Start(ctx, "service.Delete", tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err) }()
+
+	if !auth.HasScope(ctx, auth.ScopeWrite) {
+		logger.Error("Caller may not delete URLs", zap.String("owner", auth.Owner(ctx)))
+		return models.ErrorPermissionDenied
+	}
+
+	logger.Debug("Deleting URL from repository", zap.String("hash", hash))
+	err = s.urlRepository.Delete(ctx, hash, auth.Owner(ctx))
+	if errors.Is(err, models.ErrorURLNotFound) {
+		logger.Error("Original URL is not found", zap.String("hash", hash))
+		return models.ErrorInvalidURL
+	}
+	if err != nil {
+		logger.Error("Failed to delete URL from repository", zap.String("hash", hash), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package url
 
 import (
 	"context"
+	"github.com/t1ltxz-gxd/shortify/internal/metrics"
 	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
 	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
 	"go.uber.org/zap"
 )
 
@@ -15,7 +17,11 @@ import (
 // If the retrieval from the repository fails, it logs an error and returns the error.
 // If the original URL is not in the repository, it logs an error and returns an invalid URL error.
 // If the original URL is in the repository, it returns the original URL.
-func (s *service) Get(ctx context.Context, hash string) (*models.URL, error) {
+// Resolved and missing links are counted in the metrics package.
+func (s *service) Get(ctx context.Context, hash string) (_ *models.URL, err error) {
+	ctx, span := tracing.Start(ctx, "service.Get", tracing.HashKey.String(hash))
+	defer func() { tracing.End(span, err) }()
+
 	// Get the original URL from repository
 	logger.Debug("Fetching URL from repository", zap.String("hash", hash))
 	originalURL, err := s.urlRepository.Get(ctx, hash)
@@ -27,7 +33,9 @@ func (s *service) Get(ctx context.Context, hash string) (*models.URL, error) {
 	// Type assert the original URL to string
 	if originalURL == nil {
 		logger.Error("Original URL is not found", zap.String("hash", hash))
+		metrics.LinksNotFound.Inc()
 		return nil, models.ErrorInvalidURL
 	}
+	metrics.LinksResolved.Inc()
 	return originalURL, nil
 }
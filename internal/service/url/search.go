@@ -0,0 +1,96 @@
+package url
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	minQueryLength  = 3   // The shortest query, shorter ones match almost every URL
+	defaultPageSize = 20  // The page size when the request does not set one
+	maxPageSize     = 100 // The largest page size a request may ask for
+)
+
+// Search is a method of the service struct that finds the live URLs whose original URL or host contains a query.
+// It takes a context, the query, the maximum number of URLs to return, and the page token of a previous search.
+// The context is used for request-scoped data, cancellation signals, and deadlines.
+// Only the URLs owned by the principal of the context are searched,
+// and the principal must have the urls:read scope, otherwise it returns models.ErrorPermissionDenied.
+// The query is trimmed and must be at least three characters long.
+// A page size of zero means 20, and page sizes above 100 are capped.
+// The page token is opaque to the clients; it holds the number of URLs the previous pages returned.
+// It fetches one URL more than the page size to tell whether there is a next page.
+// It returns the page of URLs, the token of the next page, empty on the last page,
+// and models.ErrorInvalidQuery if the query is too short or the page token is malformed.
+func (s *service) Search(ctx context.Context, query string, pageSize int, pageToken string) (_ []*models.URL, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "service.Search")
+	defer func() { tracing.End(span, err) }()
+
+	if !auth.HasScope(ctx, auth.ScopeRead) {
+		logger.Error("Caller may not search URLs", zap.String("owner", auth.Owner(ctx)))
+		return nil, "", models.ErrorPermissionDenied
+	}
+
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < minQueryLength {
+		logger.Error("Search query is too short", zap.String("query", query))
+		return nil, "", models.ErrorInvalidQuery
+	}
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+	offset, err := decodePageToken(pageToken)
+	if err != nil {
+		logger.Error("Page token is malformed", zap.String("pageToken", pageToken), zap.Error(err))
+		return nil, "", models.ErrorInvalidQuery
+	}
+
+	urls, err := s.urlRepository.Search(ctx, auth.Owner(ctx), query, pageSize+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(urls) > pageSize {
+		urls = urls[:pageSize]
+		next = encodePageToken(offset + pageSize)
+	}
+	return urls, next, nil
+}
+
+// encodePageToken is a function that encodes the offset of the next page into a page token.
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodePageToken is a function that decodes the offset of a page from its page token.
+// An empty token is the first page.
+// It returns an error if the token was not made by encodePageToken.
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, strconv.ErrRange
+	}
+	return offset, nil
+}
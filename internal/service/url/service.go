@@ -1,6 +1,7 @@
 package url
 
 import (
+	"github.com/t1ltxz-gxd/shortify/internal/config"
 	"github.com/t1ltxz-gxd/shortify/internal/repository"
 	def "github.com/t1ltxz-gxd/shortify/internal/service"
 )
@@ -9,21 +10,29 @@ import (
 var _ def.URLService = (*service)(nil)
 
 // service is a struct that represents a service for URLs.
-// It has one field: urlRepository.
+// It has three fields: urlRepository, hash, and baseURL.
 // urlRepository is an instance of the URLRepository interface that represents the repository for URLs.
+// hash is the configuration of the generated hashes and the lifetime of the URLs.
+// baseURL is the address the short URLs start with, like http://localhost:8001.
 type service struct {
 	urlRepository repository.URLRepository // The repository for URLs
+	hash          config.Hash              // The hash configuration
+	baseURL       string                   // The address of the short URLs
 }
 
 // NewService is a function that creates a new service for URLs.
-// It takes an instance of the URLRepository interface as a parameter.
+// It takes an instance of the URLRepository interface, the hash configuration, and the base URL of the short URLs as parameters.
 // The URLRepository instance represents the repository for URLs.
 // It returns an instance of the URLService interface.
 // The URLService instance represents the service for URLs.
 func NewService(
 	urlRepository repository.URLRepository, // The repository for URLs
+	hash config.Hash, // The hash configuration
+	baseURL string, // The address of the short URLs
 ) def.URLService {
 	return &service{
 		urlRepository: urlRepository, // Set the repository for URLs
+		hash:          hash,          // Set the hash configuration
+		baseURL:       baseURL,       // Set the address of the short URLs
 	}
 }
@@ -0,0 +1,219 @@
+package url_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/auth"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	memoryDB "github.com/t1ltxz-gxd/shortify/internal/database/memory/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/cache/invalidation"
+	memoryCache "github.com/t1ltxz-gxd/shortify/internal/middleware/cache/memory/url"
+	"github.com/t1ltxz-gxd/shortify/internal/middleware/logger"
+	"github.com/t1ltxz-gxd/shortify/internal/models"
+	urlRepository "github.com/t1ltxz-gxd/shortify/internal/repository/url"
+	"github.com/t1ltxz-gxd/shortify/internal/service/url"
+)
+
+// TestMain is a function that initializes the logger before running the tests.
+func TestMain(m *testing.M) {
+	_ = logger.Init("dev", logger.Options{})
+	os.Exit(m.Run())
+}
+
+// MockURLRepository is a struct that mocks the URLRepository interface for testing.
+// It embeds the mock.Mock struct from the testify/mock package.
+type MockURLRepository struct {
+	mock.Mock
+}
+
+// Create is a method that mocks the Create method of the URLRepository interface.
+func (m *MockURLRepository) Create(ctx context.Context, hash, url, owner string, expiresAt *time.Time) error {
+	args := m.Called(ctx, hash, url, owner, expiresAt)
+	return args.Error(0)
+}
+
+// Get is a method that mocks the Get method of the URLRepository interface.
+func (m *MockURLRepository) Get(ctx context.Context, hash string) (*models.URL, error) {
+	args := m.Called(ctx, hash)
+	if u := args.Get(0); u != nil {
+		return u.(*models.URL), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Delete is a method that mocks the Delete method of the URLRepository interface.
+func (m *MockURLRepository) Delete(ctx context.Context, hash, owner string) error {
+	args := m.Called(ctx, hash, owner)
+	return args.Error(0)
+}
+
+// Search is a method that mocks the Search method of the URLRepository interface.
+func (m *MockURLRepository) Search(ctx context.Context, owner, query string, limit, offset int) ([]*models.URL, error) {
+	args := m.Called(ctx, owner, query, limit, offset)
+	if urls := args.Get(0); urls != nil {
+		return urls.([]*models.URL), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// SetDisabled is a method that mocks the SetDisabled method of the URLRepository interface.
+func (m *MockURLRepository) SetDisabled(ctx context.Context, hash string, disabled bool) error {
+	args := m.Called(ctx, hash, disabled)
+	return args.Error(0)
+}
+
+// PurgeCache is a method that mocks the PurgeCache method of the URLRepository interface.
+func (m *MockURLRepository) PurgeCache(ctx context.Context, hash string) error {
+	args := m.Called(ctx, hash)
+	return args.Error(0)
+}
+
+// PurgeCachePrefix is a method that mocks the PurgeCachePrefix method of the URLRepository interface.
+func (m *MockURLRepository) PurgeCachePrefix(ctx context.Context, prefix string) (int64, error) {
+	args := m.Called(ctx, prefix)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// hashConfig is the hash configuration of the services under test.
+var hashConfig = config.Hash{MinLength: 6, Alphabet: "abcdefghijklmnopqrstuvwxyz0123456789"}
+
+// pageToken is a function that builds the page token of the page starting at the offset, like the service does.
+func pageToken(offset string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(offset))
+}
+
+// urls is a function that returns n URLs for the repository to find.
+func urls(n int) []*models.URL {
+	found := make([]*models.URL, 0, n)
+	for i := 0; i < n; i++ {
+		found = append(found, &models.URL{Original: fmt.Sprintf("https://example.com/%d", i), Hash: fmt.Sprintf("h%d", i)})
+	}
+	return found
+}
+
+// TestSearch_Pages is a test function that checks how Search sizes the pages and pages through the results.
+// It checks that a page size of zero means 20, that larger page sizes are capped at 100,
+// that the repository is asked for one URL more than the page size to tell whether there is a next page,
+// that the page token sets the offset, and that the last page has no next page token.
+func TestSearch_Pages(t *testing.T) {
+	cases := []struct {
+		name      string
+		pageSize  int
+		pageToken string
+		limit     int // The number of URLs the repository is asked for
+		offset    int // The number of URLs the repository is asked to skip
+		found     int // The number of URLs the repository finds
+		page      int // The number of URLs returned
+		next      string
+	}{
+		{name: "DefaultPageSize", pageSize: 0, limit: 21, found: 21, page: 20, next: pageToken("20")},
+		{name: "NegativePageSize", pageSize: -1, limit: 21, found: 3, page: 3},
+		{name: "CappedPageSize", pageSize: 500, limit: 101, found: 101, page: 100, next: pageToken("100")},
+		{name: "SecondPage", pageSize: 10, pageToken: pageToken("10"), limit: 11, offset: 10, found: 11, page: 10, next: pageToken("20")},
+		{name: "LastPage", pageSize: 10, pageToken: pageToken("20"), limit: 11, offset: 20, found: 4, page: 4},
+		{name: "LastPageExactlyFull", pageSize: 10, limit: 11, found: 10, page: 10},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repo := new(MockURLRepository)
+			repo.On("Search", mock.Anything, "", "example", c.limit, c.offset).Return(urls(c.found), nil)
+			service := url.NewService(repo, hashConfig, "http://localhost:8001")
+
+			page, next, err := service.Search(context.Background(), "  example ", c.pageSize, c.pageToken)
+
+			require.NoError(t, err)
+			assert.Len(t, page, c.page)
+			assert.Equal(t, c.next, next)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSearch_Invalid is a test function that checks that Search rejects a query shorter than three characters
+// and a page token it did not make with models.ErrorInvalidQuery, without reaching the repository.
+func TestSearch_Invalid(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		pageToken string
+	}{
+		{name: "ShortQuery", query: " ab "},
+		{name: "EmptyQuery", query: ""},
+		{name: "GarbageToken", query: "example", pageToken: "!!not base64!!"},
+		{name: "NotANumber", query: "example", pageToken: pageToken("ten")},
+		{name: "NegativeOffset", query: "example", pageToken: pageToken("-20")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repo := new(MockURLRepository)
+			service := url.NewService(repo, hashConfig, "http://localhost:8001")
+
+			page, next, err := service.Search(context.Background(), c.query, 10, c.pageToken)
+
+			assert.ErrorIs(t, err, models.ErrorInvalidQuery)
+			assert.Nil(t, page)
+			assert.Empty(t, next)
+			repo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestSearch_Scope is a test function that checks that Search needs the urls:read scope,
+// and only searches the URLs owned by the principal of the context.
+func TestSearch_Scope(t *testing.T) {
+	repo := new(MockURLRepository)
+	repo.On("Search", mock.Anything, "owner1", "example", 11, 0).Return(urls(1), nil)
+	service := url.NewService(repo, hashConfig, "http://localhost:8001")
+
+	writer := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "owner1", Scopes: []string{auth.ScopeWrite}})
+	_, _, err := service.Search(writer, "example", 10, "")
+	assert.ErrorIs(t, err, models.ErrorPermissionDenied)
+
+	reader := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "owner1", Scopes: []string{auth.ScopeRead}})
+	page, _, err := service.Search(reader, "example", 10, "")
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+	repo.AssertExpectations(t)
+}
+
+// TestCreate_Owners is a test function that checks that owners shortening the same URL get links of their own,
+// which only they can delete, while shortening it twice as the same owner is still a conflict.
+func TestCreate_Owners(t *testing.T) {
+	repo := urlRepository.NewRepository(memoryDB.NewDatabase(), memoryCache.NewCache(10), invalidation.NewNopBus(),
+		func() time.Duration { return time.Minute })
+	service := url.NewService(repo, hashConfig, "http://localhost:8001")
+	as := func(owner string) context.Context {
+		return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: owner, Scopes: []string{auth.ScopeRead, auth.ScopeWrite}})
+	}
+	hash := func(short string) string {
+		return strings.TrimPrefix(short, "http://localhost:8001/")
+	}
+
+	first, err := service.Create(as("owner1"), "https://example.com", 0)
+	require.NoError(t, err)
+	second, err := service.Create(as("owner2"), "https://example.com", 0)
+	require.NoError(t, err)
+	anonymous, err := service.Create(context.Background(), "https://example.com", 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, first, anonymous)
+	assert.NotEqual(t, second, anonymous)
+
+	_, err = service.Create(as("owner1"), "https://example.com", 0)
+	assert.ErrorIs(t, err, models.ErrorURLExists)
+
+	assert.ErrorIs(t, service.Delete(as("owner2"), hash(first)), models.ErrorInvalidURL)
+	require.NoError(t, service.Delete(as("owner2"), hash(second)))
+	got, err := service.Get(context.Background(), hash(first))
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", got.Original)
+}
@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Names of the exporters selected by the tracing.exporter setting
+const (
+	ExporterNone   = "none"   // Spans are not recorded
+	ExporterOTLP   = "otlp"   // Spans are sent to an OpenTelemetry collector over OTLP/gRPC
+	ExporterStdout = "stdout" // Spans are written to the standard output as JSON
+	ExporterFile   = "file"   // Spans are written to a file as JSON
+)
+
+// instrumentationName is the name of the tracer of the application.
+const instrumentationName = "github.com/t1ltxz-gxd/shortify"
+
+// Attribute keys of the application
+const (
+	HashKey = attribute.Key("shortify.hash") // The hash of the short URL the span works on
+)
+
+// Init is a function that sets up tracing from the tracing settings.
+// It installs the W3C trace context and baggage propagators, so the trace of an incoming request continues in the spans
+// of the application, and a tracer provider exporting to the exporter selected by tracing.exporter:
+// "otlp" for a collector at tracing.otlp.endpoint, "stdout" or "file" to write the spans as JSON without a collector,
+// at tracing.file for the latter, and "none" or an empty value to record nothing.
+// tracing.sampleRatio is the share of the new traces that are recorded; a trace started by the caller keeps its decision.
+// It takes a context for managing the lifecycle of the connection to the collector, the tracing settings,
+// and the environment the application runs in, recorded as the deployment environment of the spans.
+// It returns a function that flushes the pending spans and stops the exporter, and an error if the exporter cannot be created.
+func Init(ctx context.Context, cfg config.Tracing, env string) (func(ctx context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var closeFile io.Closer
+	var err error
+	switch name := cfg.Exporter; name {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLP.Endpoint)}
+		if cfg.OTLP.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		path := cfg.File
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		var f *os.File
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		closeFile = f
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.DeploymentEnvironment(env),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeFile != nil {
+			if closeErr := closeFile.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// Start is a function that starts a span of the application as a child of the span in the context, if any.
+// It takes the context, the name of the span and its attributes.
+// It returns the context holding the new span and the span, which the caller ends with End.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End is a function that ends a span, recording the error and marking the span as failed if the error is not nil.
+// The expected errors, like a cache miss, are outcomes rather than failures: they end the span without marking it.
+func End(span trace.Span, err error, expected ...error) {
+	for _, e := range expected {
+		if errors.Is(err, e) {
+			err = nil
+			break
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,100 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/t1ltxz-gxd/shortify/internal/config"
+	"github.com/t1ltxz-gxd/shortify/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newRecorder is a function that installs a tracer provider recording the ended spans in memory for the duration of the test.
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+// TestEnd is a test function that checks that End marks a span as failed only for an unexpected error.
+func TestEnd(t *testing.T) {
+	recorder := newRecorder(t)
+	expected := errors.New("not found")
+
+	ctx, parent := tracing.Start(context.Background(), "parent", tracing.HashKey.String("abc"))
+	_, child := tracing.Start(ctx, "child")
+	tracing.End(child, expected, expected)
+	tracing.End(parent, errors.New("boom"), expected)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	assert.Equal(t, "parent", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+	assert.Contains(t, spans[1].Attributes(), tracing.HashKey.String("abc"))
+}
+
+// TestInit_Propagation is a test function that checks that the trace of an incoming W3C traceparent header
+// continues in the spans of the application.
+func TestInit_Propagation(t *testing.T) {
+	shutdown, err := tracing.Init(context.Background(), config.Tracing{Exporter: tracing.ExporterNone}, "dev")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+	recorder := newRecorder(t)
+
+	carrier := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	_, span := tracing.Start(ctx, "api.Get")
+	tracing.End(span, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
+
+// TestInit_File is a test function that checks that the file exporter writes the spans to the configured file
+// once the tracing is shut down.
+func TestInit_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces", "traces.json")
+	cfg := config.Tracing{Exporter: tracing.ExporterFile, File: path, ServiceName: "shortify-test", SampleRatio: 1.0}
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	shutdown, err := tracing.Init(context.Background(), cfg, "dev")
+	require.NoError(t, err)
+	_, span := tracing.Start(context.Background(), "service.Create")
+	tracing.End(span, nil)
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"service.Create"`)
+	assert.Contains(t, string(data), "shortify-test")
+}
+
+// TestInit_UnknownExporter is a test function that checks that an unknown exporter is rejected.
+func TestInit_UnknownExporter(t *testing.T) {
+	_, err := tracing.Init(context.Background(), config.Tracing{Exporter: "jaeger"}, "dev")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// Names of the SQL dialects that have their own migrations
+const (
+	Postgres = "postgres" // The migrations of the PostgreSQL storage
+	SQLite   = "sqlite"   // The migrations of the SQLite storage
+)
+
+// embedded holds the migrations compiled into the binary.
+// Every dialect has its own directory, and every migration is a directory like 001_initial_schema
+// holding an up.sql and a down.sql file.
+//
+//go:embed postgres/*/*.sql sqlite/*/*.sql
+var embedded embed.FS
+
+// Source is a function that returns the migrations of a dialect.
+// It takes an override directory and the name of the dialect: if the directory is set, the migrations are read
+// from its sub-directory for the dialect on disk, which is useful while developing a new migration;
+// otherwise the migrations embedded in the binary are used.
+func Source(dir, dialect string) fs.FS {
+	if dir != "" {
+		return os.DirFS(path.Join(dir, dialect))
+	}
+	sub, err := fs.Sub(embedded, dialect)
+	if err != nil {
+		// fs.Sub only fails for an invalid path, and dialect is one of the constants above
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,5 @@
+-- This statement removes the expiry and soft deletion columns together with their indexes.
+-- The links that were deleted softly become live again!
+ALTER TABLE urls
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS deleted_at;
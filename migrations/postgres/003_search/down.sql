@@ -0,0 +1,5 @@
+-- This statement removes the search indexes and the host column.
+-- The pg_trgm extension is kept, other schemas in the database may use it.
+DROP INDEX IF EXISTS urls_original_url_trgm_idx;
+DROP INDEX IF EXISTS urls_host_trgm_idx;
+ALTER TABLE urls DROP COLUMN IF EXISTS host;
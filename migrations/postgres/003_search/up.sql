@@ -0,0 +1,13 @@
+-- This migration makes the URLs searchable by any part of the original URL or of its host.
+-- The pg_trgm extension splits text into trigrams, so an index can serve substring searches and rank results by similarity.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 'host': The lower-cased host of the original URL, without the scheme, the user info and the port.
+-- It is generated from original_url, so it never gets out of sync with it.
+ALTER TABLE urls
+    ADD COLUMN IF NOT EXISTS host TEXT
+        GENERATED ALWAYS AS (lower(substring(original_url from '^[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^@/]*@)?([^:/?#]+)'))) STORED;
+
+-- Trigram indexes serve the ILIKE '%text%' filters and the similarity ranking of the searches.
+CREATE INDEX IF NOT EXISTS urls_original_url_trgm_idx ON urls USING GIN (original_url gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS urls_host_trgm_idx ON urls USING GIN (host gin_trgm_ops);
@@ -0,0 +1,5 @@
+-- This statement removes the owners of the links and the API keys.
+-- Every link becomes anonymous again!
+DROP INDEX IF EXISTS urls_owner_idx;
+ALTER TABLE urls DROP COLUMN IF EXISTS owner;
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,21 @@
+-- This migration adds the API keys and records which key created every link.
+-- 'api_keys': The keys that may create and manage links. Only the SHA-256 hash of a key is stored,
+-- the key itself is shown once when it is created.
+-- 'id': The identifier of the key, recorded as the owner of the links it creates.
+-- 'name': Who the key was issued to.
+-- 'key_hash': The hex-encoded SHA-256 hash of the key, unique so a key is found by its hash.
+-- 'created_at': The time when the key was created.
+-- 'revoked_at': The time when the key was revoked, null while it is active. Revoked keys are kept for the links they own.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(32) PRIMARY KEY, -- The identifier of the key
+    name TEXT NOT NULL, -- The holder of the key
+    key_hash CHAR(64) NOT NULL UNIQUE, -- The SHA-256 hash of the key
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(), -- The time when the key was created
+    revoked_at TIMESTAMPTZ -- The time when the key was revoked
+);
+
+-- 'owner': The ID of the key that created the link, empty for the links created before the keys or without one.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS owner VARCHAR(32) NOT NULL DEFAULT '';
+
+-- The index serves the deletions and the searches, which only see the links of the caller.
+CREATE INDEX IF NOT EXISTS urls_owner_idx ON urls (owner);
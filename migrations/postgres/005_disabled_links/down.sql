@@ -0,0 +1,3 @@
+-- This statement removes the disabled state of the links.
+-- The links that were disabled resolve again!
+ALTER TABLE urls DROP COLUMN IF EXISTS disabled_at;
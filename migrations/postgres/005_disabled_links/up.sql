@@ -0,0 +1,4 @@
+-- This migration lets an operator disable a link, so it stops resolving without being deleted.
+-- 'disabled_at': The time when the link was disabled, null while it resolves. A disabled link keeps its hash and its owner,
+-- so it can be enabled again.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ; -- The time when the URL was disabled
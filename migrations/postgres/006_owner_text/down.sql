@@ -0,0 +1,4 @@
+-- This statement gives the links of the API keys their bare key IDs back and limits the owner to 32 characters again.
+-- The owners of the links created with bearer tokens are cut to 32 characters and no longer match their tokens!
+UPDATE urls SET owner = substr(owner, 5) WHERE owner LIKE 'key:%';
+ALTER TABLE urls ALTER COLUMN owner TYPE VARCHAR(32) USING left(owner, 32);
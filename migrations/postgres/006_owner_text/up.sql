@@ -0,0 +1,6 @@
+-- This migration lets a link be owned by a bearer token subject, and tells the owners apart by where they come from.
+-- 'owner': The ID of the API key prefixed with 'key:', or the issuer and subject of the bearer token as 'jwt:<iss>|<sub>'.
+-- A subject has no length limit, so the column holds any text.
+-- The bare owners are API key IDs, which never hold a '|', while the owners holding one already belong to bearer tokens.
+ALTER TABLE urls ALTER COLUMN owner TYPE TEXT; -- The caller that created the URL, empty if created anonymously
+UPDATE urls SET owner = 'key:' || owner WHERE owner <> '' AND owner NOT LIKE 'key:%' AND owner NOT LIKE '%|%';
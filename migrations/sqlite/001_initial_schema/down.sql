@@ -0,0 +1,3 @@
+-- This statement drops the table named 'urls' if it exists.
+-- Dropping a table will result in loss of complete information stored in the table!
+DROP TABLE IF EXISTS urls;
\ No newline at end of file
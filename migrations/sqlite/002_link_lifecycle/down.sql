@@ -0,0 +1,6 @@
+-- This statement removes the expiry and soft deletion columns together with their indexes.
+-- The links that were deleted softly become live again!
+DROP INDEX IF EXISTS urls_expires_at_idx;
+DROP INDEX IF EXISTS urls_deleted_at_idx;
+ALTER TABLE urls DROP COLUMN expires_at;
+ALTER TABLE urls DROP COLUMN deleted_at;
@@ -0,0 +1,10 @@
+-- This migration lets links expire and be deleted softly, so the purge job can remove them later.
+-- 'expires_at': The time after which the link no longer resolves, null if it never expires.
+-- 'deleted_at': The time when the link was deleted, null while it is live.
+-- Both are written by the application in UTC, so they compare correctly as text.
+ALTER TABLE urls ADD COLUMN expires_at TIMESTAMP; -- The time when the URL expires
+ALTER TABLE urls ADD COLUMN deleted_at TIMESTAMP; -- The time when the URL was deleted
+
+-- Partial indexes keep the purge job from scanning the live links, which have neither column set.
+CREATE INDEX IF NOT EXISTS urls_expires_at_idx ON urls (expires_at) WHERE expires_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS urls_deleted_at_idx ON urls (deleted_at) WHERE deleted_at IS NOT NULL;
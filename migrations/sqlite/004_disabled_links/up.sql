@@ -0,0 +1,5 @@
+-- This migration lets an operator disable a link, so it stops resolving without being deleted.
+-- 'disabled_at': The time when the link was disabled, null while it resolves. A disabled link keeps its hash and its owner,
+-- so it can be enabled again.
+-- It is written by the application in UTC, like the other times.
+ALTER TABLE urls ADD COLUMN disabled_at TIMESTAMP; -- The time when the URL was disabled
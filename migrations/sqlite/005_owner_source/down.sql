@@ -0,0 +1,3 @@
+-- This statement gives the links of the API keys their bare key IDs back.
+-- The links created with bearer tokens keep their prefixed owners, which no bare subject matches!
+UPDATE urls SET owner = substr(owner, 5) WHERE owner LIKE 'key:%';
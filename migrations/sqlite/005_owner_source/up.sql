@@ -0,0 +1,4 @@
+-- This migration tells the owners of the links apart by where they come from.
+-- 'owner': The ID of the API key prefixed with 'key:', or the issuer and subject of the bearer token as 'jwt:<iss>|<sub>'.
+-- The bare owners are API key IDs, which never hold a '|', while the owners holding one already belong to bearer tokens.
+UPDATE urls SET owner = 'key:' || owner WHERE owner <> '' AND owner NOT LIKE 'key:%' AND owner NOT LIKE '%|%';
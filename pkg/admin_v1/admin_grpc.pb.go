@@ -0,0 +1,356 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v4.25.3
+// source: admin.proto
+
+package admin_v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminV1Client is the client API for AdminV1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminV1Client interface {
+	// PurgeCache is a remote procedure call (RPC) that takes a PurgeCacheRequest and returns a PurgeCacheResponse.
+	// The PurgeCacheRequest contains either the hash of a URL to evict from the caches of every instance,
+	// or a prefix of the hashes to evict from the shared cache and the local cache of this instance.
+	// The PurgeCacheResponse contains the number of URLs evicted by a prefix.
+	PurgeCache(ctx context.Context, in *PurgeCacheRequest, opts ...grpc.CallOption) (*PurgeCacheResponse, error)
+	// DisableLink is a remote procedure call (RPC) that takes a LinkRequest and returns an empty response.
+	// The LinkRequest contains the hash of the URL that stops resolving, without being deleted.
+	DisableLink(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// EnableLink is a remote procedure call (RPC) that takes a LinkRequest and returns an empty response.
+	// The LinkRequest contains the hash of a disabled URL that resolves again.
+	EnableLink(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetStats is a remote procedure call (RPC) that takes an empty request and returns a StatsResponse.
+	// The StatsResponse contains the runtime statistics of this instance.
+	GetStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatsResponse, error)
+	// GetConfig is a remote procedure call (RPC) that takes an empty request and returns a ConfigResponse.
+	// The ConfigResponse contains the configuration this instance runs with, the secrets redacted.
+	GetConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConfigResponse, error)
+	// GetLogLevel is a remote procedure call (RPC) that takes an empty request and returns a LogLevel.
+	// The LogLevel contains the lowest level this instance logs.
+	GetLogLevel(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LogLevel, error)
+	// SetLogLevel is a remote procedure call (RPC) that takes a LogLevel and returns a LogLevel.
+	// The LogLevel of the request contains the lowest level this instance logs from now on, until it is restarted:
+	// debug, info, warn or error. The LogLevel of the response contains the level it logged before.
+	SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error)
+}
+
+type adminV1Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminV1Client(cc grpc.ClientConnInterface) AdminV1Client {
+	return &adminV1Client{cc}
+}
+
+func (c *adminV1Client) PurgeCache(ctx context.Context, in *PurgeCacheRequest, opts ...grpc.CallOption) (*PurgeCacheResponse, error) {
+	out := new(PurgeCacheResponse)
+	err := c.cc.Invoke(ctx, "/admin_v1.AdminV1/PurgeCache", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminV1Client) DisableLink(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/admin_v1.AdminV1/DisableLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminV1Client) EnableLink(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/admin_v1.AdminV1/EnableLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminV1Client) GetStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/admin_v1.AdminV1/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminV1Client) GetConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConfigResponse, error) {
+	out := new(ConfigResponse)
+	err := c.cc.Invoke(ctx, "/admin_v1.AdminV1/GetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminV1Client) GetLogLevel(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LogLevel, error) {
+	out := new(LogLevel)
+	err := c.cc.Invoke(ctx, "/admin_v1.AdminV1/GetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminV1Client) SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error) {
+	out := new(LogLevel)
+	err := c.cc.Invoke(ctx, "/admin_v1.AdminV1/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminV1Server is the server API for AdminV1 service.
+// All implementations must embed UnimplementedAdminV1Server
+// for forward compatibility
+type AdminV1Server interface {
+	// PurgeCache is a remote procedure call (RPC) that takes a PurgeCacheRequest and returns a PurgeCacheResponse.
+	// The PurgeCacheRequest contains either the hash of a URL to evict from the caches of every instance,
+	// or a prefix of the hashes to evict from the shared cache and the local cache of this instance.
+	// The PurgeCacheResponse contains the number of URLs evicted by a prefix.
+	PurgeCache(context.Context, *PurgeCacheRequest) (*PurgeCacheResponse, error)
+	// DisableLink is a remote procedure call (RPC) that takes a LinkRequest and returns an empty response.
+	// The LinkRequest contains the hash of the URL that stops resolving, without being deleted.
+	DisableLink(context.Context, *LinkRequest) (*emptypb.Empty, error)
+	// EnableLink is a remote procedure call (RPC) that takes a LinkRequest and returns an empty response.
+	// The LinkRequest contains the hash of a disabled URL that resolves again.
+	EnableLink(context.Context, *LinkRequest) (*emptypb.Empty, error)
+	// GetStats is a remote procedure call (RPC) that takes an empty request and returns a StatsResponse.
+	// The StatsResponse contains the runtime statistics of this instance.
+	GetStats(context.Context, *emptypb.Empty) (*StatsResponse, error)
+	// GetConfig is a remote procedure call (RPC) that takes an empty request and returns a ConfigResponse.
+	// The ConfigResponse contains the configuration this instance runs with, the secrets redacted.
+	GetConfig(context.Context, *emptypb.Empty) (*ConfigResponse, error)
+	// GetLogLevel is a remote procedure call (RPC) that takes an empty request and returns a LogLevel.
+	// The LogLevel contains the lowest level this instance logs.
+	GetLogLevel(context.Context, *emptypb.Empty) (*LogLevel, error)
+	// SetLogLevel is a remote procedure call (RPC) that takes a LogLevel and returns a LogLevel.
+	// The LogLevel of the request contains the lowest level this instance logs from now on, until it is restarted:
+	// debug, info, warn or error. The LogLevel of the response contains the level it logged before.
+	SetLogLevel(context.Context, *LogLevel) (*LogLevel, error)
+	mustEmbedUnimplementedAdminV1Server()
+}
+
+// UnimplementedAdminV1Server must be embedded to have forward compatible implementations.
+type UnimplementedAdminV1Server struct {
+}
+
+func (UnimplementedAdminV1Server) PurgeCache(context.Context, *PurgeCacheRequest) (*PurgeCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeCache not implemented")
+}
+func (UnimplementedAdminV1Server) DisableLink(context.Context, *LinkRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableLink not implemented")
+}
+func (UnimplementedAdminV1Server) EnableLink(context.Context, *LinkRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableLink not implemented")
+}
+func (UnimplementedAdminV1Server) GetStats(context.Context, *emptypb.Empty) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedAdminV1Server) GetConfig(context.Context, *emptypb.Empty) (*ConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedAdminV1Server) GetLogLevel(context.Context, *emptypb.Empty) (*LogLevel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLevel not implemented")
+}
+func (UnimplementedAdminV1Server) SetLogLevel(context.Context, *LogLevel) (*LogLevel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedAdminV1Server) mustEmbedUnimplementedAdminV1Server() {}
+
+// UnsafeAdminV1Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminV1Server will
+// result in compilation errors.
+type UnsafeAdminV1Server interface {
+	mustEmbedUnimplementedAdminV1Server()
+}
+
+func RegisterAdminV1Server(s grpc.ServiceRegistrar, srv AdminV1Server) {
+	s.RegisterService(&AdminV1_ServiceDesc, srv)
+}
+
+func _AdminV1_PurgeCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).PurgeCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin_v1.AdminV1/PurgeCache",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).PurgeCache(ctx, req.(*PurgeCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminV1_DisableLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).DisableLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin_v1.AdminV1/DisableLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).DisableLink(ctx, req.(*LinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminV1_EnableLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).EnableLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin_v1.AdminV1/EnableLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).EnableLink(ctx, req.(*LinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminV1_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin_v1.AdminV1/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).GetStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminV1_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin_v1.AdminV1/GetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).GetConfig(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminV1_GetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).GetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin_v1.AdminV1/GetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).GetLogLevel(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminV1_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogLevel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin_v1.AdminV1/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).SetLogLevel(ctx, req.(*LogLevel))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminV1_ServiceDesc is the grpc.ServiceDesc for AdminV1 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminV1_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "admin_v1.AdminV1",
+	HandlerType: (*AdminV1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PurgeCache",
+			Handler:    _AdminV1_PurgeCache_Handler,
+		},
+		{
+			MethodName: "DisableLink",
+			Handler:    _AdminV1_DisableLink_Handler,
+		},
+		{
+			MethodName: "EnableLink",
+			Handler:    _AdminV1_EnableLink_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _AdminV1_GetStats_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _AdminV1_GetConfig_Handler,
+		},
+		{
+			MethodName: "GetLogLevel",
+			Handler:    _AdminV1_GetLogLevel_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _AdminV1_SetLogLevel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,192 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v4.25.3
+// source: apikey.proto
+
+package apikey_v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ApiKeyV1Client is the client API for ApiKeyV1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ApiKeyV1Client interface {
+	// Create is a remote procedure call (RPC) that takes a CreateRequest and returns a CreateResponse.
+	// The CreateRequest contains the name of the holder of the new key.
+	// The CreateResponse contains the key, which is shown only this once, and its description.
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	// List is a remote procedure call (RPC) that takes an empty request and returns a ListResponse.
+	// The ListResponse contains every key, the revoked ones included, the newest first, without the keys themselves.
+	List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListResponse, error)
+	// Revoke is a remote procedure call (RPC) that takes a RevokeRequest and returns an empty response.
+	// The RevokeRequest contains the ID of the key to revoke. The URLs the key created stay owned by it.
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type apiKeyV1Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewApiKeyV1Client(cc grpc.ClientConnInterface) ApiKeyV1Client {
+	return &apiKeyV1Client{cc}
+}
+
+func (c *apiKeyV1Client) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/apikey_v1.ApiKeyV1/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiKeyV1Client) List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/apikey_v1.ApiKeyV1/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiKeyV1Client) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/apikey_v1.ApiKeyV1/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApiKeyV1Server is the server API for ApiKeyV1 service.
+// All implementations must embed UnimplementedApiKeyV1Server
+// for forward compatibility
+type ApiKeyV1Server interface {
+	// Create is a remote procedure call (RPC) that takes a CreateRequest and returns a CreateResponse.
+	// The CreateRequest contains the name of the holder of the new key.
+	// The CreateResponse contains the key, which is shown only this once, and its description.
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	// List is a remote procedure call (RPC) that takes an empty request and returns a ListResponse.
+	// The ListResponse contains every key, the revoked ones included, the newest first, without the keys themselves.
+	List(context.Context, *emptypb.Empty) (*ListResponse, error)
+	// Revoke is a remote procedure call (RPC) that takes a RevokeRequest and returns an empty response.
+	// The RevokeRequest contains the ID of the key to revoke. The URLs the key created stay owned by it.
+	Revoke(context.Context, *RevokeRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedApiKeyV1Server()
+}
+
+// UnimplementedApiKeyV1Server must be embedded to have forward compatible implementations.
+type UnimplementedApiKeyV1Server struct {
+}
+
+func (UnimplementedApiKeyV1Server) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedApiKeyV1Server) List(context.Context, *emptypb.Empty) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedApiKeyV1Server) Revoke(context.Context, *RevokeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (UnimplementedApiKeyV1Server) mustEmbedUnimplementedApiKeyV1Server() {}
+
+// UnsafeApiKeyV1Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ApiKeyV1Server will
+// result in compilation errors.
+type UnsafeApiKeyV1Server interface {
+	mustEmbedUnimplementedApiKeyV1Server()
+}
+
+func RegisterApiKeyV1Server(s grpc.ServiceRegistrar, srv ApiKeyV1Server) {
+	s.RegisterService(&ApiKeyV1_ServiceDesc, srv)
+}
+
+func _ApiKeyV1_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiKeyV1Server).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apikey_v1.ApiKeyV1/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiKeyV1Server).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiKeyV1_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiKeyV1Server).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apikey_v1.ApiKeyV1/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiKeyV1Server).List(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiKeyV1_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiKeyV1Server).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apikey_v1.ApiKeyV1/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiKeyV1Server).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ApiKeyV1_ServiceDesc is the grpc.ServiceDesc for ApiKeyV1 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ApiKeyV1_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "apikey_v1.ApiKeyV1",
+	HandlerType: (*ApiKeyV1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ApiKeyV1_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _ApiKeyV1_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _ApiKeyV1_Revoke_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "apikey.proto",
+}
@@ -11,6 +11,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -22,8 +23,26 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type UrlV1Client interface {
+	// Get is a remote procedure call (RPC) that takes a GetRequest and returns a GetResponse.
+	// The GetRequest contains a hash string that represents the hashed version of the URL.
+	// The GetResponse contains the original URL.
+	// It needs no API key, so everyone can resolve the short URLs.
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	// Create is a remote procedure call (RPC) that takes a CreateRequest and returns a CreateResponse.
+	// The CreateRequest contains the original URL.
+	// The CreateResponse contains a short URL that represents the hashed version of the original URL.
+	// The URL is owned by the caller: the API key in the x-api-key metadata, recorded as key:<id>,
+	// or the subject of the bearer token in the authorization metadata, recorded as jwt:<iss>|<sub>.
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	// Delete is a remote procedure call (RPC) that takes a DeleteRequest and returns an empty response.
+	// The DeleteRequest contains a hash string that represents the hashed version of the URL to remove.
+	// Only the URLs owned by the caller, by its API key or bearer token, can be removed, the others are not found.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Search is a remote procedure call (RPC) that takes a SearchRequest and returns a SearchResponse.
+	// The SearchRequest contains a text to look for in the original URLs and their hosts, and the page to return.
+	// The SearchResponse contains the matching URLs, the most relevant first, and the token of the next page.
+	// Only the URLs owned by the caller, by its API key or bearer token, are searched.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
 }
 
 type urlV1Client struct {
@@ -52,12 +71,48 @@ func (c *urlV1Client) Create(ctx context.Context, in *CreateRequest, opts ...grp
 	return out, nil
 }
 
+func (c *urlV1Client) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/url_v1.UrlV1/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urlV1Client) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/url_v1.UrlV1/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UrlV1Server is the server API for UrlV1 service.
 // All implementations must embed UnimplementedUrlV1Server
 // for forward compatibility
 type UrlV1Server interface {
+	// Get is a remote procedure call (RPC) that takes a GetRequest and returns a GetResponse.
+	// The GetRequest contains a hash string that represents the hashed version of the URL.
+	// The GetResponse contains the original URL.
+	// It needs no API key, so everyone can resolve the short URLs.
 	Get(context.Context, *GetRequest) (*GetResponse, error)
+	// Create is a remote procedure call (RPC) that takes a CreateRequest and returns a CreateResponse.
+	// The CreateRequest contains the original URL.
+	// The CreateResponse contains a short URL that represents the hashed version of the original URL.
+	// The URL is owned by the caller: the API key in the x-api-key metadata, recorded as key:<id>,
+	// or the subject of the bearer token in the authorization metadata, recorded as jwt:<iss>|<sub>.
 	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	// Delete is a remote procedure call (RPC) that takes a DeleteRequest and returns an empty response.
+	// The DeleteRequest contains a hash string that represents the hashed version of the URL to remove.
+	// Only the URLs owned by the caller, by its API key or bearer token, can be removed, the others are not found.
+	Delete(context.Context, *DeleteRequest) (*emptypb.Empty, error)
+	// Search is a remote procedure call (RPC) that takes a SearchRequest and returns a SearchResponse.
+	// The SearchRequest contains a text to look for in the original URLs and their hosts, and the page to return.
+	// The SearchResponse contains the matching URLs, the most relevant first, and the token of the next page.
+	// Only the URLs owned by the caller, by its API key or bearer token, are searched.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
 	mustEmbedUnimplementedUrlV1Server()
 }
 
@@ -71,6 +126,12 @@ func (UnimplementedUrlV1Server) Get(context.Context, *GetRequest) (*GetResponse,
 func (UnimplementedUrlV1Server) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
+func (UnimplementedUrlV1Server) Delete(context.Context, *DeleteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedUrlV1Server) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
 func (UnimplementedUrlV1Server) mustEmbedUnimplementedUrlV1Server() {}
 
 // UnsafeUrlV1Server may be embedded to opt out of forward compatibility for this service.
@@ -120,6 +181,42 @@ func _UrlV1_Create_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UrlV1_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrlV1Server).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/url_v1.UrlV1/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrlV1Server).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrlV1_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrlV1Server).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/url_v1.UrlV1/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrlV1Server).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UrlV1_ServiceDesc is the grpc.ServiceDesc for UrlV1 service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -135,6 +232,14 @@ var UrlV1_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Create",
 			Handler:    _UrlV1_Create_Handler,
 		},
+		{
+			MethodName: "Delete",
+			Handler:    _UrlV1_Delete_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _UrlV1_Search_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "url.proto",